@@ -2,40 +2,58 @@ package main
 
 import (
 	"fmt"
-	"os"
+	"slices"
 	"strings"
 
+	"github.com/eduardofuncao/squix/internal/cli"
 	"github.com/eduardofuncao/squix/internal/config"
 	"github.com/eduardofuncao/squix/internal/db"
 	"github.com/eduardofuncao/squix/internal/editor"
 	"github.com/eduardofuncao/squix/internal/styles"
 )
 
-func (a *App) handleAdd() {
-	if len(os.Args) < 3 {
-		printError("Usage: squix add <run-name> [query]")
+func (a *App) handleAdd(args *cli.Args) {
+	global := args.Bool("global")
+	dialects, err := parseDialects(args.Strings("dialect"))
+	if err != nil {
+		printError("%v", err)
 	}
-
-	if a.config.CurrentConnection == "" {
-		printError("No active connection.  Use 'squix switch <connection>' or 'squix init' first")
+	if len(dialects) > 0 && !global {
+		printError("--dialect only applies to global queries, add --global")
 	}
 
-	_, ok := a.config.Connections[a.config.CurrentConnection]
-	if !ok {
-		a.config.Connections[a. config.CurrentConnection] = &config.ConnectionYAML{}
+	var queries map[string]db.Query
+	if global {
+		if a.config.Queries == nil {
+			a.config.Queries = make(map[string]db.Query)
+		}
+		queries = a.config.Queries
+	} else {
+		if a.connection == "" {
+			printError("No active connection.  Use 'squix switch <connection>' or 'squix init' first")
+		}
+
+		_, ok := a.config.Connections[a.connection]
+		if !ok {
+			a.config.Connections[a.connection] = &config.ConnectionYAML{}
+		}
+		queries = a.config.Connections[a.connection].Queries
 	}
-	queries := a.config.Connections[a.config.CurrentConnection].Queries
 
-	queryName := os.Args[2]
+	queryName := args.Arg(0)
 	var querySQL string
 
-	if len(os.Args) >= 4 {
-		querySQL = os.Args[3]
+	if len(args.Positionals) > 1 {
+		querySQL = args.Arg(1)
 	} else {
 		header := fmt.Sprintf("-- Creating new run:  %s\n", queryName)
-		header += fmt.Sprintf("-- Connection: %s (%s)\n",
-			a.config.CurrentConnection,
-			a.config.Connections[a.config.CurrentConnection].DBType)
+		if global {
+			header += "-- Global query, runs on any connection\n"
+		} else {
+			header += fmt.Sprintf("-- Connection: %s (%s)\n",
+				a.connection,
+				a.config.Connections[a.connection].DBType)
+		}
 		header += "-- Write your SQL run below and save\n\n"
 
 		editedContent, err := editor.EditTempFileWithTemplate(header, "squix-new-run-")
@@ -52,19 +70,47 @@ func (a *App) handleAdd() {
 	}
 
 	queries[queryName] = db.Query{
-		Name: queryName,
-		SQL:  querySQL,
-		Id:   db.GetNextQueryId(queries),
+		Name:        queryName,
+		SQL:         querySQL,
+		Id:          db.GetNextQueryId(queries),
+		Description: args.String("desc"),
+		Tags:        db.ParseTags(args.Strings("tag")...),
+		Folder:      db.CleanFolder(args.String("folder")),
+		Dialects:    dialects,
 	}
 
-	err := a.config.Save()
-	if err != nil {
+	if err := a.config.Save(); err != nil {
 		printError("Could not save configuration file: %v", err)
 	}
 
+	if global {
+		fmt.Println(styles.Success.Render(fmt.Sprintf("✓ Added global query '%s'", queryName)))
+		return
+	}
 	fmt.Println(styles.Success.Render(fmt.Sprintf("✓ Added query '%s' with ID %d", queryName, queries[queryName].Id)))
 }
 
+// parseDialects reads --dialect values, comma separated or repeated
+func parseDialects(values []string) ([]string, error) {
+	supported := db.GetSupportedDBTypes()
+	var dialects []string
+	for _, value := range values {
+		for _, dialect := range strings.Split(value, ",") {
+			dialect = db.CanonicalDBType(strings.TrimSpace(dialect))
+			if dialect == "" {
+				continue
+			}
+			if !slices.Contains(supported, dialect) {
+				return nil, fmt.Errorf("unknown dialect '%s', use one of: %s", dialect, strings.Join(supported, ", "))
+			}
+			if !slices.Contains(dialects, dialect) {
+				dialects = append(dialects, dialect)
+			}
+		}
+	}
+	return dialects, nil
+}
+
 func removeCommentLines(content string) string {
 	lines := strings.Split(content, "\n")
 	var result strings.Builder
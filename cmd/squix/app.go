@@ -5,6 +5,7 @@ import (
 	"log"
 	"os"
 
+	"github.com/eduardofuncao/squix/internal/cli"
 	"github.com/eduardofuncao/squix/internal/config"
 	"github.com/eduardofuncao/squix/internal/styles"
 )
@@ -13,64 +14,105 @@ const Version = "v0.3.0"
 
 type App struct {
 	config *config.Config
+
+	// Connection used by the command, the active one unless --connection or
+	// $SQUIX_CONNECTION names another
+	connection       string
+	connectionSource string
+
+	registry *cli.Registry
 }
 
-func NewApp(cfg *config.Config) *App {
-	return &App{
-		config: cfg,
-	}
+func NewApp() *App {
+	a := &App{}
+	a.registry = a.newRegistry()
+	return a
 }
 
-func (a *App) Run() {
-	if len(os.Args) < 2 {
-		a.printUsage()
-		os.Exit(1)
+func (a *App) Run(argv []string) {
+	args, parseErr := a.registry.Parse(argv)
+
+	if path := args.String("config"); path != "" {
+		config.CfgFile = path
 	}
+	cfg, err := config.LoadConfig(config.CfgFile)
+	if err != nil {
+		log.Fatal("Could not load config file", err)
+	}
+	a.config = cfg
 
-	if os.Args[1] == "-v" || os.Args[1] == "--version" {
+	// Initialize color scheme
+	if args.Bool("no-color") {
+		styles.DisableColors()
+	} else {
+		styles.InitScheme(cfg.ColorScheme, cfg.CustomColorScheme)
+	}
+
+	if len(argv) > 0 && (argv[0] == "-v" || argv[0] == "--version") {
 		a.printVersion()
 		os.Exit(0)
 	}
 
-	command := os.Args[1]
-	switch command {
-	case "init":
-		a.handleInit()
-	case "switch", "use":
-		a.handleSwitch()
-	case "add", "save":
-		a.handleAdd()
-	case "remove", "rm", "delete":
-		a.handleRemove()
-	case "query", "run":
-		a.handleRun()
-	case "list":
-		a.handleList()
-	case "ls":
-		a.handleListConnections()
-	case "edit":
-		a.handleEdit()
-	case "info":
-		a.handleInfo()
-	case "explore":
-		a.handleExplore()
-	case "status", "test":
-		a.handleStatus()
-	case "history":
-		a.handleHistory()
-	case "tables", "t":
-		a.handleTables()
-	case "disconnect", "clear", "unset":
-		a.handleEdit()
-	case "config":
-		a.handleConfig()
-	case "explain":
-		a.handleExplain()
-	case "help":
-		a.handleHelp()
-	default:
-		log.Fatalf("Unknown command: %s", command)
+	if parseErr != nil {
+		a.usageError(args.Command, parseErr)
+	}
+
+	if args.Bool("help") {
+		if args.Command == nil {
+			a.PrintGeneralHelp()
+		} else {
+			a.PrintCommandHelp(args.Command)
+		}
+		os.Exit(0)
+	}
+
+	if args.Command == nil {
+		a.printUsage()
+		os.Exit(1)
+	}
+
+	if err := args.CheckArgs(); err != nil {
+		a.usageError(args.Command, err)
+	}
+
+	a.resolveConnection(args)
+
+	args.Command.Run(args)
+}
+
+// resolveConnection picks the connection the command works on: --connection,
+// then $SQUIX_CONNECTION, then the active one. current_connection in the
+// config file is never changed by an override
+func (a *App) resolveConnection(args *cli.Args) {
+	a.connection = a.config.CurrentConnection
+
+	name, source := args.String("connection"), "--connection"
+	if name == "" {
+		name, source = os.Getenv(config.ConnectionEnv), "$"+config.ConnectionEnv
+	}
+	if name == "" {
+		return
+	}
+
+	if _, ok := a.config.Connections[name]; !ok {
+		printError("Connection '%s' from %s does not exist", name, source)
+	}
+	a.connection = name
+	a.connectionSource = source
+}
+
+// usageError reports a command line error with the command's usage
+func (a *App) usageError(cmd *cli.Command, err error) {
+	fmt.Fprintln(os.Stderr, styles.Error.Render("✗ Error:"), err)
+	if cmd != nil {
+		for _, usage := range cmd.Usage {
+			fmt.Fprintln(os.Stderr, styles.Faint.Render("  squix "+usage))
+		}
+		fmt.Fprintln(os.Stderr, styles.Faint.Render(fmt.Sprintf("Run 'squix help %s' for details", cmd.Name)))
+	} else {
+		fmt.Fprintln(os.Stderr, styles.Faint.Render("Run 'squix help' for the list of commands"))
 	}
+	os.Exit(1)
 }
 
 func (a *App) printUsage() {
@@ -136,10 +178,7 @@ func (a *App) printVersion() {
 	fmt.Println(styles.Faint.Render("version: " + Version))
 }
 
-func (a *App) handleListConnections() {
-	// Set os.Args to simulate "squix list connections"
-	originalArgs := os.Args
-	os.Args = []string{os.Args[0], "list", "connections"}
-	a.handleList()
-	os.Args = originalArgs
+func (a *App) handleListConnections(args *cli.Args) {
+	args.Positionals = []string{"connections"}
+	a.handleList(args)
 }
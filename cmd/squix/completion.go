@@ -0,0 +1,355 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/eduardofuncao/squix/internal/cli"
+	"github.com/eduardofuncao/squix/internal/config"
+	"github.com/eduardofuncao/squix/internal/db"
+	"github.com/eduardofuncao/squix/internal/params"
+)
+
+func (a *App) handleCompletion(args *cli.Args) {
+	switch args.Arg(0) {
+	case "bash":
+		fmt.Print(bashCompletion)
+	case "zsh":
+		fmt.Print(zshCompletion)
+	case "fish":
+		fmt.Print(fishCompletion)
+	default:
+		printError("Unknown shell: %s. Use bash, zsh or fish", args.Arg(0))
+	}
+}
+
+// handleComplete answers the completion scripts. It gets the words typed
+// after "squix", the last one being the word under the cursor, and prints
+// the candidates one per line
+func handleComplete() {
+	words := os.Args[2:]
+	if len(words) == 0 {
+		words = []string{""}
+	}
+	current := words[len(words)-1]
+	words = words[:len(words)-1]
+
+	a := NewApp()
+	a.config = completionConfig(words)
+	a.connection = a.config.CurrentConnection
+	if name := os.Getenv(config.ConnectionEnv); name != "" {
+		a.connection = name
+	}
+	if name := flagValue(words, "--connection", "-c"); name != "" {
+		a.connection = name
+	}
+
+	for _, candidate := range a.completionCandidates(words, current) {
+		if strings.HasPrefix(candidate, current) {
+			fmt.Println(candidate)
+		}
+	}
+}
+
+// completionConfig loads the config file, the one given with --config if
+// any, without creating a blank one just to complete a word
+func completionConfig(words []string) *config.Config {
+	if path := flagValue(words, "--config", ""); path != "" {
+		config.CfgFile = path
+	}
+	if _, err := os.Stat(config.CfgFile); err == nil {
+		if cfg, err := config.LoadConfig(config.CfgFile); err == nil {
+			return cfg
+		}
+	}
+	return &config.Config{}
+}
+
+func (a *App) completionCandidates(words []string, current string) []string {
+	// The command is the first word that is not a global flag or its value
+	var cmd *cli.Command
+	var args []string
+	for i := 0; i < len(words); i++ {
+		if flag, ok := a.findTypedFlag(nil, words[i]); ok {
+			if flag.Value != "" {
+				i++
+			}
+			continue
+		}
+		if c, ok := a.registry.Lookup(words[i]); ok {
+			cmd, args = c, words[i+1:]
+		}
+		break
+	}
+
+	// Value of the flag right before the cursor
+	if len(words) > 0 {
+		last := words[len(words)-1]
+		if flag, ok := a.findTypedFlag(cmd, last); ok && flag.Value != "" {
+			switch flag.Name {
+			case "format":
+				return []string{"csv", "json", "ndjson", "tsv", "markdown", "plain"}
+			case "type", "dialect":
+				return db.GetSupportedDBTypes()
+			case "connection":
+				return connectionNames(a.config)
+			case "tag", "folder":
+				return a.queryAttributes(flag.Name)
+			}
+			return nil
+		}
+	}
+
+	if strings.HasPrefix(current, "-") {
+		var flags []string
+		if cmd != nil {
+			for _, f := range cmd.Flags {
+				flags = append(flags, "--"+f.Name)
+			}
+		}
+		for _, f := range a.registry.Globals {
+			flags = append(flags, "--"+f.Name)
+		}
+		if cmd != nil && cmd.Params {
+			if positionals := a.positionalArgs(cmd, args); len(positionals) > 0 {
+				flags = append(flags, a.queryParamFlags(positionals[0], args)...)
+			}
+		}
+		return flags
+	}
+
+	if cmd == nil {
+		var names []string
+		for _, c := range a.registry.Commands() {
+			names = append(names, c.Name)
+		}
+		return names
+	}
+
+	if len(a.positionalArgs(cmd, args)) > 0 {
+		return nil
+	}
+
+	switch cmd.Name {
+	case "switch":
+		return connectionNames(a.config)
+	case "run":
+		return append(a.queryNames(), scriptFiles(current)...)
+	case "remove", "edit":
+		return a.queryNames()
+	case "tables", "explore", "explain":
+		return config.LoadTableCache(a.connection)
+	case "list":
+		return []string{"queries", "connections"}
+	case "info":
+		return []string{"tables", "views"}
+	case "vault":
+		return []string{"set", "rm", "list"}
+	case "completion":
+		return []string{"bash", "zsh", "fish"}
+	case "help":
+		var names []string
+		for _, c := range a.registry.Commands() {
+			names = append(names, c.Name)
+		}
+		return names
+	}
+	return nil
+}
+
+// findTypedFlag resolves a word typed on the command line to a flag
+func (a *App) findTypedFlag(cmd *cli.Command, word string) (cli.Flag, bool) {
+	if !strings.HasPrefix(word, "-") || strings.Contains(word, "=") {
+		return cli.Flag{}, false
+	}
+	long := strings.HasPrefix(word, "--")
+	return a.registry.FindFlag(cmd, strings.TrimLeft(word, "-"), long)
+}
+
+// positionalArgs drops flags and their values from the typed arguments
+func (a *App) positionalArgs(cmd *cli.Command, args []string) []string {
+	var positionals []string
+	for i := 0; i < len(args); i++ {
+		flag, known := a.findTypedFlag(cmd, args[i])
+		switch {
+		case known && flag.Value != "":
+			i++
+		case strings.HasPrefix(args[i], "-"):
+			// A query parameter like --name value
+			if !known && cmd.Params && !strings.Contains(args[i], "=") && i+1 < len(args) && !strings.HasPrefix(args[i+1], "--") {
+				i++
+			}
+		default:
+			positionals = append(positionals, args[i])
+		}
+	}
+	return positionals
+}
+
+// flagValue finds the value of a flag among the typed words
+func flagValue(words []string, long, short string) string {
+	for i, word := range words {
+		if value, ok := strings.CutPrefix(word, long+"="); ok {
+			return value
+		}
+		if (word == long || short != "" && word == short) && i+1 < len(words) {
+			return words[i+1]
+		}
+	}
+	return ""
+}
+
+func connectionNames(cfg *config.Config) []string {
+	names := make([]string, 0, len(cfg.Connections))
+	for name := range cfg.Connections {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// queryNames lists the saved queries of the connection by name, then by id
+func (a *App) queryNames() []string {
+	conn, ok := a.config.Connections[a.connection]
+	if !ok {
+		return nil
+	}
+
+	names := make([]string, 0, len(conn.Queries))
+	ids := make([]int, 0, len(conn.Queries))
+	for name, q := range conn.Queries {
+		names = append(names, name)
+		ids = append(ids, q.Id)
+	}
+	sort.Strings(names)
+	sort.Ints(ids)
+
+	for _, id := range ids {
+		names = append(names, strconv.Itoa(id))
+	}
+
+	// Global queries are run by name, the connection's come first
+	var global []string
+	for name := range a.config.Queries {
+		if _, ok := conn.Queries[name]; !ok {
+			global = append(global, name)
+		}
+	}
+	sort.Strings(global)
+	return append(names, global...)
+}
+
+// scriptFiles lists the .sql files and directories matching a path being
+// typed, for squix run <file.sql>
+func scriptFiles(current string) []string {
+	matches, _ := filepath.Glob(current + "*")
+	var files []string
+	for _, match := range matches {
+		if info, err := os.Stat(match); err == nil && info.IsDir() {
+			files = append(files, match+string(filepath.Separator))
+		} else if strings.EqualFold(filepath.Ext(match), ".sql") {
+			files = append(files, match)
+		}
+	}
+	return files
+}
+
+// queryAttributes lists the tags or folders used by the connection's queries
+func (a *App) queryAttributes(name string) []string {
+	conn, ok := a.config.Connections[a.connection]
+	if !ok {
+		return nil
+	}
+
+	seen := map[string]bool{}
+	var values []string
+	for _, q := range conn.Queries {
+		candidates := q.Tags
+		if name == "folder" {
+			candidates = []string{q.Folder}
+		}
+		for _, value := range candidates {
+			if value != "" && !seen[value] {
+				seen[value] = true
+				values = append(values, value)
+			}
+		}
+	}
+	sort.Strings(values)
+	return values
+}
+
+// queryParamFlags offers the :params of the chosen query as --param flags,
+// skipping the ones already given
+func (a *App) queryParamFlags(selector string, args []string) []string {
+	conn, ok := a.config.Connections[a.connection]
+	if !ok {
+		return nil
+	}
+	query, ok := db.FindQueryWithSelector(conn.Queries, selector)
+	if !ok {
+		if query, ok = a.config.Queries[selector]; !ok {
+			return nil
+		}
+		if variant, err := query.ForDialect(conn.DBType); err == nil {
+			query = variant
+		}
+	}
+
+	used := map[string]bool{}
+	for _, arg := range args {
+		used[arg] = true
+	}
+
+	var flags []string
+	for name := range params.ExtractParameters(query.SQL) {
+		if flag := "--" + name; !used[flag] {
+			flags = append(flags, flag)
+		}
+	}
+	sort.Strings(flags)
+	return flags
+}
+
+const bashCompletion = `# bash completion for squix
+# Load it with: source <(squix completion bash)
+
+_squix() {
+    local IFS=$'\n'
+    COMPREPLY=($(squix __complete "${COMP_WORDS[@]:1:COMP_CWORD}" 2>/dev/null))
+}
+
+complete -o default -F _squix squix
+`
+
+const zshCompletion = `#compdef squix
+# zsh completion for squix
+# Load it with: source <(squix completion zsh)
+
+_squix() {
+    local -a candidates
+    candidates=("${(@f)$(squix __complete "${(@)words[2,CURRENT]}" 2>/dev/null)}")
+    if [[ -n "${candidates[1]}" ]]; then
+        compadd -a candidates
+    else
+        _files
+    fi
+}
+
+compdef _squix squix
+`
+
+const fishCompletion = `# fish completion for squix
+# Load it with: squix completion fish | source
+
+function __squix_complete
+    set -l words (commandline -opc)[2..-1]
+    squix __complete $words (commandline -ct) 2>/dev/null
+end
+
+complete -c squix -f -a '(__squix_complete)'
+`
@@ -6,11 +6,12 @@ import (
 	"os"
 	"os/exec"
 
+	"github.com/eduardofuncao/squix/internal/cli"
 	"github.com/eduardofuncao/squix/internal/config"
 	"github.com/eduardofuncao/squix/internal/styles"
 )
 
-func (a *App) handleConfig() {
+func (a *App) handleConfig(args *cli.Args) {
 	editorCmd := os.Getenv("EDITOR")
 	if editorCmd == "" {
 		editorCmd = "vim"
@@ -3,10 +3,11 @@ package main
 import (
 	"fmt"
 
+	"github.com/eduardofuncao/squix/internal/cli"
 	"github.com/eduardofuncao/squix/internal/styles"
 )
 
-func (a *App) handleDisconnect() {
+func (a *App) handleDisconnect(args *cli.Args) {
 	if a.config.CurrentConnection == "" {
 		fmt.Println(styles.Faint.Render("No active connection"))
 		return
@@ -6,16 +6,18 @@ import (
 	"log"
 	"os"
 	"os/exec"
+	"sort"
 	"strings"
 
+	"github.com/eduardofuncao/squix/internal/cli"
 	"github.com/eduardofuncao/squix/internal/db"
 	"github.com/eduardofuncao/squix/internal/editor"
 	"github.com/eduardofuncao/squix/internal/styles"
 )
 
-func (a *App) handleEdit() {
-	if len(os.Args) >= 3 {
-		querySelector := os.Args[2]
+func (a *App) handleEdit(args *cli.Args) {
+	if len(args.Positionals) > 0 {
+		querySelector := args.Arg(0)
 		if querySelector == "config" {
 			printError("Config editing moved to 'squix config' command")
 			return
@@ -27,24 +29,25 @@ func (a *App) handleEdit() {
 }
 
 func (a *App) editSingleQuery(selector string) {
-	if a.config.CurrentConnection == "" {
+	if a.connection == "" {
 		log.Fatal("No active connection. Use 'squix switch <connection>' or 'squix init' first")
 	}
 
-	conn, ok := a.config.Connections[a.config.CurrentConnection]
+	conn, ok := a.config.Connections[a.connection]
 	if !ok {
-		log.Fatalf("Connection %s not found", a.config.CurrentConnection)
+		log.Fatalf("Connection %s not found", a.connection)
 	}
 
 	// Find the query
 	query, exists := db.FindQueryWithSelector(conn.Queries, selector)
 	if !exists {
-		log.Fatalf("Query '%s' not found in connection '%s'", selector, a.config.CurrentConnection)
+		log.Fatalf("Query '%s' not found in connection '%s'", selector, a.connection)
 	}
 
 	// Create temp file with the query SQL
 	var content strings.Builder
 	content.WriteString(fmt.Sprintf("-- %s\n", query.Name))
+	writeQueryAttributes(&content, query)
 	content.WriteString(query.SQL)
 
 	tmpFile, err := editor.CreateTempFile("squix-edit-query-", content.String())
@@ -74,10 +77,11 @@ func (a *App) editSingleQuery(selector string) {
 		log.Fatalf("Failed to read edited file: %v", err)
 	}
 
-	newName, newSQL, err := parseSingleQueryFile(editedData)
+	edited, err := parseSingleQueryFile(editedData)
 	if err != nil {
 		log.Fatalf("Failed to parse edited query: %v", err)
 	}
+	newName := edited.Name
 
 	if newName != query.Name {
 		if !a.confirmQueryRename(query.Name, newName) {
@@ -88,11 +92,18 @@ func (a *App) editSingleQuery(selector string) {
 		delete(conn.Queries, query.Name)
 	}
 
+	if path := a.config.QuerySource(a.connection, query.Name); path != "" {
+		fmt.Println(styles.Faint.Render(fmt.Sprintf("Query '%s' comes from %s, the edit is saved to your global config and overrides it", query.Name, relativePath(path))))
+	}
+
 	// Update query
 	query.Name = newName
-	query.SQL = newSQL
+	query.SQL = edited.SQL
+	query.Description = edited.Description
+	query.Tags = edited.Tags
+	query.Folder = edited.Folder
 	conn.Queries[query.Name] = query
-	a.config.Connections[a.config.CurrentConnection] = conn
+	a.config.Connections[a.connection] = conn
 
 	if err := a.config.Save(); err != nil {
 		log.Fatalf("Failed to save config: %v", err)
@@ -116,24 +127,32 @@ func (a *App) editQueries() {
 }
 
 func (a *App) editQueriesWithEditor(editorCmd string) {
-	if a.config.CurrentConnection == "" {
+	if a.connection == "" {
 		log.Fatal("No active connection. Use 'squix switch <connection>' or 'squix init' first")
 	}
 
-	conn, ok := a.config.Connections[a.config.CurrentConnection]
+	conn, ok := a.config.Connections[a.connection]
 	if !ok {
-		log.Fatalf("Connection %s not found", a.config.CurrentConnection)
+		log.Fatalf("Connection %s not found", a.connection)
 	}
 
 	var content strings.Builder
 	content.WriteString(fmt.Sprintf("-- Editing queries for connection: %s (%s)\n",
-		a.config.CurrentConnection, conn.DBType))
+		a.connection, conn.DBType))
 	content.WriteString("-- Format: -- runname\n")
 	content.WriteString("--         SQL run here\n")
+	content.WriteString("-- Format: optional '-- description:', '-- tags:' and '-- folder:' lines go right after the name\n")
 	content.WriteString("-- Save and close to update\n\n")
 
+	queries := make([]db.Query, 0, len(conn.Queries))
 	for _, query := range conn.Queries {
+		queries = append(queries, query)
+	}
+	sort.Slice(queries, func(i, j int) bool { return queries[i].Id < queries[j].Id })
+
+	for _, query := range queries {
 		content.WriteString(fmt.Sprintf("-- %s\n", query.Name))
+		writeQueryAttributes(&content, query)
 		content.WriteString(strings.TrimSpace(query.SQL))
 		content.WriteString("\n\n")
 	}
@@ -159,29 +178,31 @@ func (a *App) editQueriesWithEditor(editorCmd string) {
 		log.Fatalf("Failed to read edited file: %v", err)
 	}
 
-	editedQueries, err := parseSQLQueriesFile(editedData)
+	editedQueries, err := parseSQLQueriesFile(editedData, conn.Queries)
 	if err != nil {
 		log.Fatalf("Failed to parse edited queries: %v", err)
 	}
 
 	conn.Queries = editedQueries
-	a.config.Connections[a.config.CurrentConnection] = conn
+	a.config.Connections[a.connection] = conn
 
 	if err := a.config.Save(); err != nil {
 		log.Fatalf("Failed to save config: %v", err)
 	}
 
-	fmt.Printf("✓ Updated queries for connection: %s\n", a.config.CurrentConnection)
+	fmt.Printf("✓ Updated queries for connection: %s\n", a.connection)
 }
 
 // parseSingleQueryFile parses a file containing a single query
 // Expected format:
 //   -- queryname
+//   -- description: optional, like tags: and folder:
 //   SQL query here
-func parseSingleQueryFile(content string) (string, string, error) {
+func parseSingleQueryFile(content string) (db.Query, error) {
+	var query db.Query
 	lines := strings.Split(strings.TrimSpace(content), "\n")
 	if len(lines) == 0 {
-		return "", "", fmt.Errorf("empty file")
+		return query, fmt.Errorf("empty file")
 	}
 
 	// First non-empty line should be the query name comment
@@ -211,6 +232,12 @@ func parseSingleQueryFile(content string) (string, string, error) {
 			}
 		}
 
+		// Attributes come before the SQL
+		if comment, ok := strings.CutPrefix(trimmed, "--"); ok && len(sqlLines) == 0 {
+			parseQueryAttribute(strings.TrimSpace(comment), &query)
+			continue
+		}
+
 		// Rest is SQL
 		if foundName {
 			if !strings.HasPrefix(trimmed, "--") {
@@ -220,20 +247,56 @@ func parseSingleQueryFile(content string) (string, string, error) {
 	}
 
 	if queryName == "" {
-		return "", "", fmt.Errorf("query name not found (expected '-- queryname' on first line)")
+		return query, fmt.Errorf("query name not found (expected '-- queryname' on first line)")
 	}
+	query.Name = queryName
 
 	if len(sqlLines) == 0 {
-		return queryName, "", fmt.Errorf("no SQL content found")
+		return query, fmt.Errorf("no SQL content found")
 	}
 
-	sql := strings.Join(sqlLines, "\n")
-	return queryName, sql, nil
+	query.SQL = strings.Join(sqlLines, "\n")
+	return query, nil
+}
+
+// writeQueryAttributes adds the description, tags and folder of a query as
+// comment lines, read back by parseQueryAttribute
+func writeQueryAttributes(b *strings.Builder, query db.Query) {
+	if query.Description != "" {
+		b.WriteString(fmt.Sprintf("-- description: %s\n", query.Description))
+	}
+	if len(query.Tags) > 0 {
+		b.WriteString(fmt.Sprintf("-- tags: %s\n", strings.Join(query.Tags, ", ")))
+	}
+	if query.Folder != "" {
+		b.WriteString(fmt.Sprintf("-- folder: %s\n", query.Folder))
+	}
+}
+
+// parseQueryAttribute sets the attribute in a comment like "tags: a, b" on
+// the query, reporting whether the comment was one
+func parseQueryAttribute(comment string, query *db.Query) bool {
+	key, value, ok := strings.Cut(comment, ":")
+	if !ok {
+		return false
+	}
+	value = strings.TrimSpace(value)
+	switch strings.TrimSpace(key) {
+	case "description":
+		query.Description = value
+	case "tags":
+		query.Tags = db.ParseTags(value)
+	case "folder":
+		query.Folder = db.CleanFolder(value)
+	default:
+		return false
+	}
+	return true
 }
 
 func (a *App) confirmQueryRename(oldName, newName string) bool {
 	reader := bufio.NewReader(os.Stdin)
-	fmt.Printf(styles.Error.Render(fmt.Sprintf("Rename query '%s' → '%s'? [y/N]: ", oldName, newName)))
+	fmt.Print(styles.Error.Render(fmt.Sprintf("Rename query '%s' → '%s'? [y/N]: ", oldName, newName)))
 
 	response, err := reader.ReadString('\n')
 	if err != nil {
@@ -246,17 +309,29 @@ func (a *App) confirmQueryRename(oldName, newName string) bool {
 
 // parseSQLQueriesFile parses a SQL file with the format:
 // -- queryname
+// -- description: optional, like tags: and folder:
 // SQL query here
-func parseSQLQueriesFile(content string) (map[string]db.Query, error) {
+// Queries keep the id and metadata they had in existing, new ones are
+// numbered after them
+func parseSQLQueriesFile(content string, existing map[string]db.Query) (map[string]db.Query, error) {
 	queries := make(map[string]db.Query)
+	var names []string
 	var name string
+	var attributes db.Query
 	var sql strings.Builder
-	id := 1
 
 	save := func() {
 		if name != "" && sql.Len() > 0 {
-			queries[name] = db.Query{Name: name, SQL: strings.TrimSpace(sql.String()), Id: id}
-			id++
+			query, ok := existing[name]
+			if !ok {
+				query = db.Query{Name: name}
+			}
+			query.SQL = strings.TrimSpace(sql.String())
+			query.Description = attributes.Description
+			query.Tags = attributes.Tags
+			query.Folder = attributes.Folder
+			queries[name] = query
+			names = append(names, name)
 			sql.Reset()
 		}
 	}
@@ -274,8 +349,13 @@ func parseSQLQueriesFile(content string) (map[string]db.Query, error) {
 				continue
 			}
 
+			if name != "" && sql.Len() == 0 && parseQueryAttribute(comment, &attributes) {
+				continue
+			}
+
 			save()
 			name = comment
+			attributes = db.Query{}
 			continue
 		}
 
@@ -289,5 +369,15 @@ func parseSQLQueriesFile(content string) (map[string]db.Query, error) {
 	}
 
 	save()
+
+	// Number new queries in the order they were written
+	for _, name := range names {
+		query := queries[name]
+		if _, ok := existing[name]; ok {
+			continue
+		}
+		query.Id = db.GetNextQueryId(queries)
+		queries[name] = query
+	}
 	return queries, nil
 }
@@ -2,81 +2,48 @@ package main
 
 import (
 	"fmt"
-	"os"
-	"strconv"
 	"strings"
 
 	"github.com/charmbracelet/lipgloss"
+	"github.com/eduardofuncao/squix/internal/cli"
 	"github.com/eduardofuncao/squix/internal/config"
 	"github.com/eduardofuncao/squix/internal/db"
 	"github.com/eduardofuncao/squix/internal/styles"
 )
 
-type explainFlags struct {
-	depth   int
-	verbose bool
-}
-
-func parseExplainFlags() (explainFlags, []string) {
-	flags := explainFlags{
-		depth: 1, // Default to showing just direct relationships
-	}
-	remainingArgs := []string{}
-	args := os.Args[2:]
-
-	for i := 0; i < len(args); i++ {
-		arg := args[i]
-		if arg == "--depth" || arg == "-d" {
-			if i+1 < len(args) {
-				if depth, err := strconv.Atoi(args[i+1]); err == nil {
-					flags.depth = depth
-				}
-				i++ // Skip the next argument
-			}
-		} else if arg == "--verbose" || arg == "-v" {
-			flags.verbose = true
-		} else if !strings.HasPrefix(arg, "-") {
-			remainingArgs = append(remainingArgs, arg)
-		}
-	}
-
-	return flags, remainingArgs
-}
-
-func (a *App) handleExplain() {
-	if a.config.CurrentConnection == "" {
+func (a *App) handleExplain(args *cli.Args) {
+	if a.connection == "" {
 		printError(
 			"No active connection. Use 'squix switch <connection>' or 'squix init' first",
 		)
 	}
 
-	flags, args := parseExplainFlags()
-
-	if len(args) == 0 {
-		fmt.Println("Usage: squix explain [--depth|-d N] [--verbose|-v] <table-name>")
-		os.Exit(1)
+	// Default to showing just direct relationships
+	depth, err := args.Int("depth", 1)
+	if err != nil {
+		printError("%v", err)
 	}
 
 	conn := config.FromConnectionYaml(
-		a.config.Connections[a.config.CurrentConnection],
+		a.config.Connections[a.connection],
 	)
 
 	if err := conn.Open(); err != nil {
 		printError(
 			"Could not open connection to %s: %v",
-			a.config.CurrentConnection,
+			a.connection,
 			err,
 		)
 	}
 	defer conn.Close()
 
-	tableName := args[0]
+	tableName := args.Arg(0)
 
 	// Cache for FK lookups to improve performance
 	fkCache := make(map[string][]db.ForeignKey)
 	visited := make(map[string]bool)
 
-	tree := a.buildRelationshipTree(conn, tableName, flags.depth, 0, visited, fkCache, flags.verbose)
+	tree := a.buildRelationshipTree(conn, tableName, depth, 0, visited, fkCache, args.Bool("verbose"))
 
 	fmt.Println(tree)
 }
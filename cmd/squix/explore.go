@@ -2,47 +2,37 @@ package main
 
 import (
 	"fmt"
-	"os"
-	"strconv"
 	"strings"
 
+	"github.com/eduardofuncao/squix/internal/cli"
 	"github.com/eduardofuncao/squix/internal/config"
 	"github.com/eduardofuncao/squix/internal/db"
 	"github.com/eduardofuncao/squix/internal/run"
 	"github.com/eduardofuncao/squix/internal/styles"
 )
 
-func (a *App) handleExplore() {
-	if len(os.Args) < 3 {
+func (a *App) handleExplore(args *cli.Args) {
+	if len(args.Positionals) == 0 {
 		a.listTablesAndViews()
 		return
 	}
 
-	tableName := os.Args[2]
+	tableName := args.Arg(0)
 	limit := a.config.DefaultRowLimit
 	if limit == 0 {
 		limit = 1000
 	}
 
-	// Parse optional -l/--limit flag
-	for i := 3; i < len(os.Args); i++ {
-		if os.Args[i] == "-l" || os.Args[i] == "--limit" {
-			if i+1 < len(os.Args) {
-				parsed, err := strconv.Atoi(os.Args[i+1])
-				if err != nil {
-					printError("Invalid limit value: %s", os.Args[i+1])
-				}
-				limit = parsed
-			}
-			break
-		}
+	limit, err := args.Int("limit", limit)
+	if err != nil {
+		printError("%v", err)
 	}
 
-	if a.config.CurrentConnection == "" {
+	if a.connection == "" {
 		printError("No active connection. Use 'squix switch <connection>' or 'squix init' first")
 	}
 
-	conn := config.FromConnectionYaml(a.config.Connections[a.config.CurrentConnection])
+	conn := config.FromConnectionYaml(a.config.Connections[a.connection])
 
 	if err := conn.Open(); err != nil {
 		printError("Could not open connection: %v", err)
@@ -72,20 +62,20 @@ func (a *App) handleExplore() {
 }
 
 func (a *App) listTablesAndViews() {
-	if a.config.CurrentConnection == "" {
+	if a.connection == "" {
 		printError(
 			"No active connection. Use 'squix switch <connection>' or 'squix init' first",
 		)
 	}
 
 	conn := config.FromConnectionYaml(
-		a.config.Connections[a.config.CurrentConnection],
+		a.config.Connections[a.connection],
 	)
 
 	if err := conn.Open(); err != nil {
 		printError(
 			"Could not open connection to %s: %v",
-			a.config.CurrentConnection,
+			a.connection,
 			err,
 		)
 	}
@@ -100,6 +90,7 @@ func (a *App) listTablesAndViews() {
 	if err != nil {
 		printError("Could not list views: %v", err)
 	}
+	config.SaveTableCache(a.connection, append(append([]string{}, tables...), views...))
 
 	if len(tables) > 0 {
 		fmt.Printf("%s tables %s\n", styles.Title.Render("◆"), styles.Faint.Render(fmt.Sprintf("(%d)", len(tables))))
@@ -2,10 +2,158 @@ package main
 
 import (
 	"fmt"
+	"time"
 
+	"github.com/eduardofuncao/squix/internal/cli"
+	"github.com/eduardofuncao/squix/internal/config"
+	"github.com/eduardofuncao/squix/internal/db"
+	"github.com/eduardofuncao/squix/internal/editor"
+	"github.com/eduardofuncao/squix/internal/history"
+	"github.com/eduardofuncao/squix/internal/params"
+	"github.com/eduardofuncao/squix/internal/run"
 	"github.com/eduardofuncao/squix/internal/styles"
 )
 
-func (a *App) handleHistory() {
-	fmt.Println(styles. Faint.Render("To be implemented in future releases...  "))
+func historyFilter(args *cli.Args) history.Filter {
+	filter := history.Filter{
+		Connection: args.String("connection"),
+		FailedOnly: args.Bool("failed"),
+	}
+
+	if args.Has("since") {
+		since, err := history.ParseSince(args.String("since"), time.Now())
+		if err != nil {
+			printError("%v", err)
+		}
+		filter.Since = since
+	}
+
+	return filter
+}
+
+func (a *App) handleHistory(args *cli.Args) {
+	filter := historyFilter(args)
+
+	entries, err := history.Load()
+	if err != nil {
+		printError("Could not load history: %v", err)
+	}
+
+	var matching []history.Entry
+	for _, entry := range entries {
+		if filter.Match(entry) {
+			matching = append(matching, entry)
+		}
+	}
+
+	if len(matching) == 0 {
+		fmt.Println(styles.Faint.Render("No history entries found"))
+		return
+	}
+
+	selection, err := history.Browse(matching)
+	if err != nil {
+		printError("Error rendering history: %v", err)
+	}
+
+	entry := selection.Entry
+	switch selection.Action {
+	case history.ActionRun:
+		a.runHistoryEntry(entry, entry.SQL)
+	case history.ActionEdit:
+		editedSQL, err := editor.EditTempFile(entry.SQL, "squix-history-")
+		if err != nil {
+			printError("Error opening editor: %v", err)
+		}
+		if editedSQL == "" {
+			printError("Empty SQL, cancelled")
+		}
+		a.runHistoryEntry(entry, editedSQL)
+	case history.ActionSave:
+		a.saveHistoryEntry(entry, selection.Name)
+	}
+}
+
+func (a *App) historyConnection(entry history.Entry) db.DatabaseConnection {
+	connYAML, ok := a.config.Connections[entry.Connection]
+	if !ok {
+		printError("Connection '%s' does not exist", entry.Connection)
+	}
+	return config.FromConnectionYaml(connYAML)
+}
+
+func (a *App) runHistoryEntry(entry history.Entry, sql string) {
+	conn := a.historyConnection(entry)
+
+	name := entry.QueryName
+	if name == "" {
+		name = "<history>"
+	}
+
+	// Saving from the table view goes to the entry's connection, which may
+	// not be the current one
+	saveCallback := func(query db.Query) (db.Query, error) {
+		return a.saveQueryToConnection(entry.Connection, query)
+	}
+
+	var onRerun func(string) error
+	onRerun = func(editedSQL string) error {
+		finalSQL, args, displaySQL, values := a.historyParameters(entry, editedSQL, conn)
+		return run.Execute(run.ExecutionParams{
+			Query:        db.Query{Name: name, SQL: finalSQL, Id: -1},
+			Connection:   conn,
+			Config:       a.config,
+			SaveCallback: saveCallback,
+			Args:         args,
+			DisplaySQL:   displaySQL,
+			ParamSQL:     editedSQL,
+			ParamValues:  values,
+			OnRerun:      onRerun,
+		})
+	}
+
+	if err := onRerun(sql); err != nil {
+		printError("%v", err)
+	}
+}
+
+// historyParameters binds the parameters of sql, which may have been edited
+// since the entry ran. Stored values are reused only for the :name parameters
+// still present; the rest take their defaults or are prompted for. Entries
+// from before values were kept by name only replay their positional values
+// against the unchanged SQL
+func (a *App) historyParameters(entry history.Entry, sql string, conn db.DatabaseConnection) (string, []any, string, map[string]string) {
+	paramDefs := params.ExtractParameters(sql)
+	if len(paramDefs) > 0 {
+		known := map[string]string{}
+		for paramName := range paramDefs {
+			if value, ok := entry.Values[paramName]; ok {
+				known[paramName] = value
+			}
+		}
+		return a.processParameters(sql, conn, known, map[string]string{})
+	}
+
+	var args []any
+	if entry.Values == nil && sql == entry.SQL {
+		for _, param := range entry.Params {
+			args = append(args, param)
+		}
+	}
+	return sql, args, "", nil
+}
+
+func (a *App) saveHistoryEntry(entry history.Entry, name string) {
+	a.historyConnection(entry)
+
+	query, err := a.config.SaveQueryToConnection(entry.Connection, db.Query{
+		Name: name,
+		SQL:  entry.SQL,
+		Id:   -1,
+	})
+	if err != nil {
+		printError("Could not save query: %v", err)
+	}
+
+	fmt.Println(styles.Success.Render(fmt.Sprintf("✓ Saved query '%s' (id %d) to %s", query.Name, query.Id, entry.Connection)))
 }
@@ -2,29 +2,25 @@ package main
 
 import (
 	"fmt"
-	"os"
 
+	"github.com/eduardofuncao/squix/internal/cli"
 	"github.com/eduardofuncao/squix/internal/config"
 	"github.com/eduardofuncao/squix/internal/db"
 	"github.com/eduardofuncao/squix/internal/run"
 )
 
-func (a *App) handleInfo() {
-	if len(os.Args) < 3 {
-		printError("Usage: squix info <tables|views>")
-	}
-
-	infoType := os.Args[2]
+func (a *App) handleInfo(args *cli.Args) {
+	infoType := args.Arg(0)
 
 	if infoType != "tables" && infoType != "views" {
 		printError("Unknown info type: %s. Use 'tables' or 'views'", infoType)
 	}
 
-	if a.config.CurrentConnection == "" {
+	if a.connection == "" {
 		printError("No active connection. Use 'squix switch <connection>' or 'squix init' first")
 	}
 
-	conn := config.FromConnectionYaml(a.config.Connections[a.config.CurrentConnection])
+	conn := config.FromConnectionYaml(a.config.Connections[a.connection])
 
 	queryStr := conn.GetInfoSQL(infoType)
 	if queryStr == "" {
@@ -3,18 +3,16 @@ package main
 import (
 	"fmt"
 	"os"
-	"strings"
 
+	"github.com/eduardofuncao/squix/internal/cli"
 	"github.com/eduardofuncao/squix/internal/config"
 	"github.com/eduardofuncao/squix/internal/db"
 	"github.com/eduardofuncao/squix/internal/initui"
 	"github.com/eduardofuncao/squix/internal/styles"
 )
 
-func (a *App) handleInit() {
-	args := os.Args[1:]
-
-	name, dbType, connString, schema := parseInitArgs(args)
+func (a *App) handleInit(args *cli.Args) {
+	name, dbType, connString, schema, passwordCommand := parseInitArgs(args)
 
 	if dbType == "" && connString != "" {
 		dbType = db.InferDBType(connString)
@@ -48,7 +46,14 @@ func (a *App) handleInit() {
 		printError("Missing required parameters: name, db-type, and connection-string are required")
 	}
 
-	conn, err := db.CreateConnection(name, dbType, connString)
+	// Secret references stay as they are in the config file, they are only
+	// resolved to test the connection
+	resolvedConnString, err := config.ResolveConnString(connString, passwordCommand)
+	if err != nil {
+		printError("Could not resolve credentials: %v", err)
+	}
+
+	conn, err := db.CreateConnection(name, dbType, resolvedConnString)
 	if err != nil {
 		printError("Could not create connection interface: %s/%s, %s", dbType, name, err)
 	}
@@ -70,7 +75,10 @@ func (a *App) handleInit() {
 	}
 
 	a.config.CurrentConnection = conn.GetName()
-	a.config.Connections[a.config.CurrentConnection] = config.ToConnectionYAML(conn)
+	connYAML := config.ToConnectionYAML(conn)
+	connYAML.ConnString = connString
+	connYAML.PasswordCommand = passwordCommand
+	a.config.Connections[a.config.CurrentConnection] = connYAML
 	err = a.config.Save()
 	if err != nil {
 		printError("Could not save configuration file: %v", err)
@@ -83,66 +91,37 @@ func (a *App) handleInit() {
 	fmt.Println(styles.Success.Render("✓ Connection created: "), styles.Title.Render(fmt.Sprintf("%s/%s%s", conn.GetDbType(), conn.GetName(), schemaInfo)))
 }
 
-// parseInitArgs parses flags and positional args
-// Returns: name, dbType, connString, schema
-func parseInitArgs(args []string) (string, string, string, string) {
+// parseInitArgs reads the positional form, then the flags, which take
+// precedence
+// Returns: name, dbType, connString, schema, passwordCommand
+func parseInitArgs(args *cli.Args) (string, string, string, string, string) {
 	var name, dbType, connString, schema string
 
-	// Check for flags
-	hasFlags := false
-	for _, arg := range args {
-		if strings.HasPrefix(arg, "--") || strings.HasPrefix(arg, "-") {
-			hasFlags = true
-			break
-		}
+	// Positional mode:
+	// 2-arg: name conn-string (type inferred)
+	// 3-arg: name type conn-string [schema]
+	switch positionals := args.Positionals; {
+	case len(positionals) == 2:
+		name, connString = positionals[0], positionals[1]
+	case len(positionals) >= 3:
+		name, dbType, connString = positionals[0], positionals[1], positionals[2]
+		schema = args.Arg(3)
 	}
 
-	if hasFlags {
-		// Parse flag mode
-		for i := 0; i < len(args); i++ {
-			arg := args[i]
-
-			switch {
-			case arg == "--name" || arg == "-n":
-				if i+1 < len(args) && !strings.HasPrefix(args[i+1], "-") {
-					name = args[i+1]
-					i++
-				}
-			case arg == "--type" || arg == "-t":
-				if i+1 < len(args) && !strings.HasPrefix(args[i+1], "-") {
-					dbType = args[i+1]
-					i++
-				}
-			case arg == "--conn-string" || arg == "--conn" || arg == "-c":
-				if i+1 < len(args) && !strings.HasPrefix(args[i+1], "-") {
-					connString = args[i+1]
-					i++
-				}
-			case arg == "--schema" || arg == "-s":
-				if i+1 < len(args) && !strings.HasPrefix(args[i+1], "-") {
-					schema = args[i+1]
-					i++
-				}
-			}
-		}
-	} else {
-		// Positional mode:
-		// 2-arg: name conn-string (type inferred)
-		// 3-arg: name type conn-string [schema]
-		if len(args) == 3 {
-			// 2-arg mode: init name conn-string
-			name = args[1]
-			connString = args[2]
-		} else if len(args) >= 4 {
-			// 3-arg mode (legacy): init name type conn-string [schema]
-			name = args[1]
-			dbType = args[2]
-			connString = args[3]
-			if len(args) >= 5 {
-				schema = args[4]
-			}
+	flags := []struct {
+		name  string
+		value *string
+	}{
+		{"name", &name},
+		{"type", &dbType},
+		{"conn-string", &connString},
+		{"schema", &schema},
+	}
+	for _, f := range flags {
+		if args.Has(f.name) {
+			*f.value = args.String(f.name)
 		}
 	}
 
-	return name, dbType, connString, schema
+	return name, dbType, connString, schema, args.String("password-command")
 }
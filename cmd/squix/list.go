@@ -2,10 +2,10 @@ package main
 
 import (
 	"fmt"
-	"os"
 	"sort"
 	"strings"
 
+	"github.com/eduardofuncao/squix/internal/cli"
 	"github.com/eduardofuncao/squix/internal/db"
 	"github.com/eduardofuncao/squix/internal/parser"
 	"github.com/eduardofuncao/squix/internal/styles"
@@ -13,40 +13,32 @@ import (
 
 type listFlags struct {
 	oneline    bool
+	tree       bool
+	global     bool
 	searchTerm string
+	tags       []string
+	folder     string
 }
 
-func parseListFlags() (listFlags, []string) {
-	flags := listFlags{}
-	remainingArgs := []string{}
-	args := os.Args[2:]
-
-	for _, arg := range args {
-		if arg == "--oneline" || arg == "-o" {
-			flags.oneline = true
-		} else if !strings.HasPrefix(arg, "-") {
-			remainingArgs = append(remainingArgs, arg)
-		}
+func (a *App) handleList(args *cli.Args) {
+	flags := listFlags{
+		oneline: args.Bool("oneline"),
+		tree:    args.Bool("tree"),
+		global:  args.Bool("global"),
+		tags:    db.ParseTags(args.Strings("tag")...),
+		folder:  db.CleanFolder(args.String("folder")),
 	}
 
-	return flags, remainingArgs
-}
-
-func (a *App) handleList() {
-	flags, args := parseListFlags()
-
 	var objectType string
-	if len(args) == 0 {
+	if len(args.Positionals) == 0 {
 		objectType = "queries" // Default to queries
-	} else if args[0] == "queries" || args[0] == "connections" {
-		objectType = args[0]
-		if len(args) > 1 {
-			flags.searchTerm = args[1]
-		}
+	} else if args.Arg(0) == "queries" || args.Arg(0) == "connections" {
+		objectType = args.Arg(0)
+		flags.searchTerm = args.Arg(1)
 	} else {
 		// First arg is search term, default to queries
 		objectType = "queries"
-		flags.searchTerm = args[0]
+		flags.searchTerm = args.Arg(0)
 	}
 
 	switch objectType {
@@ -57,109 +49,298 @@ func (a *App) handleList() {
 		}
 		for name, connection := range a.config.Connections {
 			marker := "◆"
-			if name == a.config.CurrentConnection {
+			if name == a.connection {
 				marker = styles.Success.Render("●") // Active connection
 			} else {
 				marker = styles.Faint.Render("◆")
 			}
-			fmt.Printf("%s %s %s\n", marker, styles.Title.Render(name), styles.Faint.Render(fmt.Sprintf("(%s)", connection.DBType)))
+			source := ""
+			if path := a.config.ConnectionSource(name); path != "" {
+				source = " " + styles.Faint.Render("["+relativePath(path)+"]")
+			}
+			fmt.Printf("%s %s %s%s\n", marker, styles.Title.Render(name), styles.Faint.Render(fmt.Sprintf("(%s)", connection.DBType)), source)
 		}
 
 	case "queries":
-		if a.config.CurrentConnection == "" {
-			printError("No active connection.  Use 'squix switch <connection>' or 'squix init' first")
+		var queryList []db.Query
+		if !flags.global {
+			if a.connection == "" {
+				printError("No active connection.  Use 'squix switch <connection>' or 'squix init' first")
+			}
+			queryList = flags.filter(a.config.Connections[a.connection].Queries)
 		}
-		conn := a.config.Connections[a.config.CurrentConnection]
-		if len(conn.Queries) == 0 {
-			fmt.Println(styles.Faint.Render("No queries saved"))
+		globalList := flags.filter(a.config.Queries)
+
+		if len(queryList) == 0 && len(globalList) == 0 {
+			switch {
+			case flags.searchTerm != "":
+				fmt.Printf(styles.Faint.Render("No queries found matching '%s'\n"), flags.searchTerm)
+			case len(flags.tags) > 0 || flags.folder != "":
+				fmt.Println(styles.Faint.Render("No queries found"))
+			default:
+				fmt.Println(styles.Faint.Render("No queries saved"))
+			}
 			return
 		}
 
-		// Get sorted list of queries
-		queryList := make([]db.Query, 0, len(conn.Queries))
-		for _, query := range conn.Queries {
-			// If no search term, include all queries
-			if flags.searchTerm == "" {
-				queryList = append(queryList, query)
-				continue
+		a.displayQueries(queryList, flags, false)
+		if len(globalList) > 0 {
+			if len(queryList) > 0 {
+				fmt.Println()
 			}
+			fmt.Println(styles.Title.Render("Global queries"))
+			a.displayQueries(globalList, flags, true)
+		}
+
+	default:
+		printError("Unknown list type: %s.  Use 'queries' or 'connections'", objectType)
+	}
+}
 
-			searchLower := strings.ToLower(flags.searchTerm)
-			nameMatch := strings.Contains(strings.ToLower(query.Name), searchLower)
-			sqlMatch := strings.Contains(strings.ToLower(query.SQL), searchLower)
+func (a *App) displayQueriesOneline(queries []db.Query, global bool) {
+	for _, query := range queries {
+		tableName := db.ExtractTableNameFromSQL(query.SQL)
+		hasJoin := db.HasJoinClause(query.SQL)
 
-			if nameMatch || sqlMatch {
-				queryList = append(queryList, query)
-			}
+		tableDisplay := tableName
+		if hasJoin && tableName != "" {
+			tableDisplay = tableName + " <join>"
+		} else if tableName == "" {
+			tableDisplay = "<unknown>"
 		}
 
-		sort.Slice(queryList, func(i, j int) bool {
-			return queryList[i].Id < queryList[j].Id
-		})
+		fmt.Printf("%s %s %s%s%s\n",
+			styles.Faint.Render(queryIdLabel(query, global)),
+			styles.Title.Render(query.Name),
+			tableDisplay,
+			queryLabels(query),
+			a.querySourceLabel(query.Name, global),
+		)
+	}
+}
 
-		if flags.searchTerm != "" && len(queryList) == 0 {
-			fmt.Printf(styles.Faint.Render("No queries found matching '%s'\n"), flags.searchTerm)
-			return
+// filter returns the queries passing the filters and search term, by id
+func (f listFlags) filter(queries map[string]db.Query) []db.Query {
+	queryList := make([]db.Query, 0, len(queries))
+	for _, query := range queries {
+		if !f.matches(query) {
+			continue
 		}
 
-		// Display in oneline format if flag is set
-		if flags.oneline {
-			displayQueriesOneline(queryList)
-			return
+		// If no search term, include all queries
+		if f.searchTerm == "" {
+			queryList = append(queryList, query)
+			continue
 		}
 
-		for _, query := range queryList {
-			displayName := query.Name
-			if flags.searchTerm != "" {
-				displayName = highlightMatches(query.Name, flags.searchTerm)
-			}
+		searchLower := strings.ToLower(f.searchTerm)
+		nameMatch := strings.Contains(strings.ToLower(query.Name), searchLower)
+		sqlMatch := strings.Contains(strings.ToLower(query.SQL), searchLower)
+		descMatch := strings.Contains(strings.ToLower(query.Description), searchLower)
 
-			// Extract table name
-			tableName := db.ExtractTableNameFromSQL(query.SQL)
-			if tableName == "" {
-				tableName = "<unknown>"
-			}
-			if db.HasJoinClause(query.SQL) {
-				tableName = tableName + " <join>"
-			}
+		if nameMatch || sqlMatch || descMatch {
+			queryList = append(queryList, query)
+		}
+	}
+
+	sort.Slice(queryList, func(i, j int) bool {
+		return queryList[i].Id < queryList[j].Id
+	})
+	return queryList
+}
+
+func (a *App) displayQueries(queryList []db.Query, flags listFlags, global bool) {
+	if flags.tree {
+		a.displayQueriesTree(queryList, global)
+		return
+	}
+
+	// Display in oneline format if flag is set
+	if flags.oneline {
+		a.displayQueriesOneline(queryList, global)
+		return
+	}
+
+	for _, query := range queryList {
+		displayName := query.Name
+		if flags.searchTerm != "" {
+			displayName = highlightMatches(query.Name, flags.searchTerm)
+		}
+
+		// Extract table name
+		tableName := db.ExtractTableNameFromSQL(query.SQL)
+		if tableName == "" {
+			tableName = "<unknown>"
+		}
+		if db.HasJoinClause(query.SQL) {
+			tableName = tableName + " <join>"
+		}
 
-			formatedItem := fmt.Sprintf("◆ %d/%s (%s)", query.Id, displayName, tableName)
-			fmt.Println(styles.Title.Render(formatedItem))
+		formatedItem := fmt.Sprintf("◆ %d/%s (%s)", query.Id, displayName, tableName)
+		if global {
+			formatedItem = fmt.Sprintf("◆ %s (%s)", displayName, tableName)
+		}
+		fmt.Println(styles.Title.Render(formatedItem) + queryLabels(query) + a.querySourceLabel(query.Name, global))
+		if query.Description != "" {
+			description := query.Description
+			if flags.searchTerm != "" {
+				description = highlightMatches(description, flags.searchTerm)
+			}
+			fmt.Println(styles.Faint.Render(description))
+		}
 
+		if query.SQL != "" {
 			displaySQL := query.SQL
 			if flags.searchTerm != "" {
 				displaySQL = highlightMatches(query.SQL, flags.searchTerm)
 			}
 			fmt.Print(parser.HighlightSQL(parser.FormatSQLWithLineBreaks(displaySQL)))
 			fmt.Println()
+		}
+		for _, dialect := range sortedKeys(query.Variants) {
+			fmt.Println(styles.Faint.Render("-- " + dialect))
+			fmt.Print(parser.HighlightSQL(parser.FormatSQLWithLineBreaks(query.Variants[dialect])))
 			fmt.Println()
 		}
+		fmt.Println()
+	}
+}
 
-	default:
-		printError("Unknown list type: %s.  Use 'queries' or 'connections'", objectType)
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// matches applies the --tag and --folder filters, a query needs every tag
+func (f listFlags) matches(query db.Query) bool {
+	for _, tag := range f.tags {
+		if !query.HasTag(tag) {
+			return false
+		}
+	}
+	return query.InFolder(f.folder)
+}
+
+// queryLabels renders the folder and tags of a query
+func queryLabels(query db.Query) string {
+	var labels []string
+	if query.Folder != "" {
+		labels = append(labels, query.Folder+"/")
+	}
+	for _, tag := range query.Tags {
+		labels = append(labels, "#"+tag)
+	}
+	if len(query.Dialects) > 0 {
+		labels = append(labels, "only "+strings.Join(query.Dialects, ", "))
+	}
+	if len(query.Variants) > 0 {
+		labels = append(labels, "variants: "+strings.Join(sortedKeys(query.Variants), ", "))
+	}
+	if len(labels) == 0 {
+		return ""
+	}
+	return " " + styles.Faint.Render(strings.Join(labels, " "))
+}
+
+// folderNode is a folder of the query tree
+type folderNode struct {
+	name    string
+	folders []*folderNode
+	queries []db.Query
+}
+
+func (n *folderNode) folder(name string) *folderNode {
+	for _, f := range n.folders {
+		if f.name == name {
+			return f
+		}
 	}
+	f := &folderNode{name: name}
+	n.folders = append(n.folders, f)
+	return f
 }
 
-func displayQueriesOneline(queries []db.Query) {
+// displayQueriesTree lists the queries grouped by folder, folders first
+func (a *App) displayQueriesTree(queries []db.Query, global bool) {
+	root := &folderNode{}
 	for _, query := range queries {
-		tableName := db.ExtractTableNameFromSQL(query.SQL)
-		hasJoin := db.HasJoinClause(query.SQL)
+		node := root
+		if query.Folder != "" {
+			for _, part := range strings.Split(query.Folder, "/") {
+				node = node.folder(part)
+			}
+		}
+		node.queries = append(node.queries, query)
+	}
+	a.printFolderNode(root, "", global)
+}
 
-		tableDisplay := tableName
-		if hasJoin && tableName != "" {
-			tableDisplay = tableName + " <join>"
-		} else if tableName == "" {
-			tableDisplay = "<unknown>"
+func (a *App) printFolderNode(node *folderNode, indent string, global bool) {
+	sort.Slice(node.folders, func(i, j int) bool {
+		return node.folders[i].name < node.folders[j].name
+	})
+
+	total := len(node.folders) + len(node.queries)
+	item := 0
+	branch := func() (string, string) {
+		item++
+		if indent == "" && node.name == "" {
+			return "", ""
+		}
+		if item == total {
+			return "└── ", "    "
 		}
+		return "├── ", "│   "
+	}
 
-		fmt.Printf("%s %s %s\n",
-			styles.Faint.Render(fmt.Sprintf("%d", query.Id)),
-			styles.Title.Render(query.Name),
-			tableDisplay,
+	for _, f := range node.folders {
+		prefix, childIndent := branch()
+		fmt.Println(styles.Faint.Render(indent+prefix) + styles.Title.Render(f.name+"/"))
+		a.printFolderNode(f, indent+childIndent, global)
+	}
+	for _, query := range node.queries {
+		prefix, _ := branch()
+		tags := query
+		tags.Folder = ""
+		fmt.Printf("%s%s %s%s%s\n",
+			styles.Faint.Render(indent+prefix),
+			styles.Faint.Render(queryIdLabel(query, global)),
+			query.Name,
+			queryLabels(tags),
+			a.querySourceLabel(query.Name, global),
 		)
 	}
 }
 
+// querySourceLabel tells project queries from the ones in the global config,
+// only when a project file is in use
+func (a *App) querySourceLabel(queryName string, global bool) string {
+	if a.config.Project() == nil {
+		return ""
+	}
+	path := a.config.QuerySource(a.connection, queryName)
+	if global {
+		path = a.config.GlobalQuerySource(queryName)
+	}
+	source := "config"
+	if path != "" {
+		source = relativePath(path)
+	}
+	return " " + styles.Faint.Render("["+source+"]")
+}
+
+// queryIdLabel is the id of a query, global ones are run by name
+func queryIdLabel(query db.Query, global bool) string {
+	if global {
+		return "*"
+	}
+	return fmt.Sprintf("%d", query.Id)
+}
+
 func highlightMatches(text, searchTerm string) string {
 	if searchTerm == "" {
 		return text
@@ -1,21 +1,16 @@
 package main
 
 import (
-	"log"
-
-	"github.com/eduardofuncao/squix/internal/config"
-	"github.com/eduardofuncao/squix/internal/styles"
+	"os"
 )
 
 func main() {
-	cfg, err := config.LoadConfig(config.CfgFile)
-	if err != nil {
-		log.Fatal("Could not load config file", err)
+	// Called by the shell completion scripts, loads the config by itself
+	if len(os.Args) > 1 && os.Args[1] == "__complete" {
+		handleComplete()
+		return
 	}
 
-	// Initialize color scheme
-	styles.InitScheme(cfg.ColorScheme, cfg.CustomColorScheme)
-
-	app := NewApp(cfg)
-	app.Run()
+	app := NewApp()
+	app.Run(os.Args[1:])
 }
@@ -6,58 +6,74 @@ import (
 	"os"
 	"strings"
 
+	"github.com/eduardofuncao/squix/internal/cli"
 	"github.com/eduardofuncao/squix/internal/db"
 	"github.com/eduardofuncao/squix/internal/styles"
 )
 
-func (a *App) handleRemove() {
-	if len(os.Args) < 3 {
-		printError("Usage: squix remove <run-name> [--connection|-c <conn-name>]")
+func (a *App) handleRemove(args *cli.Args) {
+	if args.Bool("global") {
+		a.removeGlobalQuery(args)
+		return
 	}
 
-	// Parse flags
-	var connectionName string
-	args := os.Args[2:]
-	for i, arg := range args {
-		if arg == "--connection" || arg == "-c" {
-			if i+1 < len(args) {
-				connectionName = args[i+1]
-			}
-			break
+	// Without a query, --connection names the connection to remove
+	if len(args.Positionals) == 0 {
+		if !args.Has("connection") {
+			a.usageError(args.Command, fmt.Errorf("missing query or --connection"))
 		}
-	}
-
-	// If --connection flag was used, remove connection
-	if connectionName != "" {
-		a.removeConnection(connectionName)
+		a.removeConnection(a.connection)
 		return
 	}
 
-	// Otherwise, remove query (original behavior)
-	conn := a.config.Connections[a.config.CurrentConnection]
+	if a.connection == "" {
+		printError("No active connection. Use 'squix switch <connection>' or 'squix init' first")
+	}
+	conn := a.config.Connections[a.connection]
 	queries := conn.Queries
 
-	query, exists := db.FindQueryWithSelector(queries, os.Args[2])
+	query, exists := db.FindQueryWithSelector(queries, args.Arg(0))
 	if !exists {
-		printError("Query '%s' could not be found", os.Args[2])
+		printError("Query '%s' could not be found", args.Arg(0))
+	}
+	if path := a.config.QuerySource(a.connection, query.Name); path != "" {
+		printError("Query '%s' is defined in %s, remove it there", query.Name, relativePath(path))
 	}
 
-	delete(conn.Queries, query.Name)
+	a.deleteQuery(a.connection, query.Name)
 
-	err := a.config.Save()
-	if err != nil {
+	fmt.Println(styles.Success.Render(fmt.Sprintf("✓ Removed run '%s'", query.Name)))
+}
+
+func (a *App) removeGlobalQuery(args *cli.Args) {
+	if len(args.Positionals) == 0 {
+		a.usageError(args.Command, fmt.Errorf("missing query"))
+	}
+	name := args.Arg(0)
+	if _, exists := a.config.Queries[name]; !exists {
+		printError("Global query '%s' could not be found", name)
+	}
+	if path := a.config.GlobalQuerySource(name); path != "" {
+		printError("Query '%s' is defined in %s, remove it there", name, relativePath(path))
+	}
+
+	delete(a.config.Queries, name)
+	if err := a.config.Save(); err != nil {
 		printError("Could not save configuration file: %v", err)
 	}
 
-	fmt.Println(styles.Success.Render(fmt.Sprintf("✓ Removed run '%s'", query.Name)))
+	fmt.Println(styles.Success.Render(fmt.Sprintf("✓ Removed global query '%s'", name)))
 }
 
 func (a *App) removeConnection(connName string) {
 	conn, exists := a.config.Connections[connName]
 	if !exists {
-		printError(fmt.Sprintf("Connection '%s' does not exist", connName))
+		printError("Connection '%s' does not exist", connName)
 		return
 	}
+	if path := a.config.ConnectionSource(connName); path != "" {
+		printError("Connection '%s' is defined in %s, remove it there", connName, relativePath(path))
+	}
 
 	queryCount := len(conn.Queries)
 
@@ -83,7 +99,7 @@ func (a *App) removeConnection(connName string) {
 
 func (a *App) confirmDeletion(connName string, queryCount int) bool {
 	reader := bufio.NewReader(os.Stdin)
-	fmt.Printf(styles.Error.Render(fmt.Sprintf("This will delete connection '%s' and its %d queries. Continue? [y/N]: ", connName, queryCount)))
+	fmt.Print(styles.Error.Render(fmt.Sprintf("This will delete connection '%s' and its %d queries. Continue? [y/N]: ", connName, queryCount)))
 
 	response, err := reader.ReadString('\n')
 	if err != nil {
@@ -2,154 +2,137 @@ package main
 
 import (
 	"fmt"
+	"io"
 	"os"
+	"path/filepath"
 	"strings"
 
+	"github.com/charmbracelet/x/term"
+	"github.com/eduardofuncao/squix/internal/cli"
 	"github.com/eduardofuncao/squix/internal/config"
 	"github.com/eduardofuncao/squix/internal/db"
 	"github.com/eduardofuncao/squix/internal/editor"
 	"github.com/eduardofuncao/squix/internal/params"
+	"github.com/eduardofuncao/squix/internal/picker"
 	"github.com/eduardofuncao/squix/internal/run"
 )
 
-func (a *App) handleRun() {
-	if a.config.CurrentConnection == "" {
+func (a *App) handleRun(args *cli.Args) {
+	if a.connection == "" {
 		printError("No active connection.   Use 'squix switch <connection>' or 'squix init' first")
 	}
 
-	flags := parseRunFlags()
-	conn := config.FromConnectionYaml(a.config.Connections[a.config.CurrentConnection])
+	flags := run.Flags{
+		EditMode:  args.Bool("edit"),
+		LastQuery: args.Bool("last"),
+		Selector:  args.Arg(0),
+		Format:    args.String("format"),
+	}
+	format, err := run.ResolveOutputFormat(flags.Format)
+	if err != nil {
+		printError("%v", err)
+	}
+
+	conn := config.FromConnectionYaml(a.config.Connections[a.connection])
 
-	resolved, err := run.ResolveQuery(flags, a.config, a.config.CurrentConnection, conn)
+	if a.isScript(flags.Selector) {
+		a.runScript(flags, args, conn, format)
+		return
+	}
+
+	resolved, err := run.ResolveQuery(flags, a.config, a.connection, conn)
 	if err != nil {
 		printError("%v", err)
 	}
 
-	// Check if we need to create a new query via editor
+	// No selector: pick a saved query, or write a new one in the editor
 	if run.ShouldCreateNewQuery(resolved) {
-		newQuery := a.createNewQueryOrEdit()
-		resolved.Query = newQuery
+		resolved = a.pickQueryOrCreate(conn)
 	}
 
 	if flags.EditMode && !flags.LastQuery {
+		original := resolved.Query.SQL
 		resolved.Query = a.editQueryOrExit(resolved.Query)
+		resolved.Edited = resolved.Query.SQL != original
 	}
 
 	a.saveIfNeeded(resolved)
 
-	// Parse parameter flags and positional args
-	paramFlags := parseParameterFlags()
-	positionalArgsSlice := parsePositionalArgs(flags.Selector)
-
-	positionalArgs := params.MapPositionalArgs(resolved.Query.SQL, positionalArgsSlice)
+	// Parameters come as --name value flags, or in order after the selector
+	var positionalValues []string
+	if len(args.Positionals) > 1 {
+		positionalValues = args.Positionals[1:]
+	}
+	positionalArgs := params.MapPositionalArgs(resolved.Query.SQL, positionalValues)
 
-	if err := a.executeQueryWithParams(resolved.Query, conn, paramFlags, positionalArgs); err != nil {
+	if err := a.executeQueryWithParams(resolved.Query, conn, args.Params, positionalArgs, format); err != nil {
 		printError("%v", err)
 	}
 }
 
-func parseRunFlags() run.Flags {
-	flags := run.Flags{}
-	args := os.Args[2:]
-
-	for i, arg := range args {
-		// Skip parameter flags and their values
-		if strings.HasPrefix(arg, "--") && arg != "--edit" && arg != "-e" && arg != "--last" && arg != "-l" {
-			// This is a parameter flag, skip it and its value
-			if i+1 < len(args) && !strings.HasPrefix(args[i+1], "--") {
-				continue
-			}
-		}
-
-		switch arg {
-		case "--edit", "-e":
-			flags.EditMode = true
-		case "--last", "-l":
-			flags.LastQuery = true
-		default:
-			if !strings.HasPrefix(arg, "--") && flags.Selector == "" {
-				flags.Selector = arg
-			}
-		}
+// isScript tells a script to run from a saved query or inline SQL: - reads
+// it from stdin, and a .sql path is a file unless a query has that name
+func (a *App) isScript(selector string) bool {
+	if selector == "-" {
+		return true
+	}
+	if !strings.EqualFold(filepath.Ext(selector), ".sql") {
+		return false
+	}
+	if _, ok := a.config.Connections[a.connection].Queries[selector]; ok {
+		return false
 	}
-	return flags
+	_, ok := a.config.Queries[selector]
+	return !ok
 }
 
-func parseParameterFlags() map[string]string {
-	paramValues := make(map[string]string)
-	args := os.Args[2:]
-
-	i := 0
-	for i < len(args) {
-		arg := args[i]
-
-		// Skip known flags
-		if arg == "--edit" || arg == "-e" || arg == "--last" || arg == "-l" {
-			i++
-			continue
-		}
+func (a *App) runScript(flags run.Flags, args *cli.Args, conn db.DatabaseConnection, format string) {
+	if flags.EditMode || flags.LastQuery {
+		printError("--edit and --last can't be used with a script")
+	}
+	if args.Bool("continue") && args.Bool("stop-on-error") {
+		printError("--continue and --stop-on-error can't be used together")
+	}
 
-		// Check if it's a parameter flag (--param value)
-		if strings.HasPrefix(arg, "--") && len(arg) > 2 {
-			paramName := strings.TrimPrefix(arg, "--")
-
-			// If next arg exists and doesn't start with --, it's the value
-			if i+1 < len(args) && !strings.HasPrefix(args[i+1], "--") {
-				paramValues[paramName] = args[i+1]
-				i += 2 // Skip next arg as we've consumed it
-			} else {
-				// Flag without value - set to empty string (will use default)
-				paramValues[paramName] = ""
-				i++
-			}
-		} else {
-			// Skip non-flag args (query selector and positional params handled elsewhere)
-			i++
-		}
+	var script []byte
+	var err error
+	if flags.Selector == "-" {
+		script, err = io.ReadAll(os.Stdin)
+	} else {
+		script, err = os.ReadFile(flags.Selector)
+	}
+	if err != nil {
+		printError("Could not read script: %v", err)
 	}
 
-	return paramValues
-}
+	statements := db.SplitStatements(string(script), conn.GetDbType())
+	query := db.Query{Name: "<script>", Id: -1}
 
-// parsePositionalArgs extracts positional parameter values from CLI
-// Returns slice of values in order (after query selector)
-func parsePositionalArgs(selector string) []string {
-	args := os.Args[2:]
-	var positionals []string
-	selectorSeen := false
-
-	i := 0
-	for i < len(args) {
-		arg := args[i]
-
-		// Skip flags and their values
-		if strings.HasPrefix(arg, "--") {
-			// Skip the flag itself
-			i++
-			// Skip the value if it exists and isn't another flag
-			if i < len(args) && !strings.HasPrefix(args[i], "--") {
-				i++
-			}
-			continue
-		}
-		if arg == "--edit" || arg == "-e" || arg == "--last" || arg == "-l" {
-			i++
-			continue
-		}
-
-		// First non-flag arg is the query selector
-		if !selectorSeen {
-			selectorSeen = true
-			i++
-			continue
-		}
+	var onRerun func(string) error
+	onRerun = func(editedSQL string) error {
+		editedQuery := query
+		editedQuery.SQL = editedSQL
 
-		// Remaining non-flag args are positional parameter values
-		positionals = append(positionals, arg)
-		i++
+		return run.Execute(run.ExecutionParams{
+			Query:        editedQuery,
+			Connection:   conn,
+			Config:       a.config,
+			SaveCallback: a.saveQueryFromTable,
+			OnRerun:      onRerun,
+		})
 	}
 
-	return positionals
+	err = run.ExecuteScript(statements, args.Bool("continue"), run.ExecutionParams{
+		Query:        query,
+		Connection:   conn,
+		Config:       a.config,
+		SaveCallback: a.saveQueryFromTable,
+		OnRerun:      onRerun,
+		Format:       format,
+	})
+	if err != nil {
+		printError("%v", err)
+	}
 }
 
 func (a *App) createNewQueryOrEdit() db.Query {
@@ -167,6 +150,42 @@ func (a *App) createNewQueryOrEdit() db.Query {
 	return db.Query{Name: "<runtime>", SQL: editedSQL, Id: -1}
 }
 
+// pickQueryOrCreate opens the query picker when the connection has saved
+// queries and squix runs in a terminal, otherwise it goes straight to the editor
+func (a *App) pickQueryOrCreate(conn db.DatabaseConnection) run.ResolvedQuery {
+	interactive := term.IsTerminal(os.Stdin.Fd()) && term.IsTerminal(os.Stdout.Fd())
+
+	for interactive && len(conn.GetQueries()) > 0 {
+		selection, err := picker.Pick(fmt.Sprintf("Queries on %s", a.connection), conn.GetQueries())
+		if err != nil {
+			printError("Error opening query picker: %v", err)
+		}
+
+		switch selection.Action {
+		case picker.ActionNone:
+			os.Exit(0)
+		case picker.ActionRun:
+			return run.ResolvedQuery{Query: selection.Query, Saveable: true}
+		case picker.ActionEdit:
+			return run.ResolvedQuery{Query: a.editQueryOrExit(selection.Query), Saveable: true}
+		case picker.ActionDelete:
+			a.deleteQuery(a.connection, selection.Query.Name)
+			conn.SetQueries(a.config.Connections[a.connection].Queries)
+		case picker.ActionNew:
+			return run.ResolvedQuery{Query: a.createNewQueryOrEdit(), Saveable: false}
+		}
+	}
+
+	return run.ResolvedQuery{Query: a.createNewQueryOrEdit(), Saveable: false}
+}
+
+func (a *App) deleteQuery(connName, queryName string) {
+	delete(a.config.Connections[connName].Queries, queryName)
+	if err := a.config.Save(); err != nil {
+		printError("Could not save configuration file: %v", err)
+	}
+}
+
 func (a *App) editQueryOrExit(query db.Query) db.Query {
 	editedSQL, err := editor.EditTempFile(query.SQL, "squix-run-")
 	if err != nil {
@@ -177,12 +196,25 @@ func (a *App) editQueryOrExit(query db.Query) db.Query {
 }
 
 func (a *App) saveIfNeeded(resolved run.ResolvedQuery) {
+	if resolved.Global {
+		// An edit goes back to the library, in the variant run here
+		if resolved.Edited {
+			dbType := a.config.Connections[a.connection].DBType
+			if err := a.config.UpdateGlobalQuerySQL(resolved.Query.Name, dbType, resolved.Query.SQL); err != nil {
+				printError("Failed to save query: %v", err)
+			}
+		}
+		if err := a.config.UpdateLastQuery(a.connection, resolved.Query); err != nil {
+			printError("Failed to save last query: %v", err)
+		}
+		return
+	}
 	if !resolved.Saveable {
 		return
 	}
 
 	// Save the query and update last query
-	if err := a.config.SaveQueryAndLast(a.config.CurrentConnection, resolved.Query, true); err != nil {
+	if err := a.config.SaveQueryAndLast(a.connection, resolved.Query, true); err != nil {
 		printError("Failed to save query: %v", err)
 	}
 }
@@ -190,11 +222,8 @@ func (a *App) saveIfNeeded(resolved run.ResolvedQuery) {
 func (a *App) executeQuery(query db.Query, conn db.DatabaseConnection) error {
 	var onRerun func(string) error
 	onRerun = func(editedSQL string) error {
-		editedQuery := db.Query{
-			Name: query.Name,
-			SQL:  editedSQL,
-			Id:   query.Id,
-		}
+		editedQuery := query
+		editedQuery.SQL = editedSQL
 
 		return run.Execute(run.ExecutionParams{
 			Query:        editedQuery,
@@ -214,16 +243,13 @@ func (a *App) executeQuery(query db.Query, conn db.DatabaseConnection) error {
 	})
 }
 
-func (a *App) executeQueryWithParams(query db.Query, conn db.DatabaseConnection, paramFlags, positionalArgs map[string]string) error {
+func (a *App) executeQueryWithParams(query db.Query, conn db.DatabaseConnection, paramFlags, positionalArgs map[string]string, format string) error {
 	// Process parameters
-	sql, args, displaySQL := a.processParameters(query.SQL, conn, paramFlags, positionalArgs)
+	sql, args, displaySQL, values := a.processParameters(query.SQL, conn, paramFlags, positionalArgs)
 
 	// Create a modified query with processed SQL for execution
-	processedQuery := db.Query{
-		Name: query.Name,
-		SQL:  sql,
-		Id:   query.Id,
-	}
+	processedQuery := query
+	processedQuery.SQL = sql
 
 	var onRerun func(string) error
 	onRerun = func(editedSQL string) error {
@@ -232,19 +258,17 @@ func (a *App) executeQueryWithParams(query db.Query, conn db.DatabaseConnection,
 		finalSQL := editedSQL
 		finalArgs := []any{}
 		finalDisplaySQL := ""
+		var finalValues map[string]string
 
 		if strings.Contains(editedSQL, ":") {
-			finalSQL, finalArgs, finalDisplaySQL = a.processParameters(editedSQL, conn, paramFlags, positionalArgs)
+			finalSQL, finalArgs, finalDisplaySQL, finalValues = a.processParameters(editedSQL, conn, paramFlags, positionalArgs)
 		}
 		if finalDisplaySQL == "" {
 			finalDisplaySQL = finalSQL
 		}
 
-		processedQuery := db.Query{
-			Name: query.Name,
-			SQL:  finalSQL,
-			Id:   query.Id,
-		}
+		processedQuery := query
+		processedQuery.SQL = finalSQL
 
 		return run.Execute(run.ExecutionParams{
 			Query:        processedQuery,
@@ -253,6 +277,8 @@ func (a *App) executeQueryWithParams(query db.Query, conn db.DatabaseConnection,
 			SaveCallback: a.saveQueryFromTable,
 			Args:         finalArgs,
 			DisplaySQL:   finalDisplaySQL,
+			ParamSQL:     editedSQL,
+			ParamValues:  finalValues,
 			OnRerun:      onRerun,
 		})
 	}
@@ -264,17 +290,21 @@ func (a *App) executeQueryWithParams(query db.Query, conn db.DatabaseConnection,
 		SaveCallback: a.saveQueryFromTable,
 		Args:         args,
 		DisplaySQL:   displaySQL,
+		ParamSQL:     query.SQL,
+		ParamValues:  values,
 		OnRerun:      onRerun,
+		Format:       format,
 	})
 }
 
-// processParameters handles parameter extraction, validation, and substitution
-func (a *App) processParameters(sql string, conn db.DatabaseConnection, cliValues, positionals map[string]string) (string, []any, string) {
+// processParameters handles parameter extraction, validation, and substitution.
+// It also returns the resolved values by name, nil when sql has no parameters
+func (a *App) processParameters(sql string, conn db.DatabaseConnection, cliValues, positionals map[string]string) (string, []any, string, map[string]string) {
 	// Extract parameter definitions from SQL
 	paramDefs := params.ExtractParameters(sql)
 
 	if len(paramDefs) == 0 {
-		return sql, []any{}, ""
+		return sql, []any{}, "", nil
 	}
 
 	// Map positional args to parameter names
@@ -321,42 +351,14 @@ func (a *App) processParameters(sql string, conn db.DatabaseConnection, cliValue
 	// Generate display SQL with actual values for TUI
 	displaySQL := params.GenerateDisplaySQL(sql, paramValues)
 
-	// For Oracle, use literal substitution instead of prepared statements
-	// This is a workaround for godror prepared statement issues
-	if conn.GetDbType() == "oracle" && len(args) > 0 {
-		finalSQL = substituteOracleLiterals(finalSQL, args)
-		args = []any{}
-	}
-
-	return finalSQL, args, displaySQL
+	return finalSQL, args, displaySQL, paramValues
 }
 
-// substituteOracleLiterals replaces :1, :2 placeholders with actual values for Oracle
-func substituteOracleLiterals(sql string, args []any) string {
-	result := sql
-	for i, arg := range args {
-		placeholder := fmt.Sprintf(":%d", i+1)
-		var value string
-		switch v := arg.(type) {
-		case string:
-			// Escape single quotes in strings
-			escaped := strings.ReplaceAll(v, "'", "''")
-			value = fmt.Sprintf("'%s'", escaped)
-		case int, int32, int64:
-			value = fmt.Sprintf("%d", v)
-		case float32, float64:
-			value = fmt.Sprintf("%f", v)
-		default:
-			// For other types, try to convert to string
-			value = fmt.Sprintf("'%v'", v)
-		}
-		result = strings.ReplaceAll(result, placeholder, value)
-	}
-	return result
+func (a *App) saveQueryFromTable(query db.Query) (db.Query, error) {
+	return a.saveQueryToConnection(a.connection, query)
 }
 
-func (a *App) saveQueryFromTable(query db.Query) (db.Query, error) {
-	connName := a.config.CurrentConnection
+func (a *App) saveQueryToConnection(connName string, query db.Query) (db.Query, error) {
 	if connName == "" {
 		return db.Query{}, fmt.Errorf("no active connection")
 	}
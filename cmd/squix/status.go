@@ -4,18 +4,19 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/eduardofuncao/squix/internal/cli"
 	"github.com/eduardofuncao/squix/internal/config"
 	"github.com/eduardofuncao/squix/internal/spinner"
 	"github.com/eduardofuncao/squix/internal/styles"
 )
 
-func (a *App) handleStatus() {
-	if a.config.CurrentConnection == "" {
+func (a *App) handleStatus(args *cli.Args) {
+	if a.connection == "" {
 		fmt.Println(styles.Faint.Render("No active connection"))
 		return
 	}
 
-	currConn := a.config.Connections[a.config.CurrentConnection]
+	currConn := a.config.Connections[a.connection]
 
 	connInfo := fmt.Sprintf("%s/%s", currConn.DBType, currConn.Name)
 	if currConn.Schema != "" {
@@ -69,4 +70,10 @@ func (a *App) handleStatus() {
 
 	fmt.Printf("%s Using %s\n", styles.Success.Render(circleIcon), styles.Title.Render(connInfo))
 	fmt.Printf("  %d saved queries, %s\n", queryCount, styles.Faint.Render(statusText))
+	if a.connectionSource != "" {
+		fmt.Println(styles.Faint.Render(fmt.Sprintf("  from %s, the active connection is '%s'", a.connectionSource, a.config.CurrentConnection)))
+	}
+	if project := a.config.Project(); project != nil {
+		fmt.Println(styles.Faint.Render("  project file: " + relativePath(project.Path)))
+	}
 }
@@ -4,15 +4,13 @@ import (
 	"fmt"
 	"os"
 
+	"github.com/eduardofuncao/squix/internal/cli"
+	"github.com/eduardofuncao/squix/internal/config"
 	"github.com/eduardofuncao/squix/internal/styles"
 )
 
-func (a *App) handleSwitch() {
-	if len(os.Args) < 3 {
-		printError("Usage: squix switch/use <db-name>")
-	}
-
-	connName := os.Args[2]
+func (a *App) handleSwitch(args *cli.Args) {
+	connName := args.Arg(0)
 	conn, ok := a.config.Connections[connName]
 	if !ok {
 		printError("Connection '%s' does not exist", connName)
@@ -25,4 +23,8 @@ func (a *App) handleSwitch() {
 	}
 
 	fmt. Println(styles.Success.Render("⇄ Switched to: "), styles.Title.Render(fmt.Sprintf("%s/%s", conn.DBType, connName)))
+
+	if env := os.Getenv(config.ConnectionEnv); env != "" && env != connName {
+		fmt.Println(styles.Faint.Render(fmt.Sprintf("$%s=%s still overrides it in this shell", config.ConnectionEnv, env)))
+	}
 }
@@ -1,11 +1,11 @@
 package main
 
 import (
+	"context"
 	"fmt"
-	"os"
-	"strings"
 	"time"
 
+	"github.com/eduardofuncao/squix/internal/cli"
 	"github.com/eduardofuncao/squix/internal/config"
 	"github.com/eduardofuncao/squix/internal/db"
 	"github.com/eduardofuncao/squix/internal/run"
@@ -13,36 +13,15 @@ import (
 	"github.com/eduardofuncao/squix/internal/table"
 )
 
-type tablesFlags struct {
-	oneline bool
-}
-
-func parseTablesFlags() (tablesFlags, []string) {
-	flags := tablesFlags{}
-	remainingArgs := []string{}
-	args := os.Args[2:]
-
-	for _, arg := range args {
-		if arg == "--oneline" || arg == "-o" {
-			flags.oneline = true
-		} else if !strings.HasPrefix(arg, "-") {
-			remainingArgs = append(remainingArgs, arg)
-		}
-	}
-
-	return flags, remainingArgs
-}
-
-func (a *App) handleTables() {
-	if a.config.CurrentConnection == "" {
+func (a *App) handleTables(args *cli.Args) {
+	if a.connection == "" {
 		printError(
 			"No active connection. Use 'squix switch <connection>' or 'squix init' first",
 		)
 	}
 
-	flags, args := parseTablesFlags()
 	conn := config.FromConnectionYaml(
-		a.config.Connections[a.config.CurrentConnection],
+		a.config.Connections[a.connection],
 	)
 
 	if err := conn.Open(); err != nil {
@@ -56,8 +35,8 @@ func (a *App) handleTables() {
 	defer conn.Close()
 
 	// If a table name is provided, run SELECT * FROM table
-	if len(args) > 0 {
-		tableName := args[0]
+	if len(args.Positionals) > 0 {
+		tableName := args.Arg(0)
 
 		// Create a temporary query object with table metadata
 		query := db.Query{
@@ -120,25 +99,28 @@ func (a *App) handleTables() {
 		queryStr,
 	)
 
-	if flags.oneline {
+	if args.Bool("oneline") {
 		// For oneline mode, execute query and print just the table names
-		rows, err := conn.ExecQuery(nameOnlyQuery)
+		rows, err := conn.ExecQuery(context.Background(), nameOnlyQuery)
 		if err != nil {
 			printError("Could not retrieve tables: %v", err)
 		}
 		defer rows.Close()
 
+		var tables []string
 		for rows.Next() {
 			var tableName string
 			if err := rows.Scan(&tableName); err != nil {
 				printError("Could not scan row: %v", err)
 			}
 			fmt.Println(tableName)
+			tables = append(tables, tableName)
 		}
 
 		if err := rows.Err(); err != nil {
 			printError("Error iterating tables: %v", err)
 		}
+		config.SaveTableCache(a.connection, tables)
 	} else {
 		// For normal mode, use the interactive table viewer with name-only query
 		a.showTablesInteractive(conn, nameOnlyQuery)
@@ -149,12 +131,13 @@ func (a *App) showTablesInteractive(
 	conn db.DatabaseConnection,
 	queryStr string,
 ) {
+	listQuery := queryStr
 	for {
 		start := time.Now()
 		done := make(chan struct{})
 		go spinner.CircleWaitWithTimer(done)
 
-		rows, err := conn.ExecQuery(queryStr)
+		rows, err := conn.ExecQuery(context.Background(), queryStr)
 		if err != nil {
 			done <- struct{}{}
 			printError("Could not retrieve tables: %v", err)
@@ -174,6 +157,15 @@ func (a *App) showTablesInteractive(
 			return
 		}
 
+		// Remember the names for shell completion
+		if queryStr == listQuery {
+			tables := make([]string, 0, len(data))
+			for _, row := range data {
+				tables = append(tables, row[0].Text)
+			}
+			config.SaveTableCache(a.connection, tables)
+		}
+
 		q := db.Query{
 			Name: "tables",
 			SQL:  queryStr,
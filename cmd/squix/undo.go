@@ -0,0 +1,88 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/eduardofuncao/squix/internal/cli"
+	"github.com/eduardofuncao/squix/internal/config"
+	"github.com/eduardofuncao/squix/internal/editor"
+	"github.com/eduardofuncao/squix/internal/styles"
+	"github.com/eduardofuncao/squix/internal/undo"
+)
+
+func (a *App) handleUndo(args *cli.Args) {
+	if a.connection == "" {
+		printError("No active connection. Use 'squix switch <connection>' or 'squix init' first")
+	}
+
+	if args.Bool("list") {
+		a.listUndo()
+		return
+	}
+
+	entry, ok, err := undo.Last(a.connection)
+	if err != nil {
+		printError("Could not load undo journal: %v", err)
+	}
+	if !ok {
+		fmt.Println(styles.Faint.Render(fmt.Sprintf("Nothing to undo on %s", a.connection)))
+		return
+	}
+
+	script := entry.Preview()
+	if !args.Bool("yes") {
+		var saved bool
+		script, saved, err = editor.EditTempFileSaved(script+"\n-- Save and exit to run the statements above\n-- To cancel, exit without saving (e.g., :q! in vim)\n", "squix-undo-")
+		if err != nil {
+			printError("Error opening editor: %v", err)
+		}
+		if !saved {
+			fmt.Println(styles.Faint.Render("Undo cancelled"))
+			return
+		}
+	}
+
+	conn := config.FromConnectionYaml(a.config.Connections[a.connection])
+	if err := conn.Open(); err != nil {
+		printError("Could not open connection to %s: %v", a.connection, err)
+	}
+	defer conn.Close()
+
+	if err := entry.Run(conn, script); err != nil {
+		if errors.Is(err, undo.ErrNothingToRun) {
+			printError("No statements to run, undo cancelled")
+		}
+		printError("Undo failed: %v", err)
+	}
+	if err := undo.MarkUndone(entry); err != nil {
+		printError("Undo ran, but the undo journal could not be updated: %v", err)
+	}
+
+	fmt.Println(styles.Success.Render(fmt.Sprintf("✓ Undid the %s on %s", entry.Action, entry.Table)))
+}
+
+func (a *App) listUndo() {
+	entries, err := undo.Load()
+	if err != nil {
+		printError("Could not load undo journal: %v", err)
+	}
+
+	found := false
+	for i := len(entries) - 1; i >= 0; i-- {
+		entry := entries[i]
+		if entry.Connection != a.connection {
+			continue
+		}
+		found = true
+		if entry.Undone {
+			fmt.Println(styles.Faint.Render(entry.Summary() + " [undone]"))
+			continue
+		}
+		fmt.Println(entry.Summary())
+	}
+
+	if !found {
+		fmt.Println(styles.Faint.Render(fmt.Sprintf("No changes recorded on %s", a.connection)))
+	}
+}
@@ -3,6 +3,7 @@ package main
 import (
 	"fmt"
 	"os"
+	"path/filepath"
 
 	"github.com/eduardofuncao/squix/internal/styles"
 )
@@ -12,3 +13,17 @@ func printError(format string, args ...any) {
 	fmt.Fprintln(os.Stderr, styles.Error.Render("✗ Error:"), msg)
 	os.Exit(1)
 }
+
+// relativePath shortens a path to be relative to the working directory when
+// that is shorter
+func relativePath(path string) string {
+	wd, err := os.Getwd()
+	if err != nil {
+		return path
+	}
+	rel, err := filepath.Rel(wd, path)
+	if err != nil || len(rel) >= len(path) {
+		return path
+	}
+	return rel
+}
@@ -0,0 +1,147 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/x/term"
+	"github.com/eduardofuncao/squix/internal/cli"
+	"github.com/eduardofuncao/squix/internal/config"
+	"github.com/eduardofuncao/squix/internal/styles"
+)
+
+func (a *App) handleVault(args *cli.Args) {
+	subcommand, rest := args.Arg(0), args.Positionals[1:]
+
+	switch subcommand {
+	case "set", "add":
+		if len(rest) < 1 {
+			a.usageError(args.Command, fmt.Errorf("missing key for vault set"))
+		}
+		a.vaultSet(rest[0], rest[1:])
+	case "rm", "remove", "delete":
+		if len(rest) != 1 {
+			a.usageError(args.Command, fmt.Errorf("vault rm takes one key"))
+		}
+		a.vaultRemove(rest[0])
+	case "list", "ls":
+		a.vaultList()
+	default:
+		a.usageError(args.Command, fmt.Errorf("unknown vault command: %s", subcommand))
+	}
+}
+
+func (a *App) vaultSet(key string, valueArgs []string) {
+	creating := !config.VaultExists()
+	passphrase := unlockVaultPassphrase(creating)
+
+	secrets, err := config.LoadVault(passphrase)
+	if err != nil {
+		printError("Could not open vault: %v", err)
+	}
+
+	var value string
+	if len(valueArgs) > 0 {
+		value = strings.Join(valueArgs, " ")
+	} else {
+		value = readSecret(fmt.Sprintf("Value for %s: ", key))
+	}
+	if value == "" {
+		printError("Empty value, nothing saved")
+	}
+
+	secrets[key] = value
+	if err := config.SaveVault(secrets, passphrase); err != nil {
+		printError("Could not save vault: %v", err)
+	}
+
+	fmt.Println(styles.Success.Render(fmt.Sprintf("✓ Saved secret '%s'", key)))
+	fmt.Println(styles.Faint.Render(fmt.Sprintf("  Reference it in a conn_string as ${vault:%s}", key)))
+}
+
+func (a *App) vaultRemove(key string) {
+	if !config.VaultExists() {
+		printError("No vault found")
+	}
+	passphrase := unlockVaultPassphrase(false)
+
+	secrets, err := config.LoadVault(passphrase)
+	if err != nil {
+		printError("Could not open vault: %v", err)
+	}
+	if _, ok := secrets[key]; !ok {
+		printError("Secret '%s' does not exist", key)
+	}
+
+	delete(secrets, key)
+	if err := config.SaveVault(secrets, passphrase); err != nil {
+		printError("Could not save vault: %v", err)
+	}
+
+	fmt.Println(styles.Success.Render(fmt.Sprintf("✓ Removed secret '%s'", key)))
+}
+
+func (a *App) vaultList() {
+	if !config.VaultExists() {
+		fmt.Println(styles.Faint.Render("No secrets stored"))
+		return
+	}
+	passphrase := unlockVaultPassphrase(false)
+
+	secrets, err := config.LoadVault(passphrase)
+	if err != nil {
+		printError("Could not open vault: %v", err)
+	}
+
+	keys := make([]string, 0, len(secrets))
+	for key := range secrets {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	fmt.Println(styles.Title.Render("Vault secrets"))
+	for _, key := range keys {
+		fmt.Println("  " + key)
+	}
+}
+
+// unlockVaultPassphrase asks for the passphrase, twice when a new vault is
+// being created so a typo can't lock the user out
+func unlockVaultPassphrase(creating bool) string {
+	prompt := "Vault passphrase: "
+	if creating {
+		prompt = "New vault passphrase: "
+	}
+
+	passphrase, err := config.VaultPassphrase(prompt)
+	if err != nil {
+		printError("%v", err)
+	}
+
+	if creating && os.Getenv(config.VaultPassphraseEnv) == "" {
+		if readSecret("Repeat passphrase: ") != passphrase {
+			printError("Passphrases do not match")
+		}
+	}
+
+	return passphrase
+}
+
+// readSecret reads a line without echoing it, or from stdin when piped
+func readSecret(prompt string) string {
+	if !term.IsTerminal(os.Stdin.Fd()) {
+		line, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+		return strings.TrimRight(line, "\r\n")
+	}
+
+	fmt.Fprint(os.Stderr, prompt)
+	secret, err := term.ReadPassword(os.Stdin.Fd())
+	fmt.Fprintln(os.Stderr)
+	if err != nil {
+		printError("Could not read input: %v", err)
+	}
+	return string(secret)
+}
@@ -0,0 +1,293 @@
+// Package cli parses squix's command line: a command name, its flags and
+// positional arguments, and the global flags accepted by every command
+package cli
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Unlimited as MaxArgs lets a command take any number of arguments
+const Unlimited = -1
+
+type Flag struct {
+	Name  string // Long name, used as --name
+	Alias string // Optional second long name
+	Short string // Optional one letter form, used as -s
+	Value string // Placeholder shown in help for flags taking a value, empty for switches
+	Usage string
+}
+
+func (f Flag) takesValue() bool {
+	return f.Value != ""
+}
+
+// Label is the flag as shown in help, like "--limit, -l <n>"
+func (f Flag) Label() string {
+	label := "--" + f.Name
+	if f.Alias != "" {
+		label += ", --" + f.Alias
+	}
+	if f.Short != "" {
+		label += ", -" + f.Short
+	}
+	if f.takesValue() {
+		label += " <" + f.Value + ">"
+	}
+	return label
+}
+
+// Section is an extra block of command help. Lines with a tab are shown as
+// two aligned columns
+type Section struct {
+	Title string
+	Lines []string
+}
+
+type Command struct {
+	Name    string
+	Aliases []string
+	Summary string // One line, for the command list
+
+	Usage       []string // Without the leading "squix"
+	Description []string
+	Flags       []Flag
+	Sections    []Section
+	Examples    []string
+
+	MinArgs, MaxArgs int
+
+	// Params collects unknown --name value flags into Args.Params instead of
+	// rejecting them, for query parameters
+	Params bool
+
+	// Hidden commands are left out of help and completion
+	Hidden bool
+
+	Run func(*Args)
+}
+
+// Names is the command name followed by its aliases
+func (c *Command) Names() []string {
+	return append([]string{c.Name}, c.Aliases...)
+}
+
+type Registry struct {
+	Globals  []Flag
+	commands []*Command
+}
+
+func (r *Registry) Register(commands ...*Command) {
+	r.commands = append(r.commands, commands...)
+}
+
+// Commands lists the visible commands in registration order
+func (r *Registry) Commands() []*Command {
+	var visible []*Command
+	for _, c := range r.commands {
+		if !c.Hidden {
+			visible = append(visible, c)
+		}
+	}
+	return visible
+}
+
+// Lookup finds a command by name or alias
+func (r *Registry) Lookup(name string) (*Command, bool) {
+	for _, c := range r.commands {
+		for _, n := range c.Names() {
+			if n == name {
+				return c, true
+			}
+		}
+	}
+	return nil, false
+}
+
+// FindFlag resolves a flag of the command by long or short name, falling
+// back to the global flags. Command flags shadow global ones
+func (r *Registry) FindFlag(cmd *Command, name string, long bool) (Flag, bool) {
+	var flags []Flag
+	if cmd != nil {
+		flags = append(flags, cmd.Flags...)
+	}
+	flags = append(flags, r.Globals...)
+
+	for _, f := range flags {
+		if long && (f.Name == name || f.Alias != "" && f.Alias == name) || !long && f.Short != "" && f.Short == name {
+			return f, true
+		}
+	}
+	return Flag{}, false
+}
+
+// Args is a parsed command line
+type Args struct {
+	Command     *Command
+	Positionals []string
+	Params      map[string]string
+
+	values map[string]string
+	all    map[string][]string // Every value of flags given more than once
+}
+
+func (a *Args) Has(name string) bool {
+	_, ok := a.values[name]
+	return ok
+}
+
+func (a *Args) Bool(name string) bool {
+	return a.values[name] == "true"
+}
+
+// String returns the value of a flag, the last one when it was repeated
+func (a *Args) String(name string) string {
+	return a.values[name]
+}
+
+// Strings returns every value given for a flag that may be repeated
+func (a *Args) Strings(name string) []string {
+	return a.all[name]
+}
+
+// Int returns the value of an integer flag, or def when it wasn't given
+func (a *Args) Int(name string, def int) (int, error) {
+	value, ok := a.values[name]
+	if !ok {
+		return def, nil
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid value for --%s: %s (expected a number)", name, value)
+	}
+	return n, nil
+}
+
+// Arg returns the positional argument at i, or "" when there are fewer
+func (a *Args) Arg(i int) string {
+	if i < len(a.Positionals) {
+		return a.Positionals[i]
+	}
+	return ""
+}
+
+// Parse reads the arguments after the program name. Flags may come before
+// or after the command, "--name=value" and "--name value" are both accepted,
+// and everything after "--" is positional. On error the arguments parsed so
+// far are returned too, so global flags like --config still apply
+func (r *Registry) Parse(argv []string) (*Args, error) {
+	args := &Args{
+		Params: map[string]string{},
+		values: map[string]string{},
+		all:    map[string][]string{},
+	}
+
+	rest := false
+	for i := 0; i < len(argv); i++ {
+		arg := argv[i]
+
+		switch {
+		case rest || !isFlag(arg):
+			if args.Command == nil {
+				cmd, ok := r.Lookup(arg)
+				if !ok {
+					return args, fmt.Errorf("unknown command: %s", arg)
+				}
+				args.Command = cmd
+				continue
+			}
+			args.Positionals = append(args.Positionals, arg)
+		case arg == "--":
+			rest = true
+		default:
+			consumed, err := r.parseFlag(args, argv[i:])
+			if err != nil {
+				return args, err
+			}
+			i += consumed
+		}
+	}
+
+	return args, nil
+}
+
+// parseFlag stores the flag at argv[0] and returns how many of the
+// following arguments it consumed as its value
+func (r *Registry) parseFlag(args *Args, argv []string) (int, error) {
+	arg := argv[0]
+	long := strings.HasPrefix(arg, "--")
+	name, value, hasValue := strings.Cut(strings.TrimLeft(arg, "-"), "=")
+
+	flag, ok := r.FindFlag(args.Command, name, long)
+	if !ok {
+		if long && args.Command != nil && args.Command.Params {
+			return parseParam(args, name, value, hasValue, argv), nil
+		}
+		if args.Command != nil {
+			return 0, fmt.Errorf("unknown flag for %s: %s", args.Command.Name, arg)
+		}
+		return 0, fmt.Errorf("unknown flag: %s", arg)
+	}
+
+	consumed := 0
+	switch {
+	case !flag.takesValue() && hasValue:
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return 0, fmt.Errorf("--%s does not take a value", flag.Name)
+		}
+		value = strconv.FormatBool(b)
+	case !flag.takesValue():
+		value = "true"
+	case !hasValue:
+		if len(argv) < 2 {
+			return 0, fmt.Errorf("--%s needs a value", flag.Name)
+		}
+		value = argv[1]
+		consumed = 1
+	}
+
+	args.values[flag.Name] = value
+	args.all[flag.Name] = append(args.all[flag.Name], value)
+	return consumed, nil
+}
+
+// parseParam stores a query parameter. Its value is the next argument unless
+// that is another long flag, use --name=--value for values starting with --
+func parseParam(args *Args, name, value string, hasValue bool, argv []string) int {
+	consumed := 0
+	if !hasValue && len(argv) > 1 && !strings.HasPrefix(argv[1], "--") {
+		value = argv[1]
+		consumed = 1
+	}
+	args.Params[name] = value
+	return consumed
+}
+
+// CheckArgs validates the number of positional arguments
+func (a *Args) CheckArgs() error {
+	cmd := a.Command
+	if cmd == nil {
+		return nil
+	}
+	if len(a.Positionals) < cmd.MinArgs {
+		return fmt.Errorf("missing arguments for %s", cmd.Name)
+	}
+	if cmd.MaxArgs != Unlimited && len(a.Positionals) > cmd.MaxArgs {
+		return fmt.Errorf("too many arguments for %s: %s", cmd.Name, strings.Join(a.Positionals[cmd.MaxArgs:], " "))
+	}
+	return nil
+}
+
+// isFlag tells flags from positional arguments. A lone "-" and negative
+// numbers are positional
+func isFlag(arg string) bool {
+	if len(arg) < 2 || arg[0] != '-' {
+		return false
+	}
+	if _, err := strconv.ParseFloat(arg, 64); err == nil {
+		return false
+	}
+	return true
+}
@@ -0,0 +1,148 @@
+package cli
+
+import (
+	"reflect"
+	"testing"
+)
+
+func testRegistry() *Registry {
+	r := &Registry{Globals: []Flag{
+		{Name: "connection", Short: "c", Value: "name"},
+		{Name: "no-color"},
+	}}
+	r.Register(
+		&Command{
+			Name:    "run",
+			Aliases: []string{"query"},
+			Flags: []Flag{
+				{Name: "edit", Short: "e"},
+				{Name: "format", Value: "format"},
+				{Name: "tag", Value: "tag"},
+			},
+			MaxArgs: Unlimited,
+			Params:  true,
+		},
+		&Command{
+			Name: "init",
+			Flags: []Flag{
+				{Name: "conn-string", Alias: "conn", Short: "c", Value: "conn"},
+			},
+			MaxArgs: 4,
+		},
+		&Command{Name: "switch", MinArgs: 1, MaxArgs: 1},
+	)
+	return r
+}
+
+func TestParse(t *testing.T) {
+	r := testRegistry()
+
+	args, err := r.Parse([]string{"-c", "prod", "query", "users", "--format=csv", "-e", "--name", "ann", "-5"})
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if args.Command.Name != "run" {
+		t.Errorf("Command = %s, want run", args.Command.Name)
+	}
+	if got := args.String("connection"); got != "prod" {
+		t.Errorf("connection = %q, want prod", got)
+	}
+	if got := args.String("format"); got != "csv" {
+		t.Errorf("format = %q, want csv", got)
+	}
+	if !args.Bool("edit") {
+		t.Error("edit not set")
+	}
+	if want := []string{"users", "-5"}; !reflect.DeepEqual(args.Positionals, want) {
+		t.Errorf("Positionals = %v, want %v", args.Positionals, want)
+	}
+	if want := map[string]string{"name": "ann"}; !reflect.DeepEqual(args.Params, want) {
+		t.Errorf("Params = %v, want %v", args.Params, want)
+	}
+}
+
+func TestParseRepeatedFlag(t *testing.T) {
+	args, err := testRegistry().Parse([]string{"run", "q", "--tag", "a", "--tag=b"})
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if want := []string{"a", "b"}; !reflect.DeepEqual(args.Strings("tag"), want) {
+		t.Errorf("Strings(tag) = %v, want %v", args.Strings("tag"), want)
+	}
+	if got := args.String("tag"); got != "b" {
+		t.Errorf("String(tag) = %q, want the last value b", got)
+	}
+}
+
+func TestParseValuesStartingWithDashes(t *testing.T) {
+	r := testRegistry()
+
+	args, err := r.Parse([]string{"run", "q", "--name=--x", "--format", "--weird", "--", "--edit"})
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if got := args.Params["name"]; got != "--x" {
+		t.Errorf("name = %q, want --x", got)
+	}
+	if got := args.String("format"); got != "--weird" {
+		t.Errorf("format = %q, want --weird", got)
+	}
+	if args.Bool("edit") {
+		t.Error("edit after -- should be positional")
+	}
+	if want := []string{"q", "--edit"}; !reflect.DeepEqual(args.Positionals, want) {
+		t.Errorf("Positionals = %v, want %v", args.Positionals, want)
+	}
+}
+
+func TestParseCommandFlagShadowsGlobal(t *testing.T) {
+	args, err := testRegistry().Parse([]string{"init", "-c", "postgres://db", "--conn", "sqlite.db"})
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if got := args.String("conn-string"); got != "sqlite.db" {
+		t.Errorf("conn-string = %q, want sqlite.db", got)
+	}
+	if args.Has("connection") {
+		t.Error("-c on init should not set the global --connection")
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	r := testRegistry()
+
+	tests := [][]string{
+		{"frob"},
+		{"switch", "--bogus"},
+		{"run", "--format"},
+		{"run", "--edit=maybe"},
+	}
+	for _, argv := range tests {
+		if _, err := r.Parse(argv); err == nil {
+			t.Errorf("Parse(%v) expected an error", argv)
+		}
+	}
+}
+
+func TestCheckArgs(t *testing.T) {
+	r := testRegistry()
+
+	tests := []struct {
+		argv    []string
+		wantErr bool
+	}{
+		{[]string{"switch", "dev"}, false},
+		{[]string{"switch"}, true},
+		{[]string{"switch", "dev", "prod"}, true},
+		{[]string{"run", "a", "b", "c"}, false},
+	}
+	for _, tt := range tests {
+		args, err := r.Parse(tt.argv)
+		if err != nil {
+			t.Fatalf("Parse(%v) error = %v", tt.argv, err)
+		}
+		if err := args.CheckArgs(); (err != nil) != tt.wantErr {
+			t.Errorf("CheckArgs(%v) error = %v, wantErr %v", tt.argv, err, tt.wantErr)
+		}
+	}
+}
@@ -4,7 +4,9 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"time"
 
+	"github.com/eduardofuncao/squix/internal/db"
 	"github.com/eduardofuncao/squix/internal/styles"
 	"gopkg.in/yaml.v2"
 )
@@ -12,19 +14,30 @@ import (
 var CfgPath = os.ExpandEnv("$HOME/.config/squix/")
 var CfgFile = filepath.Join(CfgPath, "config.yaml")
 
+// ConnectionEnv names the connection to use in the shell it is set in, like
+// --connection, without changing current_connection
+const ConnectionEnv = "SQUIX_CONNECTION"
+
 type Config struct {
 	CurrentConnection     string                      `yaml:"current_connection"`
 	Connections           map[string]*ConnectionYAML `yaml:"connections"`
+	Queries               map[string]db.Query         `yaml:"queries,omitempty"` // Global library, runs on any connection
 	ColorScheme           string                      `yaml:"color_scheme"`
 	CustomColorScheme     *styles.ColorScheme         `yaml:"custom_colors,omitempty"`
 	History               History                     `yaml:"history"`
 	DefaultRowLimit       int                         `yaml:"default_row_limit"`
 	DefaultColumnWidth    int                         `yaml:"default_column_width"`
+	QueryTimeout          string                      `yaml:"query_timeout,omitempty"`
 	UIVisibility          UIVisibility                `yaml:"ui_visibility"`
+	QueryStorage          string                      `yaml:"query_storage,omitempty"` // yaml (default) or files
+	QueriesDir            string                      `yaml:"queries_dir,omitempty"`
+
+	project   *Project        // .squix.yaml merged in, never written back
+	queryDirs map[string]bool // Connections whose query files were loaded
 }
 
 type History struct {
-	Size int `yaml:"size"`
+	Size int `yaml:"size"` // Entries kept in the history file, -1 disables it
 }
 
 type UIVisibility struct {
@@ -46,9 +59,9 @@ func LoadConfig(path string) (*Config, error) {
 				CurrentConnection:  "",
 				Connections:        make(map[string]*ConnectionYAML),
 				ColorScheme:        "default",
-				History:            History{},
+				History:            History{Size: 1000},
 				DefaultRowLimit:    1000,
-				DefaultColumnWidth: 15,
+				DefaultColumnWidth: 30,
 				UIVisibility: UIVisibility{
 					QueryName:         true,
 					QuerySQL:          true,
@@ -63,6 +76,9 @@ func LoadConfig(path string) (*Config, error) {
 			if err != nil {
 				return nil, err
 			}
+			if err := cfg.loadProject(); err != nil {
+				return nil, err
+			}
 			return cfg, nil
 		}
 		return nil, err
@@ -74,11 +90,14 @@ func LoadConfig(path string) (*Config, error) {
 	}
 
 	if cfg.DefaultColumnWidth == 0 {
-		cfg.DefaultColumnWidth = 15
+		cfg.DefaultColumnWidth = 30
 	}
 	if cfg.DefaultRowLimit == 0 {
 		cfg.DefaultRowLimit = 1000
 	}
+	if cfg.History.Size == 0 {
+		cfg.History.Size = 1000
+	}
 
 	// Set UI visibility defaults (all true by default)
 	if !cfg.UIVisibility.QueryName && !cfg.UIVisibility.QuerySQL &&
@@ -95,18 +114,69 @@ func LoadConfig(path string) (*Config, error) {
 		cfg.UIVisibility.FooterKeymaps = true
 	}
 
+	if cfg.storesQueryFiles() {
+		inYAML := len(cfg.Queries)
+		for _, conn := range cfg.Connections {
+			inYAML += len(conn.Queries)
+		}
+		if err := cfg.loadQueryFiles(); err != nil {
+			return nil, err
+		}
+		// Queries left in config.yaml since switching to files move out now
+		if inYAML > 0 {
+			if err := cfg.Save(); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if err := cfg.loadProject(); err != nil {
+		return nil, err
+	}
+
 	return &cfg, nil
 }
 
+// QueryTimeoutFor returns how long a query on the given connection may run
+// before it is cancelled. The connection's query_timeout takes precedence over
+// the global one, zero means no timeout
+func (c *Config) QueryTimeoutFor(connName string) (time.Duration, error) {
+	timeout := c.QueryTimeout
+	if conn, ok := c.Connections[connName]; ok && conn.QueryTimeout != "" {
+		timeout = conn.QueryTimeout
+	}
+	if timeout == "" {
+		return 0, nil
+	}
+
+	d, err := time.ParseDuration(timeout)
+	if err != nil || d < 0 {
+		return 0, fmt.Errorf("invalid query_timeout '%s' (expected a duration like 30s or 5m)", timeout)
+	}
+	return d, nil
+}
+
 func (c *Config) Save() error {
-	err := os.MkdirAll(CfgPath, 0755)
+	err := os.MkdirAll(filepath.Dir(CfgFile), 0755)
 	if err != nil {
 		return fmt.Errorf("failed to create config directory: %w", err)
 	}
 
-	data, err := yaml.Marshal(c)
+	out := c.withoutProject()
+	if c.storesQueryFiles() {
+		if err := out.saveQueryFiles(); err != nil {
+			return fmt.Errorf("failed to save query files: %w", err)
+		}
+		out = out.withoutQueries()
+	}
+
+	data, err := yaml.Marshal(out)
 	if err != nil {
 		return err
 	}
-	return os.WriteFile(CfgFile, data, 0644)
+	// Connection strings may hold credentials, keep the file private
+	if err := os.WriteFile(CfgFile, data, 0600); err != nil {
+		return err
+	}
+	return os.Chmod(CfgFile, 0600)
 }
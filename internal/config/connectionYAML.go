@@ -7,12 +7,14 @@ import (
 )
 
 type ConnectionYAML struct {
-	Name       string              `yaml:"name"`
-	DBType     string              `yaml:"db_type"`
-	ConnString string              `yaml:"conn_string"`
-	Schema     string              `yaml:"schema,omitempty"`
-	Queries    map[string]db.Query `yaml:"queries"`
-	LastQuery  db.Query               `yaml:"last_query"`
+	Name            string              `yaml:"name"`
+	DBType          string              `yaml:"db_type"`
+	ConnString      string              `yaml:"conn_string"`
+	PasswordCommand string              `yaml:"password_command,omitempty"`
+	Schema          string              `yaml:"schema,omitempty"`
+	QueryTimeout    string              `yaml:"query_timeout,omitempty"`
+	Queries         map[string]db.Query `yaml:"queries"`
+	LastQuery       db.Query            `yaml:"last_query"`
 }
 
 func ToConnectionYAML(conn db.DatabaseConnection) *ConnectionYAML {
@@ -27,7 +29,13 @@ func ToConnectionYAML(conn db.DatabaseConnection) *ConnectionYAML {
 }
 
 func FromConnectionYaml(yc *ConnectionYAML) db.DatabaseConnection {
-	conn, err := db.CreateConnection(yc.Name, yc.DBType, yc.ConnString)
+	// Secrets are resolved here so they only ever live in memory
+	connString, err := ResolveConnString(yc.ConnString, yc.PasswordCommand)
+	if err != nil {
+		log.Fatalf("could not resolve credentials for %s/%s: %v", yc.DBType, yc.Name, err)
+	}
+
+	conn, err := db.CreateConnection(yc.Name, yc.DBType, connString)
 	if err != nil {
 		log.Fatalf("could not create connection from yaml for: %s/%s", yc.DBType, yc.Name)
 	}
@@ -0,0 +1,317 @@
+package config
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/eduardofuncao/squix/internal/db"
+	"gopkg.in/yaml.v2"
+)
+
+// Project file names, looked up in the working directory and its parents
+var ProjectFiles = []string{".squix.yaml", filepath.Join(".squix", "config.yaml")}
+
+// Project is a .squix.yaml found above the working directory. It holds
+// connections and queries meant to be versioned with a repository, so it
+// must not contain secrets
+type Project struct {
+	Path        string                     `yaml:"-"`
+	Connections map[string]*ConnectionYAML `yaml:"connections"`
+	Queries     map[string]db.Query        `yaml:"queries"` // Global queries, for any connection
+
+	// What the project added to the global config, so Save can leave it out
+	supplied        map[string]*ConnectionYAML
+	suppliedQueries map[string]db.Query
+	added           map[string]bool // Connections the global config had no entry for
+}
+
+// FindProjectFile walks up from dir looking for a project file, the way git
+// finds .git
+func FindProjectFile(dir string) (string, bool) {
+	for {
+		for _, name := range ProjectFiles {
+			path := filepath.Join(dir, name)
+			if info, err := os.Stat(path); err == nil && !info.IsDir() {
+				return path, true
+			}
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", false
+		}
+		dir = parent
+	}
+}
+
+func LoadProject(path string) (*Project, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var p Project
+	if err := yaml.Unmarshal(data, &p); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	p.Path = path
+
+	for name, conn := range p.Connections {
+		if conn == nil {
+			conn = &ConnectionYAML{}
+			p.Connections[name] = conn
+		}
+		conn.Name = name
+		if hasInlinePassword(conn.ConnString) {
+			return nil, fmt.Errorf("%s: connection '%s' has a password in conn_string, use ${env:VAR} or ${vault:KEY} instead", path, name)
+		}
+		for queryName, q := range conn.Queries {
+			q.Name = queryName
+			conn.Queries[queryName] = q
+		}
+	}
+	for queryName, q := range p.Queries {
+		q.Name = queryName
+		p.Queries[queryName] = q
+	}
+	return &p, nil
+}
+
+var (
+	// user:pass@tcp(host)/db for MySQL, user/pass@host/service for Oracle
+	userPasswordPattern = regexp.MustCompile(`^[^:/@\s]+[:/][^@/\s]+@`)
+	// password=... in key/value connection strings
+	passwordKeyPattern = regexp.MustCompile(`(?i)(?:^|[\s;?&])(?:password|pwd)\s*=\s*[^\s;&]`)
+)
+
+// hasInlinePassword reports whether a connection string carries a literal
+// password rather than a secret reference, as a URL, a user:pass@ or
+// user/pass@ prefix or a password key
+func hasInlinePassword(connString string) bool {
+	connString = secretRefPattern.ReplaceAllString(connString, "")
+	if passwordKeyPattern.MatchString(connString) {
+		return true
+	}
+	if u, err := url.Parse(connString); err == nil && u.User != nil {
+		password, ok := u.User.Password()
+		return ok && password != ""
+	}
+	return !strings.Contains(connString, "://") && userPasswordPattern.MatchString(connString)
+}
+
+// loadProject merges the project file above the working directory, if any.
+// The global config takes precedence: the project only fills in connection
+// settings left empty there, and queries it doesn't define
+func (c *Config) loadProject() error {
+	dir, err := os.Getwd()
+	if err != nil {
+		return nil
+	}
+	path, ok := FindProjectFile(dir)
+	if !ok {
+		return nil
+	}
+	p, err := LoadProject(path)
+	if err != nil {
+		return err
+	}
+	c.mergeProject(p)
+	return nil
+}
+
+func (c *Config) mergeProject(p *Project) {
+	if c.Connections == nil {
+		c.Connections = make(map[string]*ConnectionYAML)
+	}
+	p.supplied = make(map[string]*ConnectionYAML)
+	p.added = make(map[string]bool)
+
+	names := make([]string, 0, len(p.Connections))
+	for name := range p.Connections {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		pc := p.Connections[name]
+		conn, ok := c.Connections[name]
+		if !ok {
+			conn = &ConnectionYAML{}
+			c.Connections[name] = conn
+			p.added[name] = true
+		}
+		if conn.Queries == nil {
+			conn.Queries = make(map[string]db.Query)
+		}
+
+		supplied := &ConnectionYAML{}
+		fill := func(dst, supplied *string, value string) {
+			if *dst == "" && value != "" {
+				*dst, *supplied = value, value
+			}
+		}
+		fill(&conn.Name, &supplied.Name, name)
+		fill(&conn.DBType, &supplied.DBType, pc.DBType)
+		fill(&conn.ConnString, &supplied.ConnString, pc.ConnString)
+		fill(&conn.PasswordCommand, &supplied.PasswordCommand, pc.PasswordCommand)
+		fill(&conn.Schema, &supplied.Schema, pc.Schema)
+		fill(&conn.QueryTimeout, &supplied.QueryTimeout, pc.QueryTimeout)
+
+		supplied.Queries = mergeQueries(conn.Queries, pc.Queries)
+
+		p.supplied[name] = supplied
+	}
+
+	if c.Queries == nil {
+		c.Queries = make(map[string]db.Query)
+	}
+	p.suppliedQueries = mergeQueries(c.Queries, p.Queries)
+
+	c.project = p
+}
+
+// mergeQueries adds the queries missing from queries, numbered after the
+// ones there, and returns what was added
+func mergeQueries(queries, from map[string]db.Query) map[string]db.Query {
+	names := make([]string, 0, len(from))
+	for name := range from {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	added := make(map[string]db.Query)
+	for _, name := range names {
+		if _, exists := queries[name]; exists {
+			continue
+		}
+		q := from[name]
+		if q.Id == 0 || queryIdTaken(queries, q.Id) {
+			q.Id = GetNextQueryId(queries)
+		}
+		queries[name] = q
+		added[name] = q
+	}
+	return added
+}
+
+func queryIdTaken(queries map[string]db.Query, id int) bool {
+	for _, q := range queries {
+		if q.Id == id {
+			return true
+		}
+	}
+	return false
+}
+
+// Project returns the project file merged into the config, or nil
+func (c *Config) Project() *Project {
+	return c.project
+}
+
+// QuerySource returns the project file a query comes from, or "" when it
+// is saved in the global config
+func (c *Config) QuerySource(connName, queryName string) string {
+	if c.project == nil {
+		return ""
+	}
+	supplied, ok := c.project.supplied[connName]
+	if !ok {
+		return ""
+	}
+	if _, ok := supplied.Queries[queryName]; ok {
+		return c.project.Path
+	}
+	return ""
+}
+
+// GlobalQuerySource returns the project file a global query comes from, or
+// "" when it is saved in the global config
+func (c *Config) GlobalQuerySource(queryName string) string {
+	if c.project == nil {
+		return ""
+	}
+	if _, ok := c.project.suppliedQueries[queryName]; ok {
+		return c.project.Path
+	}
+	return ""
+}
+
+// ConnectionSource returns the project file a connection is defined in, or
+// "" when the global config has its own entry for it
+func (c *Config) ConnectionSource(connName string) string {
+	if c.project == nil {
+		return ""
+	}
+	if supplied, ok := c.project.supplied[connName]; ok && supplied.DBType != "" {
+		return c.project.Path
+	}
+	return ""
+}
+
+// withoutProject is the config as it should be written to the global config
+// file: whatever the project supplied and wasn't changed since is left out
+func (c *Config) withoutProject() *Config {
+	if c.project == nil {
+		return c
+	}
+
+	out := *c
+	out.Queries = withoutSupplied(c.Queries, c.project.suppliedQueries)
+	out.Connections = make(map[string]*ConnectionYAML, len(c.Connections))
+	for name, conn := range c.Connections {
+		supplied, ok := c.project.supplied[name]
+		if !ok {
+			out.Connections[name] = conn
+			continue
+		}
+
+		stripped := *conn
+		unfill := func(field *string, value string) {
+			if value != "" && *field == value {
+				*field = ""
+			}
+		}
+		unfill(&stripped.DBType, supplied.DBType)
+		unfill(&stripped.ConnString, supplied.ConnString)
+		unfill(&stripped.PasswordCommand, supplied.PasswordCommand)
+		unfill(&stripped.Schema, supplied.Schema)
+		unfill(&stripped.QueryTimeout, supplied.QueryTimeout)
+
+		stripped.Queries = withoutSupplied(conn.Queries, supplied.Queries)
+		// The last query run on a project connection isn't kept, it would
+		// leave a stub of the connection in the global config
+		if c.project.added[name] {
+			stripped.LastQuery = db.Query{}
+		}
+
+		// Nothing of the user's own left, the connection lives in the project
+		if stripped.DBType == "" && stripped.ConnString == "" && len(stripped.Queries) == 0 &&
+			reflect.DeepEqual(stripped.LastQuery, db.Query{}) {
+			continue
+		}
+		out.Connections[name] = &stripped
+	}
+	return &out
+}
+
+// withoutSupplied drops the queries that are still as the project supplied them
+func withoutSupplied(queries, supplied map[string]db.Query) map[string]db.Query {
+	out := make(map[string]db.Query, len(queries))
+	for name, q := range queries {
+		if pq, ok := supplied[name]; ok && sameQuery(q, pq) {
+			continue
+		}
+		out[name] = q
+	}
+	return out
+}
+
+// sameQuery compares queries ignoring their id, which the editor renumbers
+func sameQuery(a, b db.Query) bool {
+	a.Id, b.Id = 0, 0
+	return reflect.DeepEqual(a, b)
+}
@@ -0,0 +1,327 @@
+package config
+
+import (
+	"bytes"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/eduardofuncao/squix/internal/db"
+	"gopkg.in/yaml.v2"
+)
+
+// Query storage backends, set with query_storage
+const (
+	QueryStorageYAML  = "yaml"  // Queries inside config.yaml, the default
+	QueryStorageFiles = "files" // One .sql file per query, a directory per connection
+)
+
+// frontMatterFence opens and closes the comment block holding a query's
+// fields at the top of its .sql file
+const frontMatterFence = "-- ---"
+
+type queryFrontMatter struct {
+	Name        string            `yaml:"name,omitempty"`
+	Id          int               `yaml:"id"`
+	Description string            `yaml:"description,omitempty"`
+	Tags        []string          `yaml:"tags,omitempty,flow"`
+	Folder      string            `yaml:"folder,omitempty"`
+	Dialects    []string          `yaml:"dialects,omitempty,flow"`
+	Variants    map[string]string `yaml:"variants,omitempty"`
+	TableName   string            `yaml:"table_name,omitempty"`
+	PrimaryKeys []string          `yaml:"primary_keys,omitempty,flow"`
+	Metadata    map[string]string `yaml:"metadata,omitempty"`
+}
+
+func (c *Config) storesQueryFiles() bool {
+	return c.QueryStorage == QueryStorageFiles
+}
+
+// QueriesPath is the directory holding a directory of .sql files per
+// connection, and the global queries right in it. It is queries_dir or
+// "queries" next to the config file
+func (c *Config) QueriesPath() string {
+	if c.QueriesDir != "" {
+		dir := os.ExpandEnv(c.QueriesDir)
+		if rest, ok := strings.CutPrefix(dir, "~/"); ok {
+			dir = filepath.Join(os.Getenv("HOME"), rest)
+		}
+		return dir
+	}
+	return filepath.Join(filepath.Dir(CfgFile), "queries")
+}
+
+// queryFileName escapes the query name so any name maps to a single file
+func queryFileName(name string) string {
+	return url.PathEscape(name) + ".sql"
+}
+
+// FormatQueryFile renders a query as a .sql file, its fields in a comment
+// front-matter block followed by the SQL as is
+func FormatQueryFile(q db.Query, fileName string) ([]byte, error) {
+	fm := queryFrontMatter{
+		Id:          q.Id,
+		Description: q.Description,
+		Tags:        q.Tags,
+		Folder:      q.Folder,
+		Dialects:    q.Dialects,
+		Variants:    q.Variants,
+		TableName:   q.TableName,
+		PrimaryKeys: q.PrimaryKeys,
+		Metadata:    q.Metadata,
+	}
+	// The name is only spelled out when the file name can't carry it
+	if fileName != queryFileName(q.Name) {
+		fm.Name = q.Name
+	}
+	data, err := yaml.Marshal(fm)
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString(frontMatterFence + "\n")
+	for _, line := range strings.Split(strings.TrimSuffix(string(data), "\n"), "\n") {
+		buf.WriteString("-- " + line + "\n")
+	}
+	buf.WriteString(frontMatterFence + "\n")
+	buf.WriteString(q.SQL)
+	buf.WriteString("\n")
+	return buf.Bytes(), nil
+}
+
+// ParseQueryFile reads a query from a .sql file. Files without front-matter
+// are plain SQL, named after the file and given an id when loaded
+func ParseQueryFile(data []byte, fileName string) (db.Query, error) {
+	name, err := url.PathUnescape(strings.TrimSuffix(fileName, ".sql"))
+	if err != nil {
+		name = strings.TrimSuffix(fileName, ".sql")
+	}
+	q := db.Query{Name: name}
+
+	content := strings.TrimSuffix(string(data), "\n")
+	header, rest, ok := strings.Cut(content, "\n")
+	if !ok || strings.TrimSpace(header) != frontMatterFence {
+		q.SQL = content
+		return q, nil
+	}
+
+	var fmLines []string
+	for {
+		var line string
+		line, rest, ok = strings.Cut(rest, "\n")
+		if strings.TrimSpace(line) == frontMatterFence {
+			break
+		}
+		if !ok {
+			return db.Query{}, fmt.Errorf("front-matter is not closed with '%s'", frontMatterFence)
+		}
+		line = strings.TrimPrefix(line, "--")
+		fmLines = append(fmLines, strings.TrimPrefix(line, " "))
+	}
+
+	var fm queryFrontMatter
+	if err := yaml.Unmarshal([]byte(strings.Join(fmLines, "\n")), &fm); err != nil {
+		return db.Query{}, fmt.Errorf("invalid front-matter: %w", err)
+	}
+	if fm.Name != "" {
+		q.Name = fm.Name
+	}
+	q.Id = fm.Id
+	q.Description = fm.Description
+	q.Tags = fm.Tags
+	q.Folder = fm.Folder
+	q.Dialects = fm.Dialects
+	q.Variants = fm.Variants
+	q.TableName = fm.TableName
+	q.PrimaryKeys = fm.PrimaryKeys
+	q.Metadata = fm.Metadata
+	q.SQL = rest
+	return q, nil
+}
+
+// loadQueryFiles merges the .sql files of every connection into its
+// queries, and the ones in the queries directory itself into the global
+// queries. A file takes precedence over a query of the same name still in
+// config.yaml
+func (c *Config) loadQueryFiles() error {
+	root := c.QueriesPath()
+	c.queryDirs = make(map[string]bool)
+
+	if c.Queries == nil {
+		c.Queries = make(map[string]db.Query)
+	}
+	if _, err := loadQueryDir(root, c.Queries); err != nil {
+		return err
+	}
+
+	for connName, conn := range c.Connections {
+		if conn.Queries == nil {
+			conn.Queries = make(map[string]db.Query)
+		}
+		found, err := loadQueryDir(filepath.Join(root, url.PathEscape(connName)), conn.Queries)
+		if err != nil {
+			return err
+		}
+		if found {
+			c.queryDirs[connName] = true
+		}
+	}
+	return nil
+}
+
+// loadQueryDir reads the .sql files of dir into queries, reporting whether
+// the directory exists
+func loadQueryDir(dir string, queries map[string]db.Query) (bool, error) {
+	entries, err := os.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	var unnumbered []db.Query
+	for _, entry := range entries {
+		if entry.IsDir() || filepath.Ext(entry.Name()) != ".sql" {
+			continue
+		}
+		path := filepath.Join(dir, entry.Name())
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return true, err
+		}
+		q, err := ParseQueryFile(data, entry.Name())
+		if err != nil {
+			return true, fmt.Errorf("%s: %w", path, err)
+		}
+		if q.Id == 0 {
+			unnumbered = append(unnumbered, q)
+			continue
+		}
+		queries[q.Name] = q
+	}
+
+	// Hand written files get ids after the numbered ones
+	sort.Slice(unnumbered, func(i, j int) bool { return unnumbered[i].Name < unnumbered[j].Name })
+	for _, q := range unnumbered {
+		q.Id = GetNextQueryId(queries)
+		queries[q.Name] = q
+	}
+	return true, nil
+}
+
+// saveQueryFiles writes the queries of every connection to its directory,
+// removing the files of queries that no longer exist. Unchanged files are
+// left alone so they keep clean diffs
+func (c *Config) saveQueryFiles() error {
+	root := c.QueriesPath()
+
+	if err := saveQueryDir(root, c.Queries); err != nil {
+		return err
+	}
+
+	for connName, conn := range c.Connections {
+		if len(conn.Queries) > 0 && c.queryDirs != nil {
+			c.queryDirs[connName] = true
+		}
+		if err := saveQueryDir(filepath.Join(root, url.PathEscape(connName)), conn.Queries); err != nil {
+			return err
+		}
+	}
+
+	// Only directories loaded for a connection are touched, queries_dir may
+	// be shared with other files
+	for connName := range c.queryDirs {
+		if _, ok := c.Connections[connName]; ok {
+			continue
+		}
+		if err := removeStaleQueryFiles(filepath.Join(root, url.PathEscape(connName)), nil); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// saveQueryDir writes queries to dir, one file each, and removes the files
+// of queries that no longer exist
+func saveQueryDir(dir string, queries map[string]db.Query) error {
+	written := make(map[string]bool)
+	if len(queries) > 0 {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return fmt.Errorf("failed to create queries directory: %w", err)
+		}
+	}
+	for _, q := range queries {
+		fileName := queryFileName(q.Name)
+		data, err := FormatQueryFile(q, fileName)
+		if err != nil {
+			return err
+		}
+		written[fileName] = true
+
+		path := filepath.Join(dir, fileName)
+		if existing, err := os.ReadFile(path); err == nil && bytes.Equal(existing, data) {
+			continue
+		}
+		if err := os.WriteFile(path, data, 0644); err != nil {
+			return err
+		}
+	}
+	return removeStaleQueryFiles(dir, written)
+}
+
+// removeStaleQueryFiles deletes the .sql files squix wrote in dir that
+// weren't just written, and the directory itself once empty. Other .sql
+// files are left alone, queries_dir may be shared with other files
+func removeStaleQueryFiles(dir string, written map[string]bool) error {
+	entries, err := os.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	for _, entry := range entries {
+		if entry.IsDir() || filepath.Ext(entry.Name()) != ".sql" || written[entry.Name()] {
+			continue
+		}
+		path := filepath.Join(dir, entry.Name())
+		if !isSavedQueryFile(path, entry.Name()) {
+			continue
+		}
+		if err := os.Remove(path); err != nil {
+			return err
+		}
+	}
+	os.Remove(dir) // Fails while other files are left, which is fine
+	return nil
+}
+
+// isSavedQueryFile reports whether the file looks like one squix wrote: it
+// opens with the front-matter fence and is named after its query
+func isSavedQueryFile(path, fileName string) bool {
+	data, err := os.ReadFile(path)
+	if err != nil || !bytes.HasPrefix(data, []byte(frontMatterFence+"\n")) {
+		return false
+	}
+	q, err := ParseQueryFile(data, fileName)
+	return err == nil && queryFileName(q.Name) == fileName
+}
+
+// withoutQueries is the config with the queries left out, for config.yaml
+// when they are stored as files
+func (c *Config) withoutQueries() *Config {
+	out := *c
+	out.Queries = nil
+	out.Connections = make(map[string]*ConnectionYAML, len(c.Connections))
+	for name, conn := range c.Connections {
+		stripped := *conn
+		stripped.Queries = map[string]db.Query{}
+		out.Connections[name] = &stripped
+	}
+	return &out
+}
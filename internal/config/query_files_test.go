@@ -0,0 +1,84 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/eduardofuncao/squix/internal/db"
+)
+
+func TestQueryFileRoundTrip(t *testing.T) {
+	queries := []db.Query{
+		{Name: "users", Id: 1, SQL: "SELECT * FROM users"},
+		{
+			Name:        "orders by/customer",
+			Id:          7,
+			SQL:         "-- latest first\nSELECT *\nFROM orders\nWHERE customer_id = :id\n",
+			Description: "Orders of a customer, newest first",
+			Tags:        []string{"sales", "daily"},
+			Folder:      "reports/customers",
+			TableName:   "orders",
+			PrimaryKeys: []string{"order_id", "line"},
+			Metadata:    map[string]string{"column_widths": "notes=60"},
+		},
+		{Name: "empty", Id: 2},
+	}
+
+	for _, want := range queries {
+		fileName := queryFileName(want.Name)
+		data, err := FormatQueryFile(want, fileName)
+		if err != nil {
+			t.Fatalf("FormatQueryFile(%s) error = %v", want.Name, err)
+		}
+		got, err := ParseQueryFile(data, fileName)
+		if err != nil {
+			t.Fatalf("ParseQueryFile(%s) error = %v\n%s", want.Name, err, data)
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("round trip of %s:\n got %#v\nwant %#v\n%s", want.Name, got, want, data)
+		}
+	}
+}
+
+func TestParseQueryFileWithoutFrontMatter(t *testing.T) {
+	got, err := ParseQueryFile([]byte("SELECT 1\n"), "one.sql")
+	if err != nil {
+		t.Fatalf("ParseQueryFile() error = %v", err)
+	}
+	want := db.Query{Name: "one", SQL: "SELECT 1"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ParseQueryFile() = %#v, want %#v", got, want)
+	}
+
+	if _, err := ParseQueryFile([]byte("-- ---\n-- id: 1\nSELECT 1\n"), "open.sql"); err == nil {
+		t.Error("expected an error for an unclosed front-matter")
+	}
+}
+
+func TestSaveQueryDirKeepsOtherFiles(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"gone.sql":      "-- ---\n-- id: 2\n-- ---\nSELECT 2\n",
+		"migration.sql": "CREATE TABLE users (id int);\n",
+		"renamed.sql":   "-- ---\n-- name: other\n-- id: 3\n-- ---\nSELECT 3\n",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	queries := map[string]db.Query{"kept": {Name: "kept", Id: 1, SQL: "SELECT 1"}}
+	if err := saveQueryDir(dir, queries); err != nil {
+		t.Fatalf("saveQueryDir() error = %v", err)
+	}
+
+	for name, want := range map[string]bool{"kept.sql": true, "gone.sql": false, "migration.sql": true, "renamed.sql": true} {
+		_, err := os.Stat(filepath.Join(dir, name))
+		if exists := err == nil; exists != want {
+			t.Errorf("%s exists = %v, want %v", name, exists, want)
+		}
+	}
+}
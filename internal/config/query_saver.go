@@ -2,6 +2,7 @@ package config
 
 import (
 	"fmt"
+	"maps"
 
 	"github.com/eduardofuncao/squix/internal/db"
 )
@@ -42,6 +43,30 @@ func (c *Config) SaveQueryToConnection(connName string, query db.Query) (db.Quer
 	return query, nil
 }
 
+// UpdateGlobalQuerySQL writes edited SQL back to a global query: to its
+// variant for dbType when it has one, otherwise to its SQL
+func (c *Config) UpdateGlobalQuerySQL(name, dbType, sql string) error {
+	q, ok := c.Queries[name]
+	if !ok {
+		return fmt.Errorf("global query '%s' not found", name)
+	}
+
+	// A copy, the project may share the map it supplied
+	q.Variants = maps.Clone(q.Variants)
+	variant := false
+	for dialect := range q.Variants {
+		if db.CanonicalDBType(dialect) == db.CanonicalDBType(dbType) {
+			q.Variants[dialect] = sql
+			variant = true
+		}
+	}
+	if !variant {
+		q.SQL = sql
+	}
+	c.Queries[name] = q
+	return c.Save()
+}
+
 func (c *Config) UpdateLastQuery(connName string, query db.Query) error {
 	connData := c.Connections[connName]
 	connData.LastQuery = query
@@ -51,8 +76,8 @@ func (c *Config) UpdateLastQuery(connName string, query db.Query) error {
 func (c *Config) SaveQueryAndLast(connName string, query db.Query, saveAsLast bool) error {
 	connData := c.Connections[connName]
 
-	// Save the query (if it has a name and isn't inline)
-	if query.Name != "<inline>" && query.Name != "" && query.SQL != "" {
+	// Save the query (if it has a name and isn't inline or from the global library)
+	if query.Name != "<inline>" && query.Name != "" && query.SQL != "" && query.Id > 0 {
 		connData.Queries[query.Name] = query
 	}
 
@@ -0,0 +1,40 @@
+package config
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/eduardofuncao/squix/internal/db"
+)
+
+func TestUpdateGlobalQuerySQL(t *testing.T) {
+	saved := CfgFile
+	CfgFile = filepath.Join(t.TempDir(), "config.yaml")
+	t.Cleanup(func() { CfgFile = saved })
+
+	cfg := &Config{Queries: map[string]db.Query{
+		"sizes": {
+			Name:     "sizes",
+			SQL:      "SELECT 1",
+			Variants: map[string]string{"postgresql": "SELECT 2"},
+		},
+	}}
+
+	if err := cfg.UpdateGlobalQuerySQL("sizes", "postgres", "SELECT 3"); err != nil {
+		t.Fatalf("UpdateGlobalQuerySQL(postgres) error: %v", err)
+	}
+	if q := cfg.Queries["sizes"]; q.Variants["postgresql"] != "SELECT 3" || q.SQL != "SELECT 1" {
+		t.Errorf("edit on postgres saved as %+v, want the postgresql variant changed", q)
+	}
+
+	if err := cfg.UpdateGlobalQuerySQL("sizes", "sqlite", "SELECT 4"); err != nil {
+		t.Fatalf("UpdateGlobalQuerySQL(sqlite) error: %v", err)
+	}
+	if q := cfg.Queries["sizes"]; q.SQL != "SELECT 4" || q.Variants["postgresql"] != "SELECT 3" {
+		t.Errorf("edit on sqlite saved as %+v, want the SQL changed", q)
+	}
+
+	if err := cfg.UpdateGlobalQuerySQL("missing", "sqlite", "SELECT 5"); err == nil {
+		t.Error("UpdateGlobalQuerySQL() expected an error for an unknown query")
+	}
+}
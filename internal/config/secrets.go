@@ -0,0 +1,108 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"regexp"
+	"runtime"
+	"strings"
+)
+
+// Secret references allowed in conn_string:
+//
+//	${env:VAR}     value of an environment variable
+//	${vault:KEY}   secret stored with squix vault set
+//	${password}    output of the connection's password_command
+var secretRefPattern = regexp.MustCompile(`\$\{(env:[^}]+|vault:[^}]+|password)\}`)
+
+func HasSecretRefs(connString string) bool {
+	return secretRefPattern.MatchString(connString)
+}
+
+// ResolveConnString replaces secret references in the connection string with
+// their values. The result must never be written back to the config file
+func ResolveConnString(connString, passwordCommand string) (string, error) {
+	var resolveErr error
+	var vault map[string]string
+
+	resolved := secretRefPattern.ReplaceAllStringFunc(connString, func(ref string) string {
+		if resolveErr != nil {
+			return ""
+		}
+		ref = strings.TrimSuffix(strings.TrimPrefix(ref, "${"), "}")
+
+		switch {
+		case ref == "password":
+			if passwordCommand == "" {
+				resolveErr = fmt.Errorf("conn_string uses ${password} but no password_command is set")
+				return ""
+			}
+			password, err := runPasswordCommand(passwordCommand)
+			if err != nil {
+				resolveErr = err
+			}
+			return password
+
+		case strings.HasPrefix(ref, "env:"):
+			name := strings.TrimPrefix(ref, "env:")
+			value, ok := os.LookupEnv(name)
+			if !ok {
+				resolveErr = fmt.Errorf("environment variable %s is not set", name)
+			}
+			return value
+
+		default:
+			key := strings.TrimPrefix(ref, "vault:")
+			if vault == nil {
+				var err error
+				if vault, err = unlockVault(); err != nil {
+					resolveErr = err
+					return ""
+				}
+			}
+			value, ok := vault[key]
+			if !ok {
+				resolveErr = fmt.Errorf("secret '%s' not found in vault, add it with: squix vault set %s", key, key)
+			}
+			return value
+		}
+	})
+
+	if resolveErr != nil {
+		return "", resolveErr
+	}
+	return resolved, nil
+}
+
+func unlockVault() (map[string]string, error) {
+	if !VaultExists() {
+		return nil, fmt.Errorf("no vault found, create one with: squix vault set <key>")
+	}
+	passphrase, err := VaultPassphrase("Vault passphrase: ")
+	if err != nil {
+		return nil, err
+	}
+	return LoadVault(passphrase)
+}
+
+func runPasswordCommand(command string) (string, error) {
+	var cmd *exec.Cmd
+	if runtime.GOOS == "windows" {
+		cmd = exec.Command("cmd", "/C", command)
+	} else {
+		cmd = exec.Command("sh", "-c", command)
+	}
+	// Let tools like pass or gpg prompt on the terminal
+	cmd.Stdin = os.Stdin
+	cmd.Stderr = os.Stderr
+
+	out, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("password_command failed: %w", err)
+	}
+
+	// Only the first line counts, pass keeps extra fields below it
+	password, _, _ := strings.Cut(string(out), "\n")
+	return strings.TrimRight(password, "\r"), nil
+}
@@ -0,0 +1,48 @@
+package config
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+// TableCacheFile keeps the table names last listed on each connection, so
+// shell completion can offer them without opening a database connection
+var TableCacheFile = filepath.Join(CfgPath, "tables_cache.json")
+
+// SaveTableCache records the tables of a connection, replacing the previous
+// list. It is best effort, a cache that can't be written is simply stale
+func SaveTableCache(connName string, tables []string) {
+	cache := loadTableCacheFile()
+	names := append([]string{}, tables...)
+	sort.Strings(names)
+	cache[connName] = names
+
+	data, err := json.MarshalIndent(cache, "", "  ")
+	if err != nil {
+		return
+	}
+	if err := os.MkdirAll(CfgPath, 0o755); err != nil {
+		return
+	}
+	os.WriteFile(TableCacheFile, data, 0o644)
+}
+
+// LoadTableCache returns the cached tables of a connection, nil if they were
+// never listed
+func LoadTableCache(connName string) []string {
+	return loadTableCacheFile()[connName]
+}
+
+func loadTableCacheFile() map[string][]string {
+	cache := map[string][]string{}
+	data, err := os.ReadFile(TableCacheFile)
+	if err != nil {
+		return cache
+	}
+	if err := json.Unmarshal(data, &cache); err != nil {
+		return map[string][]string{}
+	}
+	return cache
+}
@@ -0,0 +1,145 @@
+package config
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/charmbracelet/x/term"
+	"golang.org/x/crypto/scrypt"
+)
+
+var VaultFile = filepath.Join(CfgPath, "vault.json")
+
+// VaultPassphraseEnv can hold the vault passphrase for non-interactive use
+const VaultPassphraseEnv = "SQUIX_VAULT_PASSPHRASE"
+
+var ErrWrongPassphrase = errors.New("wrong vault passphrase")
+
+// vaultFile is the on-disk format. The secrets map is encrypted with
+// AES-256-GCM, using a key derived from the passphrase with scrypt
+type vaultFile struct {
+	Salt  []byte `json:"salt"`
+	Nonce []byte `json:"nonce"`
+	Data  []byte `json:"data"`
+}
+
+// Passphrase is cached so a command resolving several secrets only asks once
+var vaultPassphrase string
+
+func VaultExists() bool {
+	_, err := os.Stat(VaultFile)
+	return err == nil
+}
+
+// VaultPassphrase reads the passphrase from $SQUIX_VAULT_PASSPHRASE, or asks
+// for it on the terminal
+func VaultPassphrase(prompt string) (string, error) {
+	if vaultPassphrase != "" {
+		return vaultPassphrase, nil
+	}
+	if env := os.Getenv(VaultPassphraseEnv); env != "" {
+		vaultPassphrase = env
+		return env, nil
+	}
+
+	if !term.IsTerminal(os.Stdin.Fd()) {
+		return "", fmt.Errorf("vault is locked: set $%s or run squix from a terminal", VaultPassphraseEnv)
+	}
+
+	fmt.Fprint(os.Stderr, prompt)
+	passphrase, err := term.ReadPassword(os.Stdin.Fd())
+	fmt.Fprintln(os.Stderr)
+	if err != nil {
+		return "", fmt.Errorf("could not read passphrase: %w", err)
+	}
+	if len(passphrase) == 0 {
+		return "", fmt.Errorf("empty passphrase")
+	}
+
+	vaultPassphrase = string(passphrase)
+	return vaultPassphrase, nil
+}
+
+// LoadVault decrypts the vault. A missing vault is empty
+func LoadVault(passphrase string) (map[string]string, error) {
+	raw, err := os.ReadFile(VaultFile)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return map[string]string{}, nil
+		}
+		return nil, err
+	}
+
+	var vf vaultFile
+	if err := json.Unmarshal(raw, &vf); err != nil {
+		return nil, fmt.Errorf("vault file is corrupted: %w", err)
+	}
+
+	gcm, err := vaultCipher(passphrase, vf.Salt)
+	if err != nil {
+		return nil, err
+	}
+	plain, err := gcm.Open(nil, vf.Nonce, vf.Data, nil)
+	if err != nil {
+		return nil, ErrWrongPassphrase
+	}
+
+	secrets := map[string]string{}
+	if err := json.Unmarshal(plain, &secrets); err != nil {
+		return nil, fmt.Errorf("vault file is corrupted: %w", err)
+	}
+	return secrets, nil
+}
+
+// SaveVault encrypts secrets with a fresh salt and nonce and writes the vault
+func SaveVault(secrets map[string]string, passphrase string) error {
+	plain, err := json.Marshal(secrets)
+	if err != nil {
+		return err
+	}
+
+	salt := make([]byte, 16)
+	if _, err := rand.Read(salt); err != nil {
+		return err
+	}
+	gcm, err := vaultCipher(passphrase, salt)
+	if err != nil {
+		return err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(vaultFile{
+		Salt:  salt,
+		Nonce: nonce,
+		Data:  gcm.Seal(nil, nonce, plain, nil),
+	}, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(VaultFile), 0755); err != nil {
+		return fmt.Errorf("failed to create config directory: %w", err)
+	}
+	return os.WriteFile(VaultFile, data, 0600)
+}
+
+func vaultCipher(passphrase string, salt []byte) (cipher.AEAD, error) {
+	key, err := scrypt.Key([]byte(passphrase), salt, 1<<15, 8, 1, 32)
+	if err != nil {
+		return nil, err
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
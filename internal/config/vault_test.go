@@ -0,0 +1,92 @@
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// useTempVault points the vault at an empty temp dir, with no passphrase
+// cached, for the length of the test
+func useTempVault(t *testing.T) {
+	t.Helper()
+	oldFile, oldPassphrase := VaultFile, vaultPassphrase
+	VaultFile = filepath.Join(t.TempDir(), "vault.json")
+	vaultPassphrase = ""
+	t.Cleanup(func() {
+		VaultFile, vaultPassphrase = oldFile, oldPassphrase
+	})
+}
+
+func TestVaultRoundTrip(t *testing.T) {
+	useTempVault(t)
+
+	secrets, err := LoadVault("hunter2")
+	if err != nil || len(secrets) != 0 {
+		t.Fatalf("LoadVault() without a vault = %v, %v, want an empty vault", secrets, err)
+	}
+
+	want := map[string]string{"prod_db": "s3cr3t", "empty": ""}
+	if err := SaveVault(want, "hunter2"); err != nil {
+		t.Fatalf("SaveVault() error: %v", err)
+	}
+	if info, err := os.Stat(VaultFile); err != nil || info.Mode().Perm() != 0600 {
+		t.Errorf("vault file mode = %v, %v, want 0600", info.Mode().Perm(), err)
+	}
+
+	got, err := LoadVault("hunter2")
+	if err != nil {
+		t.Fatalf("LoadVault() error: %v", err)
+	}
+	if len(got) != len(want) || got["prod_db"] != "s3cr3t" || got["empty"] != "" {
+		t.Errorf("LoadVault() = %v, want %v", got, want)
+	}
+}
+
+func TestVaultWrongPassphrase(t *testing.T) {
+	useTempVault(t)
+
+	if err := SaveVault(map[string]string{"key": "value"}, "hunter2"); err != nil {
+		t.Fatalf("SaveVault() error: %v", err)
+	}
+	if _, err := LoadVault("hunter3"); !errors.Is(err, ErrWrongPassphrase) {
+		t.Errorf("LoadVault() with a wrong passphrase error = %v, want %v", err, ErrWrongPassphrase)
+	}
+}
+
+func TestVaultCorrupted(t *testing.T) {
+	useTempVault(t)
+
+	if err := SaveVault(map[string]string{"key": "value"}, "hunter2"); err != nil {
+		t.Fatalf("SaveVault() error: %v", err)
+	}
+	raw, err := os.ReadFile(VaultFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Flipped ciphertext fails authentication like a wrong passphrase does
+	var tampered vaultFile
+	if err := json.Unmarshal(raw, &tampered); err != nil {
+		t.Fatal(err)
+	}
+	tampered.Data[0] ^= 0xff
+	if raw, err = json.Marshal(tampered); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(VaultFile, raw, 0600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadVault("hunter2"); !errors.Is(err, ErrWrongPassphrase) {
+		t.Errorf("LoadVault() with tampered data error = %v, want %v", err, ErrWrongPassphrase)
+	}
+
+	if err := os.WriteFile(VaultFile, []byte("{not json"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadVault("hunter2"); err == nil || errors.Is(err, ErrWrongPassphrase) {
+		t.Errorf("LoadVault() with a truncated file error = %v, want a corruption error", err)
+	}
+}
@@ -1,10 +1,12 @@
 package db
 
 import (
+	"context"
 	"database/sql"
 	"errors"
 	"fmt"
 	"regexp"
+	"slices"
 	"strings"
 )
 
@@ -29,13 +31,17 @@ func (b *BaseConnection) Close() error {
 func (b *BaseConnection) Query(name string, args ...any) (any, error) {
 	return struct{}{}, errors.New("Close() not implemented for base connection")
 }
-func (b *BaseConnection) ExecQuery(sql string, args ...any) (*sql.Rows, error) {
+func (b *BaseConnection) ExecQuery(ctx context.Context, sql string, args ...any) (*sql.Rows, error) {
 	return nil, errors.New("ExecQuery() not implemented for base connection")
 }
-func (b *BaseConnection) Exec(sql string, args ...any) error {
+func (b *BaseConnection) Exec(ctx context.Context, sql string, args ...any) error {
 	return errors.New("Exec() not implemented for base connection")
 }
 
+func (b *BaseConnection) BeginTx(ctx context.Context) (*sql.Tx, error) {
+	return nil, errors.New("BeginTx() not implemented for base connection")
+}
+
 func (b *BaseConnection) GetTableMetadata(
 	tableName string,
 ) (*TableMetadata, error) {
@@ -64,39 +70,68 @@ func (b *BaseConnection) GetForeignKeysReferencingTable(tableName string) ([]For
 	return []ForeignKey{}, errors.New("GetForeignKeysReferencingTable() not implemented for base connection")
 }
 
+// PrimaryKeyWhere matches a row on every column of its primary key, for the
+// WHERE clause of generated statements. Values are written as literals of
+// the key column types, so they read the same as the statement that runs
+func PrimaryKeyWhere(pkColumns, pkValues, pkTypes []string, dbType string) string {
+	conditions := make([]string, len(pkColumns))
+	for i, col := range pkColumns {
+		value, columnType := "", ""
+		if i < len(pkValues) {
+			value = pkValues[i]
+		}
+		if i < len(pkTypes) {
+			columnType = pkTypes[i]
+		}
+		conditions[i] = QuoteIdentifier(col, dbType) + " = " + TypedLiteral(TextCell(value), columnType, dbType)
+	}
+	return strings.Join(conditions, " AND ")
+}
+
+// hasPrimaryKeyValues tells whether there is a value for every key column
+func hasPrimaryKeyValues(pkColumns, pkValues []string) bool {
+	return len(pkColumns) > 0 && len(pkValues) == len(pkColumns) && !slices.Contains(pkValues, "")
+}
+
 func (b *BaseConnection) BuildUpdateStatement(
-	tableName, columnName, currentValue, pkColumn, pkValue string,
+	tableName, columnName string, currentValue Cell, pkColumns, pkValues, pkTypes []string,
 ) string {
-	escapedValue := strings.ReplaceAll(currentValue, "'", "''")
+	value := CellLiteral(currentValue)
 
-	if pkColumn != "" && pkValue != "" {
-		escapedPkValue := strings.ReplaceAll(pkValue, "'", "''")
+	if hasPrimaryKeyValues(pkColumns, pkValues) {
 		return fmt.Sprintf(
-			"UPDATE %s\nSET %s = '%s'\nWHERE %s = '%s';",
+			"UPDATE %s\nSET %s = %s\nWHERE %s;",
 			tableName,
 			columnName,
-			escapedValue,
-			pkColumn,
-			escapedPkValue,
+			value,
+			PrimaryKeyWhere(pkColumns, pkValues, pkTypes, b.GetDbType()),
 		)
 	}
 
 	return fmt.Sprintf(
-		"-- No primary key specified. Edit WHERE clause manually.\nUPDATE %s\nSET %s = '%s'\nWHERE <condition>;",
+		"-- No primary key specified. Edit WHERE clause manually.\nUPDATE %s\nSET %s = %s\nWHERE <condition>;",
 		tableName,
 		columnName,
-		escapedValue,
+		value,
 	)
 }
 
-func (b *BaseConnection) BuildDeleteStatement(tableName, primaryKeyCol, pkValue string) string {
-	escapedPkValue := strings.ReplaceAll(pkValue, "'", "''")
+func (b *BaseConnection) BuildDeleteStatement(tableName string, pkColumns, pkValues, pkTypes []string) string {
+	return fmt.Sprintf(
+		"DELETE FROM %s\nWHERE %s;",
+		tableName,
+		PrimaryKeyWhere(pkColumns, pkValues, pkTypes, b.GetDbType()),
+	)
+}
 
+// BuildInsertStatement takes the values as SQL expressions, literals already
+// quoted
+func (b *BaseConnection) BuildInsertStatement(tableName string, columns, values []string) string {
 	return fmt.Sprintf(
-		"DELETE FROM %s\nWHERE %s = '%s';",
+		"INSERT INTO %s (%s)\nVALUES (%s);",
 		tableName,
-		primaryKeyCol,
-		escapedPkValue,
+		strings.Join(columns, ", "),
+		strings.Join(values, ", "),
 	)
 }
 
@@ -0,0 +1,361 @@
+package db
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Edit statements open in the editor with plain SQL literals, so they read
+// like any other statement. Before running, the literals assigned to or
+// compared with a column, or inserted into one, become placeholders, with
+// arguments typed after the column, and the driver takes care of quoting them
+
+const identifierSyntax = `(?:[\w$#]+|"[^"]+"|\[[^\]]+\]|` + "`[^`]+`" + `)(?:\.(?:[\w$#]+|"[^"]+"|\[[^\]]+\]|` + "`[^`]+`" + `))*`
+
+const literalSyntax = `N?'(?:[^']|'')*'|NULL|[-+]?\d+(?:\.\d+)?(?:[eE][-+]?\d+)?`
+
+var (
+	bindItemPattern = regexp.MustCompile(
+		`(?is)^(\s*)(` + identifierSyntax + `)(\s*=\s*)(` + literalSyntax + `)(\s*;?\s*)$`)
+	assignmentPattern = regexp.MustCompile(`(?s)^\s*(` + identifierSyntax + `)\s*=`)
+	insertPattern     = regexp.MustCompile(
+		`(?is)^(\s*INSERT\s+INTO\s+[^(]+?\s*\()(.*?)(\)\s*(?:OVERRIDING\s+\w+\s+VALUE\s+)?VALUES\s*\()(.*)(\)\s*;?\s*)$`)
+	literalPattern = regexp.MustCompile(`(?is)^(?:` + literalSyntax + `)$`)
+	numberPattern  = regexp.MustCompile(`^[-+]?\d+(?:\.\d+)?(?:[eE][-+]?\d+)?$`)
+)
+
+// BindLiterals replaces the literals of every "column = literal" in the SET
+// and WHERE clauses of statement, or in the VALUES of an INSERT, with
+// placeholders, returning the arguments in placeholder order. columnTypes
+// maps lowercase column names to their database type. Anything else, like
+// expressions or OR conditions, is left as written
+func BindLiterals(statement string, columnTypes map[string]string, placeholder func(index int) string) (string, []any) {
+	if match := insertPattern.FindStringSubmatch(statement); match != nil {
+		return bindInsert(match, columnTypes, placeholder)
+	}
+
+	var b strings.Builder
+	var args []any
+
+	for _, part := range splitClauses(statement) {
+		match := bindItemPattern.FindStringSubmatch(part)
+		if match == nil {
+			b.WriteString(part)
+			continue
+		}
+
+		column := unquoteIdentifier(match[2])
+		args = append(args, TypedValue(literalValue(match[4]), columnTypes[strings.ToLower(column)]))
+		b.WriteString(match[1] + match[2] + match[3] + placeholder(len(args)) + match[5])
+	}
+	return b.String(), args
+}
+
+// AssignedColumns returns the columns an UPDATE sets, from its SET clause or
+// the UPDATE clause of a ClickHouse mutation
+func AssignedColumns(statement string) []string {
+	var columns []string
+	inSet := false
+	for _, part := range splitClauses(statement) {
+		switch strings.ToUpper(part) {
+		case "SET", "UPDATE":
+			inSet = true
+			continue
+		case "WHERE":
+			inSet = false
+			continue
+		}
+		if !inSet {
+			continue
+		}
+		if match := assignmentPattern.FindStringSubmatch(part); match != nil {
+			columns = append(columns, unquoteIdentifier(match[1]))
+		}
+	}
+	return columns
+}
+
+// bindInsert binds the VALUES of an INSERT matched by insertPattern, each
+// typed after the column in the same position of the column list
+func bindInsert(match []string, columnTypes map[string]string, placeholder func(index int) string) (string, []any) {
+	columns := splitClauses(match[2])
+	values := splitClauses(match[4])
+	if len(columns) != len(values) {
+		return match[0], nil
+	}
+
+	var args []any
+	// Items and the commas between them alternate
+	for i := 0; i < len(values); i += 2 {
+		literal := strings.TrimSpace(values[i])
+		if !literalPattern.MatchString(literal) {
+			continue
+		}
+		column := unquoteIdentifier(strings.TrimSpace(columns[i]))
+		args = append(args, TypedValue(literalValue(literal), columnTypes[strings.ToLower(column)]))
+		values[i] = strings.Replace(values[i], literal, placeholder(len(args)), 1)
+	}
+	return match[1] + match[2] + match[3] + strings.Join(values, "") + match[5], args
+}
+
+// literalValue reads a SQL literal, nil for NULL
+func literalValue(literal string) any {
+	switch {
+	case strings.EqualFold(literal, "NULL"):
+		return nil
+	case strings.HasPrefix(literal, "N'"), strings.HasPrefix(literal, "n'"):
+		literal = literal[1:]
+	}
+	if strings.HasPrefix(literal, "'") {
+		return strings.ReplaceAll(literal[1:len(literal)-1], "''", "'")
+	}
+	return literal
+}
+
+// TypedValue converts a value as shown in the table to the Go type drivers
+// take for a column of columnType. Values that don't parse stay strings, for
+// the database to convert or reject
+func TypedValue(value any, columnType string) any {
+	s, ok := value.(string)
+	if !ok {
+		return value
+	}
+
+	switch columnKind(columnType) {
+	case kindInteger:
+		if n, err := strconv.ParseInt(s, 10, 64); err == nil {
+			return n
+		}
+	case kindFloat:
+		if f, err := strconv.ParseFloat(s, 64); err == nil {
+			return f
+		}
+	case kindBool:
+		if b, err := strconv.ParseBool(s); err == nil {
+			return b
+		}
+	case kindBinary:
+		return []byte(s)
+	case kindTime:
+		if t, ok := parseTime(s); ok {
+			return t
+		}
+	}
+	return s
+}
+
+type valueKind int
+
+const (
+	kindText valueKind = iota
+	kindInteger
+	kindFloat
+	kindBool
+	kindBinary
+	kindTime
+	kindDecimal // Bound as text, so no precision is lost
+)
+
+// columnKind sorts the type names drivers report into the kinds of values
+// they take. Sizes, UNSIGNED and ClickHouse's Nullable() don't matter here
+func columnKind(columnType string) valueKind {
+	t := strings.ToUpper(strings.TrimSpace(columnType))
+	for _, wrapper := range []string{"NULLABLE(", "LOWCARDINALITY("} {
+		if strings.HasPrefix(t, wrapper) {
+			t = strings.TrimSuffix(strings.TrimPrefix(t, wrapper), ")")
+		}
+	}
+	if i := strings.Index(t, "("); i >= 0 {
+		t = t[:i]
+	}
+	t = strings.TrimSpace(strings.TrimSuffix(t, " UNSIGNED"))
+
+	switch t {
+	case "INT", "INTEGER", "SMALLINT", "BIGINT", "TINYINT", "MEDIUMINT", "INT2", "INT4", "INT8",
+		"SERIAL", "BIGSERIAL", "SMALLSERIAL", "INT16", "INT32", "INT64", "UINT8", "UINT16", "UINT32",
+		"UINT64", "UTINYINT", "USMALLINT", "UINTEGER", "UBIGINT":
+		return kindInteger
+	case "FLOAT", "FLOAT4", "FLOAT8", "FLOAT32", "FLOAT64", "DOUBLE", "DOUBLE PRECISION", "REAL",
+		"BINARY_FLOAT", "BINARY_DOUBLE":
+		return kindFloat
+	case "NUMERIC", "DECIMAL", "DEC", "NUMBER":
+		return kindDecimal
+	case "BOOL", "BOOLEAN":
+		return kindBool
+	case "BYTEA", "BLOB", "TINYBLOB", "MEDIUMBLOB", "LONGBLOB", "BINARY", "VARBINARY", "IMAGE",
+		"RAW", "LONG RAW":
+		return kindBinary
+	case "DATE", "DATE32", "DATETIME", "DATETIME2", "DATETIME64", "SMALLDATETIME", "DATETIMEOFFSET",
+		"TIMESTAMP", "TIMESTAMPTZ", "TIMESTAMP WITH TIME ZONE", "TIMESTAMP WITHOUT TIME ZONE":
+		return kindTime
+	}
+	return kindText
+}
+
+// timeLayouts are the ways dates show in the table: as Go prints a
+// time.Time, and as the databases that return them as text write them
+var timeLayouts = []string{
+	"2006-01-02 15:04:05.999999999 -0700 MST",
+	time.RFC3339Nano,
+	"2006-01-02 15:04:05.999999999Z07:00",
+	"2006-01-02 15:04:05.999999999",
+	"2006-01-02T15:04:05.999999999",
+	"2006-01-02",
+}
+
+func parseTime(s string) (time.Time, bool) {
+	for _, layout := range timeLayouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// DriverArgs adapts typed arguments to what the driver of dbType binds.
+// Oracle has no boolean in SQL before 23ai, so booleans go as 1 and 0
+func DriverArgs(args []any, dbType string) []any {
+	if CanonicalDBType(dbType) != "oracle" {
+		return args
+	}
+	adapted := make([]any, len(args))
+	for i, arg := range args {
+		if b, ok := arg.(bool); ok {
+			arg = int64(0)
+			if b {
+				arg = int64(1)
+			}
+		}
+		adapted[i] = arg
+	}
+	return adapted
+}
+
+// CellLiteral writes a cell as a SQL literal, with NULL bare
+func CellLiteral(value Cell) string {
+	if value.Null {
+		return "NULL"
+	}
+	return "'" + strings.ReplaceAll(value.Text, "'", "''") + "'"
+}
+
+// TypedLiteral writes a cell as a SQL literal after the type of its column:
+// numbers bare, booleans the way dbType writes them, anything else quoted
+func TypedLiteral(value Cell, columnType, dbType string) string {
+	if value.Null {
+		return "NULL"
+	}
+	switch columnKind(columnType) {
+	case kindInteger, kindFloat, kindDecimal:
+		if numberPattern.MatchString(value.Text) {
+			return value.Text
+		}
+	case kindBool:
+		if b, err := strconv.ParseBool(value.Text); err == nil {
+			switch CanonicalDBType(dbType) {
+			case "oracle", "sqlserver":
+				if b {
+					return "1"
+				}
+				return "0"
+			}
+			return strings.ToUpper(strconv.FormatBool(b))
+		}
+	}
+	return CellLiteral(value)
+}
+
+// QuoteIdentifier quotes a table or column name the way dbType does, so
+// reserved words, spaces and mixed case work
+func QuoteIdentifier(name, dbType string) string {
+	switch CanonicalDBType(dbType) {
+	case "mysql":
+		return "`" + strings.ReplaceAll(name, "`", "``") + "`"
+	case "clickhouse":
+		return "`" + strings.ReplaceAll(name, "`", "\\`") + "`"
+	case "sqlserver":
+		return quoteSQLServerIdentifier(name)
+	}
+	return `"` + strings.ReplaceAll(name, `"`, `""`) + `"`
+}
+
+// QuoteTableName quotes every part of a possibly schema qualified table
+// name. Table names come from the query as written, in lowercase, so they
+// are set in uppercase for the databases that fold unquoted names that way
+func QuoteTableName(name, dbType string) string {
+	parts := strings.Split(name, ".")
+	for i, part := range parts {
+		if part == "" || strings.ContainsAny(part[:1], "\"[`") {
+			continue
+		}
+		switch CanonicalDBType(dbType) {
+		case "oracle", "firebird":
+			part = strings.ToUpper(part)
+		}
+		parts[i] = QuoteIdentifier(part, dbType)
+	}
+	return strings.Join(parts, ".")
+}
+
+func unquoteIdentifier(identifier string) string {
+	if i := strings.LastIndex(identifier, "."); i >= 0 {
+		identifier = identifier[i+1:]
+	}
+	return strings.Trim(identifier, "\"`[]")
+}
+
+// splitClauses cuts a statement into its keywords (SET, UPDATE, WHERE, AND),
+// commas and what is between them. Quotes and parentheses are kept whole, so
+// joining the parts back gives the statement unchanged
+func splitClauses(statement string) []string {
+	var parts []string
+	start := 0
+	var quote byte
+	depth := 0
+
+	for i := 0; i < len(statement); i++ {
+		c := statement[i]
+		switch {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+			continue
+		case c == '\'' || c == '"' || c == '`':
+			quote = c
+			continue
+		case c == '(':
+			depth++
+			continue
+		case c == ')':
+			depth--
+			continue
+		case depth > 0:
+			continue
+		}
+
+		length := 0
+		if c == ',' {
+			length = 1
+		} else if i == 0 || !isWordByte(statement[i-1]) {
+			for _, keyword := range []string{"SET", "UPDATE", "WHERE", "AND"} {
+				end := i + len(keyword)
+				if end <= len(statement) && strings.EqualFold(statement[i:end], keyword) &&
+					(end == len(statement) || !isWordByte(statement[end])) {
+					length = len(keyword)
+					break
+				}
+			}
+		}
+		if length == 0 {
+			continue
+		}
+
+		parts = append(parts, statement[start:i], statement[i:i+length])
+		start = i + length
+		i += length - 1
+	}
+	return append(parts, statement[start:])
+}
@@ -0,0 +1,141 @@
+package db
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestBindLiterals(t *testing.T) {
+	columnTypes := map[string]string{
+		"id":      "INT4",
+		"name":    "TEXT",
+		"active":  "BOOL",
+		"born":    "DATE",
+		"payload": "BYTEA",
+	}
+	placeholder := func(i int) string { return fmt.Sprintf("$%d", i) }
+
+	tests := []struct {
+		name      string
+		statement string
+		want      string
+		args      []any
+	}{
+		{
+			name:      "Update by primary key",
+			statement: "UPDATE users\nSET name = 'O''Brien'\nWHERE id = '7'",
+			want:      "UPDATE users\nSET name = $1\nWHERE id = $2",
+			args:      []any{"O'Brien", int64(7)},
+		},
+		{
+			name:      "NULL and the string NULL",
+			statement: "UPDATE users SET name = NULL, active = 'false' WHERE id = 1 AND name = 'NULL'",
+			want:      "UPDATE users SET name = $1, active = $2 WHERE id = $3 AND name = $4",
+			args:      []any{nil, false, int64(1), "NULL"},
+		},
+		{
+			name:      "Dates and binary",
+			statement: `UPDATE "public"."users" SET born = '2024-03-01', payload = 'raw' WHERE "id" = '1'`,
+			want:      `UPDATE "public"."users" SET born = $1, payload = $2 WHERE "id" = $3`,
+			args:      []any{time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC), []byte("raw"), int64(1)},
+		},
+		{
+			name:      "Expressions and keywords inside quotes left alone",
+			statement: "UPDATE users SET name = upper(name) WHERE name = 'a, b AND c = ''d''' OR id = 2",
+			want:      "UPDATE users SET name = upper(name) WHERE name = 'a, b AND c = ''d''' OR id = 2",
+			args:      nil,
+		},
+		{
+			name:      "ClickHouse mutation",
+			statement: "ALTER TABLE users DELETE WHERE id = '3'",
+			want:      "ALTER TABLE users DELETE WHERE id = $1",
+			args:      []any{int64(3)},
+		},
+		{
+			name:      "Insert values typed by column position",
+			statement: "INSERT INTO users (id, \"name\", born, active)\nOVERRIDING SYSTEM VALUE\nVALUES ('4', 'a, b', now(), NULL);",
+			want:      "INSERT INTO users (id, \"name\", born, active)\nOVERRIDING SYSTEM VALUE\nVALUES ($1, $2, now(), $3);",
+			args:      []any{int64(4), "a, b", nil},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, args := BindLiterals(tt.statement, columnTypes, placeholder)
+			if got != tt.want {
+				t.Errorf("BindLiterals() statement = %q, want %q", got, tt.want)
+			}
+			if !reflect.DeepEqual(args, tt.args) {
+				t.Errorf("BindLiterals() args = %#v, want %#v", args, tt.args)
+			}
+		})
+	}
+}
+
+func TestDriverArgs(t *testing.T) {
+	args := []any{true, false, int64(3), "x", nil}
+
+	if got := DriverArgs(args, "postgres"); !reflect.DeepEqual(got, args) {
+		t.Errorf("DriverArgs(postgres) = %#v, want %#v", got, args)
+	}
+	want := []any{int64(1), int64(0), int64(3), "x", nil}
+	if got := DriverArgs(args, "oracle"); !reflect.DeepEqual(got, want) {
+		t.Errorf("DriverArgs(oracle) = %#v, want %#v", got, want)
+	}
+}
+
+func TestTypedLiteral(t *testing.T) {
+	tests := []struct {
+		value      Cell
+		columnType string
+		dbType     string
+		want       string
+	}{
+		{value: TextCell("42"), columnType: "INT8", dbType: "postgres", want: "42"},
+		{value: TextCell("1.5e3"), columnType: "FLOAT8", dbType: "postgres", want: "1.5e3"},
+		{value: TextCell("NaN"), columnType: "FLOAT8", dbType: "postgres", want: "'NaN'"},
+		{value: TextCell("007"), columnType: "VARCHAR(10)", dbType: "postgres", want: "'007'"},
+		{value: TextCell("12.30"), columnType: "NUMBER(10,2)", dbType: "oracle", want: "12.30"},
+		{value: TextCell("t"), columnType: "BOOL", dbType: "postgres", want: "TRUE"},
+		{value: TextCell("false"), columnType: "BOOLEAN", dbType: "oracle", want: "0"},
+		{value: NullCell, columnType: "INT", dbType: "mysql", want: "NULL"},
+	}
+
+	for _, tt := range tests {
+		if got := TypedLiteral(tt.value, tt.columnType, tt.dbType); got != tt.want {
+			t.Errorf("TypedLiteral(%v, %s, %s) = %s, want %s", tt.value, tt.columnType, tt.dbType, got, tt.want)
+		}
+	}
+}
+
+func TestAssignedColumns(t *testing.T) {
+	tests := map[string][]string{
+		"UPDATE users\nSET name = 'a, b', \"Age\" = age + 1\nWHERE id = 7": {"name", "Age"},
+		"ALTER TABLE users UPDATE [note] = NULL WHERE id = 7":              {"note"},
+		"DELETE FROM users WHERE id = 7":                                   nil,
+	}
+	for statement, want := range tests {
+		if got := AssignedColumns(statement); !reflect.DeepEqual(got, want) {
+			t.Errorf("AssignedColumns(%q) = %q, want %q", statement, got, want)
+		}
+	}
+}
+
+func TestPrimaryKeyWhere(t *testing.T) {
+	tests := []struct {
+		dbType string
+		want   string
+	}{
+		{dbType: "postgres", want: `"order_id" = 7 AND "code" = 'O''B'`},
+		{dbType: "mysql", want: "`order_id` = 7 AND `code` = 'O''B'"},
+		{dbType: "sqlserver", want: "[order_id] = 7 AND [code] = 'O''B'"},
+	}
+	for _, tt := range tests {
+		got := PrimaryKeyWhere([]string{"order_id", "code"}, []string{"7", "O'B"}, []string{"INT", "VARCHAR"}, tt.dbType)
+		if got != tt.want {
+			t.Errorf("PrimaryKeyWhere(%s) = %s, want %s", tt.dbType, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,52 @@
+package db
+
+import "encoding/json"
+
+// Cell is a value of a result set as text. Null tells SQL NULL apart from
+// text that happens to read "NULL"
+type Cell struct {
+	Text string
+	Null bool
+}
+
+// NullCell is SQL NULL
+var NullCell = Cell{Null: true}
+
+// TextCell is a value that isn't NULL
+func TextCell(text string) Cell {
+	return Cell{Text: text}
+}
+
+// String is the cell as shown in the table, NULL for SQL NULL
+func (c Cell) String() string {
+	if c.Null {
+		return "NULL"
+	}
+	return c.Text
+}
+
+// MarshalJSON writes SQL NULL as null and anything else as a string
+func (c Cell) MarshalJSON() ([]byte, error) {
+	if c.Null {
+		return []byte("null"), nil
+	}
+	return json.Marshal(c.Text)
+}
+
+func (c *Cell) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		*c = NullCell
+		return nil
+	}
+	*c = Cell{}
+	return json.Unmarshal(data, &c.Text)
+}
+
+// CellStrings is a row as shown in the table
+func CellStrings(row []Cell) []string {
+	values := make([]string, len(row))
+	for i, cell := range row {
+		values[i] = cell.String()
+	}
+	return values
+}
@@ -1,6 +1,7 @@
 package db
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
 	"net/url"
@@ -76,15 +77,20 @@ func (c *ClickHouseConnection) Query(queryName string, args ...any) (any, error)
 	return c.db.Query(query.SQL, args...)
 }
 
-func (c *ClickHouseConnection) ExecQuery(sql string, args ...any) (*sql.Rows, error) {
-	return c.db.Query(sql, args...)
+func (c *ClickHouseConnection) ExecQuery(ctx context.Context, sql string, args ...any) (*sql.Rows, error) {
+	return c.db.QueryContext(ctx, sql, args...)
 }
 
-func (c *ClickHouseConnection) Exec(sql string, args ...any) error {
-	_, err := c.db.Exec(sql, args...)
+func (c *ClickHouseConnection) Exec(ctx context.Context, sql string, args ...any) error {
+	_, err := c.db.ExecContext(ctx, sql, args...)
 	return err
 }
 
+func (c *ClickHouseConnection) BeginTx(ctx context.Context) (*sql.Tx, error) {
+	// Mutations apply right away, the driver's transactions only batch inserts
+	return nil, ErrNoTransactions
+}
+
 func (c *ClickHouseConnection) GetTableMetadata(tableName string) (*TableMetadata, error) {
 	if c.db == nil {
 		return nil, fmt.Errorf("database is not open")
@@ -106,11 +112,10 @@ func (c *ClickHouseConnection) GetTableMetadata(tableName string) (*TableMetadat
 	row := c.db.QueryRow(pkQuery, tableName)
 	var primaryKey string
 	if err := row.Scan(&primaryKey); err == nil && primaryKey != "" {
-		// Extract first column if comma-separated (for composite PKs)
-		keys := strings.Split(primaryKey, ",")
-		if len(keys) > 0 {
+		// Composite keys are comma-separated
+		for key := range strings.SplitSeq(primaryKey, ",") {
 			// Remove quotes and trim whitespace
-			pk := strings.TrimSpace(keys[0])
+			pk := strings.TrimSpace(key)
 			pk = strings.Trim(pk, "`\"'")
 			metadata.PrimaryKeys = append(metadata.PrimaryKeys, pk)
 		}
@@ -118,7 +123,7 @@ func (c *ClickHouseConnection) GetTableMetadata(tableName string) (*TableMetadat
 
 	// Query for column metadata
 	colQuery := `
-		SELECT name, type
+		SELECT name, type, default_kind, default_expression
 		FROM system.columns
 		WHERE table = ?
 		  AND database = currentDatabase()
@@ -132,12 +137,24 @@ func (c *ClickHouseConnection) GetTableMetadata(tableName string) (*TableMetadat
 	defer colRows.Close()
 
 	for colRows.Next() {
-		var colName, colType string
-		if err := colRows.Scan(&colName, &colType); err != nil {
+		var colName, colType, defaultKind, defaultExpr string
+		if err := colRows.Scan(&colName, &colType, &defaultKind, &defaultExpr); err != nil {
 			continue
 		}
 		metadata.Columns = append(metadata.Columns, colName)
 		metadata.ColumnTypes = append(metadata.ColumnTypes, colType)
+
+		// MATERIALIZED and ALIAS columns can't be inserted into
+		switch defaultKind {
+		case "DEFAULT":
+			metadata.ColumnDefaults = append(metadata.ColumnDefaults, defaultExpr)
+		case "MATERIALIZED", "ALIAS":
+			metadata.ColumnDefaults = append(metadata.ColumnDefaults, "")
+			metadata.GeneratedColumns = append(metadata.GeneratedColumns, colName)
+			metadata.ComputedColumns = append(metadata.ComputedColumns, colName)
+		default:
+			metadata.ColumnDefaults = append(metadata.ColumnDefaults, "")
+		}
 	}
 
 	metadata.ForeignKeys = []ForeignKey{}
@@ -250,21 +267,19 @@ func (c *ClickHouseConnection) GetViews() ([]string, error) {
 	return views, nil
 }
 
-func (c *ClickHouseConnection) BuildUpdateStatement(tableName, columnName, currentValue, pkColumn, pkValue string) string {
-	escapedValue := strings.ReplaceAll(currentValue, "'", "''")
+func (c *ClickHouseConnection) BuildUpdateStatement(tableName, columnName string, currentValue Cell, pkColumns, pkValues, pkTypes []string) string {
+	value := CellLiteral(currentValue)
 
-	if pkColumn != "" && pkValue != "" {
-		escapedPkValue := strings.ReplaceAll(pkValue, "'", "''")
+	if hasPrimaryKeyValues(pkColumns, pkValues) {
 		return fmt.Sprintf(`-- ClickHouse UPDATE statement
 -- Note: ClickHouse uses ALTER TABLE UPDATE for mutations
 ALTER TABLE %s
-UPDATE %s = '%s'
-WHERE %s = '%s';`,
+UPDATE %s = %s
+WHERE %s;`,
 			tableName,
 			columnName,
-			escapedValue,
-			pkColumn,
-			escapedPkValue,
+			value,
+			PrimaryKeyWhere(pkColumns, pkValues, pkTypes, c.GetDbType()),
 		)
 	}
 
@@ -272,17 +287,15 @@ WHERE %s = '%s';`,
 -- No primary key specified. Edit WHERE clause manually.
 -- Note: ClickHouse uses ALTER TABLE UPDATE for mutations
 ALTER TABLE %s
-UPDATE %s = '%s'
+UPDATE %s = %s
 WHERE <condition>;`,
 		tableName,
 		columnName,
-		escapedValue,
+		value,
 	)
 }
 
-func (c *ClickHouseConnection) BuildDeleteStatement(tableName, primaryKeyCol, pkValue string) string {
-	escapedPkValue := strings.ReplaceAll(pkValue, "'", "''")
-
+func (c *ClickHouseConnection) BuildDeleteStatement(tableName string, pkColumns, pkValues, pkTypes []string) string {
 	return fmt.Sprintf(`-- ClickHouse DELETE statement
 -- WARNING: This will permanently delete data!
 -- Note: ClickHouse uses ALTER TABLE DELETE for mutations
@@ -290,10 +303,25 @@ func (c *ClickHouseConnection) BuildDeleteStatement(tableName, primaryKeyCol, pk
 
 ALTER TABLE %s
 DELETE
-WHERE %s = '%s';`,
+WHERE %s;`,
+		tableName,
+		PrimaryKeyWhere(pkColumns, pkValues, pkTypes, c.GetDbType()),
+	)
+}
+
+func (c *ClickHouseConnection) BuildInsertStatement(tableName string, columns, values []string) string {
+	quotedColumns := make([]string, len(columns))
+	for i, col := range columns {
+		quotedColumns[i] = "`" + strings.ReplaceAll(col, "`", "\\`") + "`"
+	}
+
+	return fmt.Sprintf(`-- ClickHouse INSERT statement
+-- Note: MATERIALIZED and ALIAS columns are computed by ClickHouse
+INSERT INTO %s (%s)
+VALUES (%s);`,
 		tableName,
-		primaryKeyCol,
-		escapedPkValue,
+		strings.Join(quotedColumns, ", "),
+		strings.Join(values, ", "),
 	)
 }
 
@@ -2,6 +2,7 @@ package db
 
 import (
 	"fmt"
+	"strings"
 )
 
 func CreateConnection(name, dbType, connString string) (DatabaseConnection, error) {
@@ -14,8 +15,8 @@ func CreateConnection(name, dbType, connString string) (DatabaseConnection, erro
 		return NewSQLiteConnection(name, connString)
 	case "sqlserver", "mssql":
 		return NewSQLServerConnection(name, connString)
-	// case "duckdb":
-	// 	return NewDuckDBConnection(name, connString)
+	case "duckdb":
+		return NewDuckDBConnection(name, connString)
 	case "clickhouse":
 		return NewClickHouseConnection(name, connString)
 	case "godror", "oracle":
@@ -26,3 +27,23 @@ func CreateConnection(name, dbType, connString string) (DatabaseConnection, erro
 		return nil, fmt.Errorf("driver not implemented for %s", dbType)
 	}
 }
+
+// CanonicalDBType maps the alternative names accepted for a database type to
+// the one its connection reports with GetDbType
+func CanonicalDBType(dbType string) string {
+	switch strings.ToLower(dbType) {
+	case "postgresql":
+		return "postgres"
+	case "mariadb":
+		return "mysql"
+	case "sqlite3":
+		return "sqlite"
+	case "mssql":
+		return "sqlserver"
+	case "godror":
+		return "oracle"
+	case "interbase":
+		return "firebird"
+	}
+	return strings.ToLower(dbType)
+}
@@ -1,14 +1,19 @@
 package db
 
-import "database/sql"
+import (
+	"context"
+	"database/sql"
+	"errors"
+)
 
 type DatabaseConnection interface {
 	Open() error
 	Ping() error
 	Close() error
 	Query(queryName string, args ...any) (any, error)
-	ExecQuery(sql string, args ...any) (*sql.Rows, error)
-	Exec(sql string, args ...any) error
+	ExecQuery(ctx context.Context, sql string, args ...any) (*sql.Rows, error)
+	Exec(ctx context.Context, sql string, args ...any) error
+	BeginTx(ctx context.Context) (*sql.Tx, error)
 	GetInfoSQL(infoType string) string
 	GetTables() ([]string, error)
 	GetViews() ([]string, error)
@@ -17,9 +22,10 @@ type DatabaseConnection interface {
 	GetForeignKeysReferencingTable(tableName string) ([]ForeignKey, error)
 	GetUniqueConstraints(tableName string) ([]string, error)
 	BuildUpdateStatement(
-		tableName, columnName, currentValue, pkColumn, pkValue string,
+		tableName, columnName string, currentValue Cell, pkColumns, pkValues, pkTypes []string,
 	) string
-	BuildDeleteStatement(tableName, primaryKeyCol, pkValue string) string
+	BuildDeleteStatement(tableName string, pkColumns, pkValues, pkTypes []string) string
+	BuildInsertStatement(tableName string, columns, values []string) string
 	ApplyRowLimit(sql string, limit int) string
 	GetPlaceholder(paramIndex int) string
 
@@ -34,3 +40,7 @@ type DatabaseConnection interface {
 	SetLastQuery(Query)
 	SetQueries(map[string]Query)
 }
+
+// ErrNoTransactions is returned by BeginTx for databases whose writes can't
+// be rolled back
+var ErrNoTransactions = errors.New("transactions are not supported")
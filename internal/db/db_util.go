@@ -2,62 +2,25 @@ package db
 
 import (
 	"database/sql"
-	"fmt"
-	"log"
 )
 
-func FormatTableData(rows *sql.Rows) (columns []string, data [][]string, err error) {
+func FormatTableData(rows *sql.Rows) (columns []string, data [][]Cell, err error) {
 	columns, _, data, err = FormatTableDataWithTypes(rows)
 	return columns, data, err
 }
 
-func FormatTableDataWithTypes(rows *sql.Rows) (columns []string, columnTypes []string, data [][]string, err error) {
-	columns, err = rows.Columns()
+func FormatTableDataWithTypes(rows *sql.Rows) (columns []string, columnTypes []string, data [][]Cell, err error) {
+	it, err := NewRowIterator(rows, 0)
 	if err != nil {
-		log.Fatalf("Error getting columns: %v", err)
+		return nil, nil, nil, err
 	}
+	defer it.Close()
 
-	// Get column types from the result set
-	columnTypeObjects, err := rows.ColumnTypes()
+	data, err = it.ReadAll()
 	if err != nil {
-		log.Fatalf("Error getting column types: %v", err)
+		return nil, nil, nil, err
 	}
-
-	columnTypes = make([]string, len(columns))
-	for i, ct := range columnTypeObjects {
-		columnTypes[i] = ct.DatabaseTypeName()
-	}
-
-	values := make([]any, len(columns))
-	valuePtrs := make([]any, len(columns))
-	for i := range columns {
-		valuePtrs[i] = &values[i]
-	}
-	for rows.Next() {
-		err = rows.Scan(valuePtrs...)
-		if err != nil {
-			log.Fatalf("Error scanning row: %v", err)
-		}
-		rowData := make([]string, len(columns))
-		for i, val := range values {
-			if val == nil {
-				rowData[i] = "NULL"
-			} else {
-				// Handle byte slices (common with MySQL text/varchar columns)
-				if b, ok := val.([]byte); ok {
-					rowData[i] = string(b)
-				} else {
-					rowData[i] = fmt.Sprintf("%v", val)
-				}
-			}
-		}
-		data = append(data, rowData)
-	}
-
-	if err = rows.Err(); err != nil {
-		log.Fatalf("Error during iteration: %v", err)
-	}
-	return columns, columnTypes, data, nil
+	return it.Columns(), it.ColumnTypes(), data, nil
 }
 
 func GetNextQueryId(queries map[string]Query) (id int) {
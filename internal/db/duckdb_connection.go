@@ -1,180 +1,341 @@
+//go:build cgo
+
 package db
-//
-// import (
-// 	"database/sql"
-// 	"fmt"
-// 	"strings"
-//
-// 	_ "github.com/marcboeker/go-duckdb"
-// )
-//
-// type DuckDBConnection struct {
-// 	*BaseConnection
-// 	db *sql.DB
-// }
-//
-// func NewDuckDBConnection(name, connStr string) (*DuckDBConnection, error) {
-// 	bc := &BaseConnection{
-// 		Name:       name,
-// 		DbType:     "duckdb",
-// 		ConnString: connStr,
-// 	}
-// 	return &DuckDBConnection{BaseConnection: bc}, nil
-// }
-//
-// func (d *DuckDBConnection) Open() error {
-// 	db, err := sql.Open("duckdb", d.ConnString)
-// 	if err != nil {
-// 		return fmt.Errorf("failed to open duckdb database: %w", err)
-// 	}
-// 	d.db = db
-// 	return nil
-// }
-//
-// func (d *DuckDBConnection) Ping() error {
-// 	if d.db == nil {
-// 		return fmt.Errorf("database is not open")
-// 	}
-// 	return d.db.Ping()
-// }
-//
-// func (d *DuckDBConnection) Close() error {
-// 	if d.db != nil {
-// 		return d.db.Close()
-// 	}
-// 	return nil
-// }
-//
-// func (d *DuckDBConnection) Query(queryName string, args ...any) (any, error) {
-// 	query, exists := d.Queries[queryName]
-// 	if !exists {
-// 		return nil, fmt.Errorf("query not found: %s", queryName)
-// 	}
-// 	return d.db.Query(query.SQL, args...)
-// }
-//
-// func (d *DuckDBConnection) ExecQuery(sql string, args ...any) (*sql.Rows, error) {
-// 	return d.db.Query(sql, args...)
-// }
-//
-// func (d *DuckDBConnection) Exec(sql string, args ...any) error {
-// 	_, err := d.db.Exec(sql, args...)
-// 	return err
-// }
-//
-// func (d *DuckDBConnection) GetTableMetadata(tableName string) (*TableMetadata, error) {
-// 	if d.db == nil {
-// 		return nil, fmt.Errorf("database is not open")
-// 	}
-//
-// 	metadata := &TableMetadata{
-// 		TableName: tableName,
-// 	}
-//
-// 	pkQuery := `
-// 		SELECT column_name
-// 		FROM information_schema.key_column_usage
-// 		WHERE table_name = ?
-// 		LIMIT 1
-// 	`
-//
-// 	pkRows, err := d.db.Query(pkQuery, tableName)
-// 	if err == nil {
-// 		defer pkRows.Close()
-// 		if pkRows.Next() {
-// 			var pkColumn string
-// 			if err := pkRows.Scan(&pkColumn); err == nil {
-// 				metadata.PrimaryKey = pkColumn
-// 			}
-// 		}
-// 	}
-//
-// 	colQuery := `
-// 		SELECT column_name, data_type
-// 		FROM information_schema.columns
-// 		WHERE table_name = ?
-// 		ORDER BY ordinal_position
-// 	`
-//
-// 	colRows, err := d.db.Query(colQuery, tableName)
-// 	if err != nil {
-// 		return metadata, fmt.Errorf("failed to query duckdb column metadata: %w", err)
-// 	}
-// 	defer colRows.Close()
-//
-// 	for colRows.Next() {
-// 		var colName, colType string
-// 		if err := colRows.Scan(&colName, &colType); err != nil {
-// 			continue
-// 		}
-// 		metadata.Columns = append(metadata.Columns, colName)
-// 		metadata.ColumnTypes = append(metadata.ColumnTypes, colType)
-// 	}
-//
-// 	return metadata, nil
-// }
-//
-// func (d *DuckDBConnection) GetInfoSQL(infoType string) string {
-// 	switch infoType {
-// 	case "tables":
-// 		return `SELECT table_schema as schema,
-// 		       table_name as name
-// 		FROM information_schema.tables
-// 		WHERE table_type = 'BASE TABLE'
-// 		ORDER BY table_schema, table_name`
-// 	case "views":
-// 		return `SELECT table_schema as schema,
-// 		       table_name as name
-// 		FROM information_schema.views
-// 		ORDER BY table_schema, table_name`
-// 	default:
-// 		return ""
-// 	}
-// }
-// func (d *DuckDBConnection) BuildUpdateStatement(tableName, columnName, currentValue, pkColumn, pkValue string) string {
-// 	escapedValue := strings.ReplaceAll(currentValue, "'", "''")
-//
-// 	if pkColumn != "" && pkValue != "" {
-// 		escapedPkValue := strings.ReplaceAll(pkValue, "'", "''")
-// 		return fmt.Sprintf(`-- DuckDB UPDATE statement
-// UPDATE %s
-// SET %s = '%s'
-// WHERE %s = '%s';`,
-// 			tableName,
-// 			columnName,
-// 			escapedValue,
-// 			pkColumn,
-// 			escapedPkValue,
-// 		)
-// 	}
-//
-// 	return fmt.Sprintf(`-- DuckDB UPDATE statement
-// -- No primary key specified. Edit WHERE clause manually.
-// UPDATE %s
-// SET %s = '%s'
-// WHERE <condition>;`,
-// 		tableName,
-// 		columnName,
-// 		escapedValue,
-// 	)
-// }
-//
-// func (d *DuckDBConnection) BuildDeleteStatement(tableName, primaryKeyCol, pkValue string) string {
-// 	escapedPkValue := strings.ReplaceAll(pkValue, "'", "''")
-//
-// 	return fmt.Sprintf(`-- DuckDB DELETE statement
-// -- WARNING: This will permanently delete data!
-// -- Ensure the WHERE clause is correct.
-//
-// DELETE FROM %s
-// WHERE %s = '%s';`,
-// 		tableName,
-// 		primaryKeyCol,
-// 		escapedPkValue,
-// 	)
-// }
-//
-// func (d *DuckDBConnection) GetForeignKeysReferencingTable(tableName string) ([]ForeignKey, error) {
-// 	return []ForeignKey{}, fmt.Errorf("GetForeignKeysReferencingTable not implemented for this driver")
-// }
-//
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	_ "github.com/marcboeker/go-duckdb"
+)
+
+type DuckDBConnection struct {
+	*BaseConnection
+	db *sql.DB
+}
+
+func NewDuckDBConnection(name, connStr string) (*DuckDBConnection, error) {
+	bc := &BaseConnection{
+		Name:       name,
+		DbType:     "duckdb",
+		ConnString: connStr,
+	}
+	return &DuckDBConnection{BaseConnection: bc}, nil
+}
+
+func (d *DuckDBConnection) Open() error {
+	db, err := sql.Open("duckdb", d.ConnString)
+	if err != nil {
+		return fmt.Errorf("failed to open duckdb database: %w", err)
+	}
+	d.db = db
+
+	if d.Schema != "" {
+		setSchemaSQL := fmt.Sprintf("SET schema = '%s'", strings.ReplaceAll(d.Schema, "'", "''"))
+		_, err = d.db.Exec(setSchemaSQL)
+		if err != nil {
+			d.db.Close()
+			return fmt.Errorf("failed to set schema to '%s': %w", d.Schema, err)
+		}
+	}
+
+	return nil
+}
+
+func (d *DuckDBConnection) Ping() error {
+	if d.db == nil {
+		return fmt.Errorf("database is not open")
+	}
+	return d.db.Ping()
+}
+
+func (d *DuckDBConnection) Close() error {
+	if d.db != nil {
+		return d.db.Close()
+	}
+	return nil
+}
+
+func (d *DuckDBConnection) Query(queryName string, args ...any) (any, error) {
+	query, exists := d.Queries[queryName]
+	if !exists {
+		return nil, fmt.Errorf("query not found: %s", queryName)
+	}
+	return d.db.Query(query.SQL, args...)
+}
+
+func (d *DuckDBConnection) ExecQuery(ctx context.Context, sql string, args ...any) (*sql.Rows, error) {
+	return d.db.QueryContext(ctx, sql, args...)
+}
+
+func (d *DuckDBConnection) Exec(ctx context.Context, sql string, args ...any) error {
+	_, err := d.db.ExecContext(ctx, sql, args...)
+	return err
+}
+
+func (d *DuckDBConnection) BeginTx(ctx context.Context) (*sql.Tx, error) {
+	return d.db.BeginTx(ctx, nil)
+}
+
+func (d *DuckDBConnection) GetTableMetadata(tableName string) (*TableMetadata, error) {
+	if d.db == nil {
+		return nil, fmt.Errorf("database is not open")
+	}
+
+	metadata := &TableMetadata{
+		TableName: tableName,
+	}
+
+	colQuery := `
+		SELECT column_name, data_type, COALESCE(column_default, '')
+		FROM information_schema.columns
+		WHERE table_name = ?
+		  AND table_schema = current_schema()
+		ORDER BY ordinal_position
+	`
+
+	colRows, err := d.db.Query(colQuery, tableName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query duckdb column metadata: %w", err)
+	}
+	defer colRows.Close()
+
+	for colRows.Next() {
+		var colName, colType, colDefault string
+		if err := colRows.Scan(&colName, &colType, &colDefault); err != nil {
+			continue
+		}
+		metadata.Columns = append(metadata.Columns, colName)
+		metadata.ColumnTypes = append(metadata.ColumnTypes, colType)
+		metadata.ColumnDefaults = append(metadata.ColumnDefaults, colDefault)
+
+		// Columns numbered from a sequence
+		if strings.HasPrefix(strings.ToLower(colDefault), "nextval(") {
+			metadata.GeneratedColumns = append(metadata.GeneratedColumns, colName)
+		}
+	}
+
+	// Composite keys list every column, in key order
+	pkQuery := `
+		SELECT unnest(constraint_column_names)
+		FROM duckdb_constraints()
+		WHERE constraint_type = 'PRIMARY KEY'
+		  AND table_name = ?
+		  AND schema_name = current_schema()
+	`
+
+	pkRows, err := d.db.Query(pkQuery, tableName)
+	if err == nil {
+		defer pkRows.Close()
+		for pkRows.Next() {
+			var pkColumn string
+			if err := pkRows.Scan(&pkColumn); err == nil {
+				metadata.PrimaryKeys = append(metadata.PrimaryKeys, pkColumn)
+			}
+		}
+	}
+
+	fks, err := d.GetForeignKeys(tableName)
+	if err == nil {
+		metadata.ForeignKeys = fks
+	}
+
+	return metadata, nil
+}
+
+func (d *DuckDBConnection) GetInfoSQL(infoType string) string {
+	switch infoType {
+	case "tables":
+		return `SELECT table_schema as schema,
+		       table_name as name
+		FROM information_schema.tables
+		WHERE table_type = 'BASE TABLE'
+		ORDER BY table_schema, table_name`
+	case "views":
+		return `SELECT table_schema as schema,
+		       table_name as name
+		FROM information_schema.tables
+		WHERE table_type = 'VIEW'
+		ORDER BY table_schema, table_name`
+	default:
+		return ""
+	}
+}
+
+func (d *DuckDBConnection) GetTables() ([]string, error) {
+	return d.listRelations("BASE TABLE")
+}
+
+func (d *DuckDBConnection) GetViews() ([]string, error) {
+	return d.listRelations("VIEW")
+}
+
+func (d *DuckDBConnection) listRelations(tableType string) ([]string, error) {
+	if d.db == nil {
+		return nil, fmt.Errorf("database not open")
+	}
+
+	query := `
+		SELECT table_name
+		FROM information_schema.tables
+		WHERE table_type = ?
+		  AND table_schema = current_schema()
+		ORDER BY table_name
+	`
+
+	rows, err := d.db.Query(query, tableType)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query tables: %w", err)
+	}
+	defer rows.Close()
+
+	var names []string
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err == nil {
+			names = append(names, name)
+		}
+	}
+
+	return names, nil
+}
+
+func (d *DuckDBConnection) GetForeignKeys(tableName string) ([]ForeignKey, error) {
+	if d.db == nil {
+		return nil, fmt.Errorf("database not open")
+	}
+
+	// unnest on both lists pairs each column with the one it references
+	query := `
+		SELECT unnest(constraint_column_names),
+		       referenced_table,
+		       unnest(referenced_column_names)
+		FROM duckdb_constraints()
+		WHERE constraint_type = 'FOREIGN KEY'
+		  AND table_name = ?
+		  AND schema_name = current_schema()
+	`
+
+	rows, err := d.db.Query(query, tableName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query foreign keys: %w", err)
+	}
+	defer rows.Close()
+
+	var foreignKeys []ForeignKey
+	for rows.Next() {
+		var fk ForeignKey
+		if err := rows.Scan(&fk.Column, &fk.ReferencedTable, &fk.ReferencedColumn); err == nil {
+			foreignKeys = append(foreignKeys, fk)
+		}
+	}
+
+	return foreignKeys, nil
+}
+
+func (d *DuckDBConnection) GetForeignKeysReferencingTable(tableName string) ([]ForeignKey, error) {
+	if d.db == nil {
+		return nil, fmt.Errorf("database not open")
+	}
+
+	// Find all foreign keys that reference this table (reverse direction)
+	query := `
+		SELECT unnest(constraint_column_names),
+		       table_name,
+		       unnest(referenced_column_names)
+		FROM duckdb_constraints()
+		WHERE constraint_type = 'FOREIGN KEY'
+		  AND lower(referenced_table) = lower(?)
+		  AND schema_name = current_schema()
+	`
+
+	rows, err := d.db.Query(query, tableName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query referencing foreign keys: %w", err)
+	}
+	defer rows.Close()
+
+	var foreignKeys []ForeignKey
+	for rows.Next() {
+		var fk ForeignKey
+		if err := rows.Scan(&fk.Column, &fk.ReferencedTable, &fk.ReferencedColumn); err == nil {
+			foreignKeys = append(foreignKeys, fk)
+		}
+	}
+
+	return foreignKeys, nil
+}
+
+func (d *DuckDBConnection) GetUniqueConstraints(tableName string) ([]string, error) {
+	if d.db == nil {
+		return nil, fmt.Errorf("database not open")
+	}
+
+	query := `
+		SELECT DISTINCT unnest(constraint_column_names)
+		FROM duckdb_constraints()
+		WHERE constraint_type = 'UNIQUE'
+		  AND table_name = ?
+		  AND schema_name = current_schema()
+	`
+
+	rows, err := d.db.Query(query, tableName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query unique constraints: %w", err)
+	}
+	defer rows.Close()
+
+	var uniqueColumns []string
+	for rows.Next() {
+		var colName string
+		if err := rows.Scan(&colName); err == nil {
+			uniqueColumns = append(uniqueColumns, colName)
+		}
+	}
+
+	return uniqueColumns, nil
+}
+
+func (d *DuckDBConnection) BuildUpdateStatement(tableName, columnName string, currentValue Cell, pkColumns, pkValues, pkTypes []string) string {
+	value := CellLiteral(currentValue)
+
+	if hasPrimaryKeyValues(pkColumns, pkValues) {
+		return fmt.Sprintf(`-- DuckDB UPDATE statement
+UPDATE %s
+SET %s = %s
+WHERE %s;`,
+			tableName,
+			columnName,
+			value,
+			PrimaryKeyWhere(pkColumns, pkValues, pkTypes, d.GetDbType()),
+		)
+	}
+
+	return fmt.Sprintf(`-- DuckDB UPDATE statement
+-- No primary key specified. Edit WHERE clause manually.
+UPDATE %s
+SET %s = %s
+WHERE <condition>;`,
+		tableName,
+		columnName,
+		value,
+	)
+}
+
+func (d *DuckDBConnection) BuildDeleteStatement(tableName string, pkColumns, pkValues, pkTypes []string) string {
+	return fmt.Sprintf(`-- DuckDB DELETE statement
+-- WARNING: This will permanently delete data!
+-- Ensure the WHERE clause is correct.
+
+DELETE FROM %s
+WHERE %s;`,
+		tableName,
+		PrimaryKeyWhere(pkColumns, pkValues, pkTypes, d.GetDbType()),
+	)
+}
+
+func (d *DuckDBConnection) GetPlaceholder(paramIndex int) string {
+	return "?"
+}
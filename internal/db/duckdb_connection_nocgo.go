@@ -0,0 +1,154 @@
+//go:build !cgo
+
+package db
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+)
+
+type DuckDBConnection struct {
+	*BaseConnection
+	db *sql.DB
+}
+
+func NewDuckDBConnection(name, connStr string) (*DuckDBConnection, error) {
+	return nil, fmt.Errorf("DuckDB driver not available: this binary was built without CGO support. Please use a build with CGO enabled or choose a different database")
+}
+
+func (d *DuckDBConnection) Open() error {
+	return fmt.Errorf("DuckDB driver not available: binary built without CGO")
+}
+
+func (d *DuckDBConnection) Ping() error {
+	return fmt.Errorf("DuckDB driver not available: binary built without CGO")
+}
+
+func (d *DuckDBConnection) Close() error {
+	return nil
+}
+
+func (d *DuckDBConnection) Query(queryName string, args ...any) (any, error) {
+	return nil, fmt.Errorf("DuckDB driver not available: binary built without CGO")
+}
+
+func (d *DuckDBConnection) ExecQuery(ctx context.Context, sql string, args ...any) (*sql.Rows, error) {
+	return nil, fmt.Errorf("DuckDB driver not available: binary built without CGO")
+}
+
+func (d *DuckDBConnection) Exec(ctx context.Context, sql string, args ...any) error {
+	return fmt.Errorf("DuckDB driver not available: binary built without CGO")
+}
+
+func (d *DuckDBConnection) BeginTx(ctx context.Context) (*sql.Tx, error) {
+	return nil, fmt.Errorf("DuckDB driver not available: binary built without CGO")
+}
+
+func (d *DuckDBConnection) GetTableMetadata(tableName string) (*TableMetadata, error) {
+	return nil, fmt.Errorf("DuckDB driver not available: binary built without CGO")
+}
+
+func (d *DuckDBConnection) GetInfoSQL(infoType string) string {
+	return ""
+}
+
+func (d *DuckDBConnection) BuildUpdateStatement(tableName, columnName string, currentValue Cell, pkColumns, pkValues, pkTypes []string) string {
+	return "-- DuckDB driver not available: binary built without CGO"
+}
+
+func (d *DuckDBConnection) ApplyRowLimit(sql string, limit int) string {
+	return sql
+}
+
+func (d *DuckDBConnection) BuildDeleteStatement(tableName string, pkColumns, pkValues, pkTypes []string) string {
+	return "-- DuckDB driver not available: binary built without CGO"
+}
+
+func (d *DuckDBConnection) BuildInsertStatement(tableName string, columns, values []string) string {
+	return "-- DuckDB driver not available: binary built without CGO"
+}
+
+func (d *DuckDBConnection) GetPlaceholder(paramIndex int) string {
+	return "?"
+}
+
+func (d *DuckDBConnection) GetTables() ([]string, error) {
+	return nil, fmt.Errorf("DuckDB driver not available: binary built without CGO")
+}
+
+func (d *DuckDBConnection) GetViews() ([]string, error) {
+	return nil, fmt.Errorf("DuckDB driver not available: binary built without CGO")
+}
+
+func (d *DuckDBConnection) GetForeignKeys(table string) ([]ForeignKey, error) {
+	return nil, fmt.Errorf("DuckDB driver not available: binary built without CGO")
+}
+
+func (d *DuckDBConnection) GetForeignKeysReferencingTable(table string) ([]ForeignKey, error) {
+	return nil, fmt.Errorf("DuckDB driver not available: binary built without CGO")
+}
+
+func (d *DuckDBConnection) GetUniqueConstraints(table string) ([]string, error) {
+	return nil, fmt.Errorf("DuckDB driver not available: binary built without CGO")
+}
+
+func (d *DuckDBConnection) GetName() string {
+	if d.BaseConnection != nil {
+		return d.BaseConnection.Name
+	}
+	return ""
+}
+
+func (d *DuckDBConnection) GetDbType() string {
+	if d.BaseConnection != nil {
+		return d.BaseConnection.DbType
+	}
+	return ""
+}
+
+func (d *DuckDBConnection) GetConnString() string {
+	if d.BaseConnection != nil {
+		return d.BaseConnection.ConnString
+	}
+	return ""
+}
+
+func (d *DuckDBConnection) GetSchema() string {
+	if d.BaseConnection != nil {
+		return d.BaseConnection.Schema
+	}
+	return ""
+}
+
+func (d *DuckDBConnection) GetQueries() map[string]Query {
+	if d.BaseConnection != nil {
+		return d.BaseConnection.Queries
+	}
+	return nil
+}
+
+func (d *DuckDBConnection) GetLastQuery() Query {
+	if d.BaseConnection != nil {
+		return d.BaseConnection.LastQuery
+	}
+	return Query{}
+}
+
+func (d *DuckDBConnection) SetSchema(schema string) {
+	if d.BaseConnection != nil {
+		d.BaseConnection.Schema = schema
+	}
+}
+
+func (d *DuckDBConnection) SetLastQuery(query Query) {
+	if d.BaseConnection != nil {
+		d.BaseConnection.LastQuery = query
+	}
+}
+
+func (d *DuckDBConnection) SetQueries(queries map[string]Query) {
+	if d.BaseConnection != nil {
+		d.BaseConnection.Queries = queries
+	}
+}
@@ -1,6 +1,7 @@
 package db
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
 	"strings"
@@ -63,15 +64,19 @@ func (f *FirebirdConnection) Query(queryName string, args ...any) (any, error) {
 	return f.db.Query(query.SQL, args...)
 }
 
-func (f *FirebirdConnection) ExecQuery(sqlStr string, args ...any) (*sql.Rows, error) {
-	return f.db.Query(sqlStr, args...)
+func (f *FirebirdConnection) ExecQuery(ctx context.Context, sqlStr string, args ...any) (*sql.Rows, error) {
+	return f.db.QueryContext(ctx, sqlStr, args...)
 }
 
-func (f *FirebirdConnection) Exec(sqlStr string, args ...any) error {
-	_, err := f.db.Exec(sqlStr, args...)
+func (f *FirebirdConnection) Exec(ctx context.Context, sqlStr string, args ...any) error {
+	_, err := f.db.ExecContext(ctx, sqlStr, args...)
 	return err
 }
 
+func (f *FirebirdConnection) BeginTx(ctx context.Context) (*sql.Tx, error) {
+	return f.db.BeginTx(ctx, nil)
+}
+
 func (f *FirebirdConnection) SetSchema(schema string) {
 	// Firebird doesn't use schemas like PostgreSQL
 	// No-op implementation
@@ -295,11 +300,17 @@ func (f *FirebirdConnection) GetTableMetadata(tableName string) (*TableMetadata,
 			FROM RDB$RELATION_CONSTRAINTS RC
 			JOIN RDB$INDEX_SEGMENTS ICS ON RC.RDB$INDEX_NAME = ICS.RDB$INDEX_NAME
 			WHERE TRIM(RC.RDB$CONSTRAINT_NAME) = ?
+			ORDER BY ICS.RDB$FIELD_POSITION
 		`
-		var pkColumn string
-		err := f.db.QueryRow(pkColQuery, strings.TrimSpace(pkName.String)).Scan(&pkColumn)
+		pkRows, err := f.db.Query(pkColQuery, strings.TrimSpace(pkName.String))
 		if err == nil {
-			metadata.PrimaryKeys = append(metadata.PrimaryKeys, pkColumn)
+			defer pkRows.Close()
+			for pkRows.Next() {
+				var pkColumn string
+				if err := pkRows.Scan(&pkColumn); err == nil {
+					metadata.PrimaryKeys = append(metadata.PrimaryKeys, pkColumn)
+				}
+			}
 		}
 	}
 
@@ -323,6 +334,28 @@ func (f *FirebirdConnection) GetTableMetadata(tableName string) (*TableMetadata,
 		metadata.ColumnTypes = append(metadata.ColumnTypes, dataType)
 	}
 
+	// Identity columns exist since Firebird 3, older servers have none.
+	// Type 0 is GENERATED ALWAYS, 1 is BY DEFAULT
+	identityQuery := `
+		SELECT TRIM(RF.RDB$FIELD_NAME), RF.RDB$IDENTITY_TYPE
+		FROM RDB$RELATION_FIELDS RF
+		WHERE TRIM(RF.RDB$RELATION_NAME) = ?
+		AND RF.RDB$IDENTITY_TYPE IS NOT NULL
+	`
+	if identityRows, err := f.db.Query(identityQuery, strings.ToUpper(tableName)); err == nil {
+		defer identityRows.Close()
+		for identityRows.Next() {
+			var colName string
+			var identityType int
+			if err := identityRows.Scan(&colName, &identityType); err == nil {
+				metadata.GeneratedColumns = append(metadata.GeneratedColumns, colName)
+				if identityType == 0 {
+					metadata.IdentityColumns = append(metadata.IdentityColumns, colName)
+				}
+			}
+		}
+	}
+
 	// Fetch foreign keys
 	fks, err := f.GetForeignKeys(tableName)
 	if err == nil {
@@ -332,8 +365,8 @@ func (f *FirebirdConnection) GetTableMetadata(tableName string) (*TableMetadata,
 	return metadata, nil
 }
 
-func (f *FirebirdConnection) BuildDeleteStatement(tableName, primaryKeyCol, pkValue string) string {
-	return fmt.Sprintf("DELETE FROM %s WHERE %s = '%s'", tableName, primaryKeyCol, pkValue)
+func (f *FirebirdConnection) BuildDeleteStatement(tableName string, pkColumns, pkValues, pkTypes []string) string {
+	return fmt.Sprintf("DELETE FROM %s WHERE %s", tableName, PrimaryKeyWhere(pkColumns, pkValues, pkTypes, f.GetDbType()))
 }
 
 func (f *FirebirdConnection) GetUniqueConstraints(tableName string) ([]string, error) {
@@ -26,6 +26,11 @@ func InferDBType(connString string) string {
 		return "sqlite"
 	}
 
+	// DuckDB file pattern detection
+	if strings.HasSuffix(conn, ".duckdb") || strings.HasSuffix(conn, ".ddb") {
+		return "duckdb"
+	}
+
 	// SQLite file pattern detection
 	if strings.HasSuffix(conn, ".db") ||
 	   strings.HasSuffix(conn, ".sqlite") ||
@@ -46,5 +51,6 @@ func GetSupportedDBTypes() []string {
 		"clickhouse",
 		"oracle",
 		"firebird",
+		"duckdb",
 	}
 }
@@ -11,6 +11,10 @@ type TableMetadata struct {
 	PrimaryKeys       []string
 	ColumnTypes       []string
 	Columns           []string
+	ColumnDefaults    []string // Default expression of each column, empty when it has none
+	GeneratedColumns  []string // Auto-increment, identity and computed columns, filled in by the database
+	ComputedColumns   []string // Generated columns computed from an expression, they never take a value
+	IdentityColumns   []string // Generated columns that refuse a value unless the insert overrides them
 	ForeignKeys       []ForeignKey
 	UniqueConstraints []string
 }
@@ -1,8 +1,10 @@
 package db
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
+	"strconv"
 	"strings"
 
 	_ "github.com/go-sql-driver/mysql"
@@ -68,17 +70,22 @@ func (m *MySQLConnection) Query(queryName string, args ...any) (any, error) {
 }
 
 func (m *MySQLConnection) ExecQuery(
+	ctx context.Context,
 	sql string,
 	args ...any,
 ) (*sql.Rows, error) {
-	return m.db.Query(sql, args...)
+	return m.db.QueryContext(ctx, sql, args...)
 }
 
-func (m *MySQLConnection) Exec(sql string, args ...any) error {
-	_, err := m.db.Exec(sql, args...)
+func (m *MySQLConnection) Exec(ctx context.Context, sql string, args ...any) error {
+	_, err := m.db.ExecContext(ctx, sql, args...)
 	return err
 }
 
+func (m *MySQLConnection) BeginTx(ctx context.Context) (*sql.Tx, error) {
+	return m.db.BeginTx(ctx, nil)
+}
+
 func (m *MySQLConnection) GetTableMetadata(
 	tableName string,
 ) (*TableMetadata, error) {
@@ -93,7 +100,6 @@ func (m *MySQLConnection) GetTableMetadata(
 		AND CONSTRAINT_NAME = 'PRIMARY'
 		AND TABLE_SCHEMA = DATABASE()
 		ORDER BY ORDINAL_POSITION
-		LIMIT 1
 	`
 
 	rows, err := m.db.Query(pkQuery, tableName)
@@ -106,7 +112,7 @@ func (m *MySQLConnection) GetTableMetadata(
 		TableName: tableName,
 	}
 
-	if rows.Next() {
+	for rows.Next() {
 		var pkColumn string
 		if err := rows.Scan(&pkColumn); err == nil {
 			metadata.PrimaryKeys = append(metadata.PrimaryKeys, pkColumn)
@@ -115,7 +121,9 @@ func (m *MySQLConnection) GetTableMetadata(
 
 	colQuery := `
 		SELECT COLUMN_NAME,
-		       COLUMN_TYPE
+		       COLUMN_TYPE,
+		       COLUMN_DEFAULT,
+		       EXTRA
 		FROM INFORMATION_SCHEMA.COLUMNS
 		WHERE TABLE_NAME = ?
 		AND TABLE_SCHEMA = DATABASE()
@@ -126,10 +134,22 @@ func (m *MySQLConnection) GetTableMetadata(
 	if err == nil {
 		defer colRows.Close()
 		for colRows.Next() {
-			var colName, colType string
-			if err := colRows.Scan(&colName, &colType); err == nil {
+			var colName, colType, extra string
+			var colDefault sql.NullString
+			if err := colRows.Scan(&colName, &colType, &colDefault, &extra); err == nil {
 				metadata.Columns = append(metadata.Columns, colName)
 				metadata.ColumnTypes = append(metadata.ColumnTypes, colType)
+				metadata.ColumnDefaults = append(metadata.ColumnDefaults, mysqlDefaultExpression(colDefault, extra))
+
+				extra = strings.ToUpper(extra)
+				if strings.Contains(extra, "AUTO_INCREMENT") ||
+					strings.Contains(extra, "VIRTUAL GENERATED") ||
+					strings.Contains(extra, "STORED GENERATED") {
+					metadata.GeneratedColumns = append(metadata.GeneratedColumns, colName)
+				}
+				if strings.Contains(extra, "VIRTUAL GENERATED") || strings.Contains(extra, "STORED GENERATED") {
+					metadata.ComputedColumns = append(metadata.ComputedColumns, colName)
+				}
 			}
 		}
 	}
@@ -143,6 +163,27 @@ func (m *MySQLConnection) GetTableMetadata(
 	return metadata, nil
 }
 
+// mysqlDefaultExpression turns COLUMN_DEFAULT into SQL. MySQL stores literal
+// defaults unquoted and marks expressions as DEFAULT_GENERATED, while MariaDB
+// quotes its literals already
+func mysqlDefaultExpression(colDefault sql.NullString, extra string) string {
+	if !colDefault.Valid {
+		return ""
+	}
+	value := colDefault.String
+	upper := strings.ToUpper(value)
+	if strings.Contains(strings.ToUpper(extra), "DEFAULT_GENERATED") ||
+		strings.HasPrefix(value, "'") ||
+		strings.HasPrefix(upper, "CURRENT_TIMESTAMP") ||
+		upper == "NULL" {
+		return value
+	}
+	if _, err := strconv.ParseFloat(value, 64); err == nil {
+		return value
+	}
+	return "'" + strings.ReplaceAll(value, "'", "''") + "'"
+}
+
 func (m *MySQLConnection) GetForeignKeys(tableName string) ([]ForeignKey, error) {
 	if m.db == nil {
 		return nil, fmt.Errorf("database is not open")
@@ -322,40 +363,35 @@ func (m *MySQLConnection) GetViews() ([]string, error) {
 }
 
 func (m *MySQLConnection) BuildUpdateStatement(
-	tableName, columnName, currentValue, pkColumn, pkValue string,
+	tableName, columnName string, currentValue Cell, pkColumns, pkValues, pkTypes []string,
 ) string {
-	escapedValue := strings.ReplaceAll(currentValue, "'", "''")
+	value := CellLiteral(currentValue)
 
-	if pkColumn != "" && pkValue != "" {
-		escapedPkValue := strings.ReplaceAll(pkValue, "'", "''")
+	if hasPrimaryKeyValues(pkColumns, pkValues) {
 		return fmt.Sprintf(
-			"-- MySQL UPDATE statement\nUPDATE %s\nSET %s = '%s'\nWHERE %s = '%s';",
+			"-- MySQL UPDATE statement\nUPDATE %s\nSET %s = %s\nWHERE %s;",
 			tableName,
 			columnName,
-			escapedValue,
-			pkColumn,
-			escapedPkValue,
+			value,
+			PrimaryKeyWhere(pkColumns, pkValues, pkTypes, m.GetDbType()),
 		)
 	}
 
 	return fmt.Sprintf(
-		"-- MySQL UPDATE statement\n-- No primary key specified. Edit WHERE clause manually.\nUPDATE `%s`\nSET `%s` = '%s'\nWHERE <condition>;",
+		"-- MySQL UPDATE statement\n-- No primary key specified. Edit WHERE clause manually.\nUPDATE `%s`\nSET `%s` = %s\nWHERE <condition>;",
 		tableName,
 		columnName,
-		escapedValue,
+		value,
 	)
 }
 
 func (m *MySQLConnection) BuildDeleteStatement(
-	tableName, primaryKeyCol, pkValue string,
+	tableName string, pkColumns, pkValues, pkTypes []string,
 ) string {
-	escapedPkValue := strings.ReplaceAll(pkValue, "'", "''")
-
 	return fmt.Sprintf(
-		"-- MySQL DELETE statement\n-- WARNING: This will permanently delete data!\n-- Ensure the WHERE clause is correct.\n\nDELETE FROM %s\nWHERE %s = '%s';",
+		"-- MySQL DELETE statement\n-- WARNING: This will permanently delete data!\n-- Ensure the WHERE clause is correct.\n\nDELETE FROM %s\nWHERE %s;",
 		tableName,
-		primaryKeyCol,
-		escapedPkValue,
+		PrimaryKeyWhere(pkColumns, pkValues, pkTypes, m.GetDbType()),
 	)
 }
 
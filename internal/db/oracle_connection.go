@@ -3,6 +3,7 @@
 package db
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
 	"strings"
@@ -64,15 +65,19 @@ func (oc *OracleConnection) Query(queryName string, args ...any) (any, error) {
 	return oc.db.Query(query.SQL, args...)
 }
 
-func (oc *OracleConnection) ExecQuery(sql string, args ...any) (*sql.Rows, error) {
-	return oc.db.Query(sql, args...)
+func (oc *OracleConnection) ExecQuery(ctx context.Context, sql string, args ...any) (*sql.Rows, error) {
+	return oc.db.QueryContext(ctx, sql, args...)
 }
 
-func (oc *OracleConnection) Exec(sql string, args ...any) error {
-	_, err := oc.db.Exec(sql, args...)
+func (oc *OracleConnection) Exec(ctx context.Context, sql string, args ...any) error {
+	_, err := oc.db.ExecContext(ctx, sql, args...)
 	return err
 }
 
+func (oc *OracleConnection) BeginTx(ctx context.Context) (*sql.Tx, error) {
+	return oc.db.BeginTx(ctx, nil)
+}
+
 func (oc *OracleConnection) GetTableMetadata(tableName string) (*TableMetadata, error) {
 	if oc.db == nil {
 		return nil, fmt.Errorf("database is not open")
@@ -98,7 +103,6 @@ func (oc *OracleConnection) GetTableMetadata(tableName string) (*TableMetadata,
 			AND cons.owner = cols.owner
 		WHERE cons.constraint_type = 'P'
 		AND cons.table_name = : 1
-		AND ROWNUM = 1
 		ORDER BY cols.position
 	`
 
@@ -111,8 +115,7 @@ func (oc *OracleConnection) GetTableMetadata(tableName string) (*TableMetadata,
 			WHERE cons.constraint_type = 'P'
 			AND cons. table_name = :1
 			AND cons.owner = :2
-			AND ROWNUM = 1
-			ORDER BY cols.position
+				ORDER BY cols.position
 		`
 	}
 
@@ -133,7 +136,7 @@ func (oc *OracleConnection) GetTableMetadata(tableName string) (*TableMetadata,
 	}
 	defer rows.Close()
 
-	if rows.Next() {
+	for rows.Next() {
 		var pkColumn string
 		if err := rows.Scan(&pkColumn); err == nil {
 			metadata.PrimaryKeys = append(metadata.PrimaryKeys, pkColumn)
@@ -141,18 +144,20 @@ func (oc *OracleConnection) GetTableMetadata(tableName string) (*TableMetadata,
 	}
 
 	colQuery := `
-		SELECT column_name, data_type, data_length, data_precision, data_scale
-		FROM all_tab_columns
+		SELECT column_name, data_type, data_length, data_precision, data_scale, data_default, virtual_column
+		FROM all_tab_cols
 		WHERE table_name = : 1
+		  AND hidden_column = 'NO'
 		ORDER BY column_id
 	`
 
 	if currentOwner != "" {
 		colQuery = `
-			SELECT column_name, data_type, data_length, data_precision, data_scale
-			FROM all_tab_columns
+			SELECT column_name, data_type, data_length, data_precision, data_scale, data_default, virtual_column
+			FROM all_tab_cols
 			WHERE table_name = :1
 			  AND owner = :2
+			  AND hidden_column = 'NO'
 			ORDER BY column_id
 		`
 	}
@@ -170,10 +175,11 @@ func (oc *OracleConnection) GetTableMetadata(tableName string) (*TableMetadata,
 	defer colRows.Close()
 
 	for colRows.Next() {
-		var colName, dataType string
+		var colName, dataType, virtualColumn string
 		var dataLength, dataPrecision, dataScale sql.NullInt64
+		var dataDefault sql.NullString
 
-		if err := colRows.Scan(&colName, &dataType, &dataLength, &dataPrecision, &dataScale); err != nil {
+		if err := colRows.Scan(&colName, &dataType, &dataLength, &dataPrecision, &dataScale, &dataDefault, &virtualColumn); err != nil {
 			continue
 		}
 
@@ -202,6 +208,41 @@ func (oc *OracleConnection) GetTableMetadata(tableName string) (*TableMetadata,
 
 		metadata.Columns = append(metadata.Columns, colName)
 		metadata.ColumnTypes = append(metadata.ColumnTypes, fullType)
+
+		// Identity columns default to the nextval of their ISEQ$$ sequence
+		colDefault := strings.TrimSpace(dataDefault.String)
+		if virtualColumn == "YES" || strings.Contains(colDefault, "ISEQ$$") {
+			metadata.ColumnDefaults = append(metadata.ColumnDefaults, "")
+			metadata.GeneratedColumns = append(metadata.GeneratedColumns, colName)
+			if virtualColumn == "YES" {
+				metadata.ComputedColumns = append(metadata.ComputedColumns, colName)
+			}
+		} else {
+			metadata.ColumnDefaults = append(metadata.ColumnDefaults, colDefault)
+		}
+	}
+
+	// Identity columns exist since 12c, the ALWAYS ones refuse a given value
+	identityQuery := `
+		SELECT column_name FROM user_tab_identity_cols
+		WHERE table_name = :1 AND generation_type = 'ALWAYS'
+	`
+	identityArgs := []any{upperTableName}
+	if currentOwner != "" {
+		identityQuery = `
+			SELECT column_name FROM all_tab_identity_cols
+			WHERE table_name = :1 AND owner = :2 AND generation_type = 'ALWAYS'
+		`
+		identityArgs = append(identityArgs, currentOwner)
+	}
+	if identityRows, err := oc.db.Query(identityQuery, identityArgs...); err == nil {
+		defer identityRows.Close()
+		for identityRows.Next() {
+			var colName string
+			if err := identityRows.Scan(&colName); err == nil {
+				metadata.IdentityColumns = append(metadata.IdentityColumns, colName)
+			}
+		}
 	}
 
 	// Fetch foreign keys
@@ -506,26 +547,24 @@ func (oc *OracleConnection) GetUniqueConstraints(tableName string) ([]string, er
 	return uniqueColumns, nil
 }
 
-func (oc *OracleConnection) BuildUpdateStatement(tableName, columnName, currentValue, pkColumn, pkValue string) string {
-	escapedValue := strings.ReplaceAll(currentValue, "'", "''")
+func (oc *OracleConnection) BuildUpdateStatement(tableName, columnName string, currentValue Cell, pkColumns, pkValues, pkTypes []string) string {
+	value := CellLiteral(currentValue)
 
-	if pkColumn != "" && pkValue != "" {
-		escapedPkValue := strings.ReplaceAll(pkValue, "'", "''")
+	if hasPrimaryKeyValues(pkColumns, pkValues) {
 		return fmt.Sprintf(
-			"-- Oracle UPDATE statement\nUPDATE %s\nSET %s = '%s'\nWHERE %s = '%s';",
+			"-- Oracle UPDATE statement\nUPDATE %s\nSET %s = %s\nWHERE %s;",
 			tableName,
 			columnName,
-			escapedValue,
-			pkColumn,
-			escapedPkValue,
+			value,
+			PrimaryKeyWhere(pkColumns, pkValues, pkTypes, oc.GetDbType()),
 		)
 	}
 
 	return fmt.Sprintf(
-		"-- Oracle UPDATE statement\n-- No primary key specified. Edit WHERE clause manually.\nUPDATE %s\nSET %s = '%s'\nWHERE <condition>;\n-- COMMIT;",
+		"-- Oracle UPDATE statement\n-- No primary key specified. Edit WHERE clause manually.\nUPDATE %s\nSET %s = %s\nWHERE <condition>;\n-- COMMIT;",
 		tableName,
 		columnName,
-		escapedValue,
+		value,
 	)
 }
 
@@ -543,14 +582,27 @@ func (oc *OracleConnection) ApplyRowLimit(sql string, limit int) string {
 	return fmt.Sprintf("%s\nFETCH FIRST %d ROWS ONLY", strings.TrimRight(sql, ";"), limit)
 }
 
-func (oc *OracleConnection) BuildDeleteStatement(tableName, primaryKeyCol, pkValue string) string {
-	escapedPkValue := strings.ReplaceAll(pkValue, "'", "''")
+func (oc *OracleConnection) BuildDeleteStatement(tableName string, pkColumns, pkValues, pkTypes []string) string {
+	return fmt.Sprintf(
+		"-- Oracle DELETE statement\n-- WARNING: This will permanently delete data!\n-- Ensure the WHERE clause is correct.\n\nDELETE FROM %s\nWHERE %s;\n-- COMMIT;",
+		tableName,
+		PrimaryKeyWhere(pkColumns, pkValues, pkTypes, oc.GetDbType()),
+	)
+}
+
+func (oc *OracleConnection) BuildInsertStatement(tableName string, columns, values []string) string {
+	// Column names come from the data dictionary in their stored case, so
+	// quoting them keeps reserved words and mixed case working
+	quotedColumns := make([]string, len(columns))
+	for i, col := range columns {
+		quotedColumns[i] = `"` + strings.ReplaceAll(col, `"`, `""`) + `"`
+	}
 
 	return fmt.Sprintf(
-		"-- Oracle DELETE statement\n-- WARNING: This will permanently delete data!\n-- Ensure the WHERE clause is correct.\n\nDELETE FROM %s\nWHERE %s = '%s';\n-- COMMIT;",
+		"-- Oracle INSERT statement\nINSERT INTO %s (%s)\nVALUES (%s);\n-- COMMIT;",
 		tableName,
-		primaryKeyCol,
-		escapedPkValue,
+		strings.Join(quotedColumns, ", "),
+		strings.Join(values, ", "),
 	)
 }
 
@@ -3,6 +3,7 @@
 package db
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
 )
@@ -32,14 +33,18 @@ func (oc *OracleConnection) Query(queryName string, args ...any) (any, error) {
 	return nil, fmt.Errorf("Oracle driver not available: binary built without CGO")
 }
 
-func (oc *OracleConnection) ExecQuery(sql string, args ...any) (*sql.Rows, error) {
+func (oc *OracleConnection) ExecQuery(ctx context.Context, sql string, args ...any) (*sql.Rows, error) {
 	return nil, fmt.Errorf("Oracle driver not available: binary built without CGO")
 }
 
-func (oc *OracleConnection) Exec(sql string, args ...any) error {
+func (oc *OracleConnection) Exec(ctx context.Context, sql string, args ...any) error {
 	return fmt.Errorf("Oracle driver not available: binary built without CGO")
 }
 
+func (oc *OracleConnection) BeginTx(ctx context.Context) (*sql.Tx, error) {
+	return nil, fmt.Errorf("Oracle driver not available: binary built without CGO")
+}
+
 func (oc *OracleConnection) GetTableMetadata(tableName string) (*TableMetadata, error) {
 	return nil, fmt.Errorf("Oracle driver not available: binary built without CGO")
 }
@@ -48,7 +53,7 @@ func (oc *OracleConnection) GetInfoSQL(infoType string) string {
 	return ""
 }
 
-func (oc *OracleConnection) BuildUpdateStatement(tableName, columnName, currentValue, pkColumn, pkValue string) string {
+func (oc *OracleConnection) BuildUpdateStatement(tableName, columnName string, currentValue Cell, pkColumns, pkValues, pkTypes []string) string {
 	return "-- Oracle driver not available: binary built without CGO"
 }
 
@@ -56,7 +61,11 @@ func (oc *OracleConnection) ApplyRowLimit(sql string, limit int) string {
 	return sql
 }
 
-func (oc *OracleConnection) BuildDeleteStatement(tableName, primaryKeyCol, pkValue string) string {
+func (oc *OracleConnection) BuildDeleteStatement(tableName string, pkColumns, pkValues, pkTypes []string) string {
+	return "-- Oracle driver not available: binary built without CGO"
+}
+
+func (oc *OracleConnection) BuildInsertStatement(tableName string, columns, values []string) string {
 	return "-- Oracle driver not available: binary built without CGO"
 }
 
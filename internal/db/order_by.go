@@ -0,0 +1,132 @@
+package db
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+type sqlWord struct {
+	upper string
+	pos   int
+}
+
+// ApplyOrderBy sorts a query by the column at the given 1-based position,
+// replacing its top-level ORDER BY if it has one. The column is referenced by
+// position so names never need dialect-specific quoting, and any LIMIT,
+// OFFSET, FETCH or FOR clause stays after the ORDER BY
+func ApplyOrderBy(sql string, column int, desc bool) string {
+	clean := strings.TrimRight(strings.TrimSpace(sql), "; \t\n")
+
+	direction := "ASC"
+	if desc {
+		direction = "DESC"
+	}
+	clause := fmt.Sprintf("ORDER BY %d %s", column, direction)
+
+	words := topLevelWords(clean)
+
+	orderPos, lastSelect := -1, -1
+	for i, w := range words {
+		switch w.upper {
+		case "SELECT":
+			lastSelect = w.pos
+		case "ORDER":
+			if i+1 < len(words) && words[i+1].upper == "BY" {
+				orderPos = w.pos
+			}
+		}
+	}
+
+	tailPos := -1
+	for _, w := range words {
+		if w.pos <= max(orderPos, lastSelect) {
+			continue
+		}
+		switch w.upper {
+		case "LIMIT", "OFFSET", "FETCH", "FOR":
+			tailPos = w.pos
+		}
+		if tailPos >= 0 {
+			break
+		}
+	}
+
+	switch {
+	case orderPos >= 0 && tailPos >= 0:
+		return clean[:orderPos] + clause + "\n" + clean[tailPos:]
+	case orderPos >= 0:
+		return clean[:orderPos] + clause
+	case tailPos >= 0:
+		return clean[:tailPos] + clause + "\n" + clean[tailPos:]
+	default:
+		return clean + "\n" + clause
+	}
+}
+
+// topLevelWords lists the words outside parentheses, string literals,
+// quoted identifiers and comments, with their byte offsets
+func topLevelWords(sql string) []sqlWord {
+	var words []sqlWord
+	depth := 0
+
+	for i := 0; i < len(sql); {
+		c := sql[i]
+		switch {
+		case c == '\'' || c == '"' || c == '`':
+			i = skipQuoted(sql, i, c)
+		case c == '[':
+			i = skipQuoted(sql, i, ']')
+		case strings.HasPrefix(sql[i:], "--"):
+			if end := strings.IndexByte(sql[i:], '\n'); end >= 0 {
+				i += end + 1
+			} else {
+				i = len(sql)
+			}
+		case strings.HasPrefix(sql[i:], "/*"):
+			if end := strings.Index(sql[i+2:], "*/"); end >= 0 {
+				i += end + 4
+			} else {
+				i = len(sql)
+			}
+		case c == '(':
+			depth++
+			i++
+		case c == ')':
+			depth--
+			i++
+		case isWordByte(c):
+			start := i
+			for i < len(sql) && isWordByte(sql[i]) {
+				i++
+			}
+			if depth == 0 {
+				words = append(words, sqlWord{upper: strings.ToUpper(sql[start:i]), pos: start})
+			}
+		default:
+			i++
+		}
+	}
+
+	return words
+}
+
+// skipQuoted returns the offset just past the literal starting at i, treating
+// a doubled closing quote as an escaped one
+func skipQuoted(sql string, i int, closing byte) int {
+	for j := i + 1; j < len(sql); j++ {
+		if sql[j] != closing {
+			continue
+		}
+		if j+1 < len(sql) && sql[j+1] == closing && closing != ']' {
+			j++
+			continue
+		}
+		return j + 1
+	}
+	return len(sql)
+}
+
+func isWordByte(c byte) bool {
+	return c == '_' || c >= 0x80 || unicode.IsLetter(rune(c)) || unicode.IsDigit(rune(c))
+}
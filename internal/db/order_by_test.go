@@ -0,0 +1,73 @@
+package db
+
+import "testing"
+
+func TestApplyOrderBy(t *testing.T) {
+	tests := []struct {
+		name   string
+		sql    string
+		column int
+		desc   bool
+		want   string
+	}{
+		{
+			name:   "Plain select",
+			sql:    "SELECT * FROM users;",
+			column: 2,
+			want:   "SELECT * FROM users\nORDER BY 2 ASC",
+		},
+		{
+			name:   "Existing ORDER BY replaced",
+			sql:    "SELECT id, name FROM users ORDER BY name DESC",
+			column: 1,
+			want:   "SELECT id, name FROM users ORDER BY 1 ASC",
+		},
+		{
+			name:   "LIMIT and OFFSET kept after",
+			sql:    "SELECT * FROM users LIMIT 10 OFFSET 20",
+			column: 3,
+			desc:   true,
+			want:   "SELECT * FROM users ORDER BY 3 DESC\nLIMIT 10 OFFSET 20",
+		},
+		{
+			name:   "ORDER BY replaced before LIMIT",
+			sql:    "SELECT * FROM users ORDER BY id LIMIT 5",
+			column: 2,
+			desc:   true,
+			want:   "SELECT * FROM users ORDER BY 2 DESC\nLIMIT 5",
+		},
+		{
+			name:   "FETCH FIRST",
+			sql:    "SELECT * FROM users ORDER BY id OFFSET 5 ROWS FETCH FIRST 10 ROWS ONLY",
+			column: 1,
+			want:   "SELECT * FROM users ORDER BY 1 ASC\nOFFSET 5 ROWS FETCH FIRST 10 ROWS ONLY",
+		},
+		{
+			name:   "CTE with its own ORDER BY and LIMIT",
+			sql:    "WITH recent AS (SELECT * FROM orders ORDER BY created_at DESC LIMIT 100)\nSELECT * FROM recent",
+			column: 2,
+			want:   "WITH recent AS (SELECT * FROM orders ORDER BY created_at DESC LIMIT 100)\nSELECT * FROM recent\nORDER BY 2 ASC",
+		},
+		{
+			name:   "Subquery in the WHERE clause",
+			sql:    "SELECT * FROM users WHERE id IN (SELECT user_id FROM orders ORDER BY total LIMIT 3)",
+			column: 1,
+			desc:   true,
+			want:   "SELECT * FROM users WHERE id IN (SELECT user_id FROM orders ORDER BY total LIMIT 3)\nORDER BY 1 DESC",
+		},
+		{
+			name:   "Keywords in strings and comments ignored",
+			sql:    "SELECT 'order by x limit 1' AS note -- limit 5\nFROM users",
+			column: 1,
+			want:   "SELECT 'order by x limit 1' AS note -- limit 5\nFROM users\nORDER BY 1 ASC",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ApplyOrderBy(tt.sql, tt.column, tt.desc); got != tt.want {
+				t.Errorf("ApplyOrderBy(%q) = %q, want %q", tt.sql, got, tt.want)
+			}
+		})
+	}
+}
@@ -1,9 +1,9 @@
 package db
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
-	"strings"
 
 	_ "github.com/lib/pq"
 )
@@ -64,17 +64,22 @@ func (p *PostgresConnection) Query(queryName string, args ...any) (any, error) {
 }
 
 func (p *PostgresConnection) ExecQuery(
+	ctx context.Context,
 	sql string,
 	args ...any,
 ) (*sql.Rows, error) {
-	return p.db.Query(sql, args...)
+	return p.db.QueryContext(ctx, sql, args...)
 }
 
-func (p *PostgresConnection) Exec(sql string, args ...any) error {
-	_, err := p.db.Exec(sql, args...)
+func (p *PostgresConnection) Exec(ctx context.Context, sql string, args ...any) error {
+	_, err := p.db.ExecContext(ctx, sql, args...)
 	return err
 }
 
+func (p *PostgresConnection) BeginTx(ctx context.Context) (*sql.Tx, error) {
+	return p.db.BeginTx(ctx, nil)
+}
+
 func (p *PostgresConnection) GetTableMetadata(
 	tableName string,
 ) (*TableMetadata, error) {
@@ -103,7 +108,7 @@ func (p *PostgresConnection) GetTableMetadata(
 		WHERE c.relname = $1
 		AND n.nspname = $2
 		AND i.indisprimary
-		ORDER BY a.attnum
+		ORDER BY array_position(i.indkey::int2[], a.attnum)
 	`
 
 	rows, err := p.db.Query(pkQuery, tableName, currentSchema)
@@ -131,7 +136,13 @@ func (p *PostgresConnection) GetTableMetadata(
 		           WHEN numeric_precision IS NOT NULL
 		           THEN data_type || '(' || numeric_precision || ',' || numeric_scale || ')'
 		           ELSE data_type
-		       END as full_type
+		       END as full_type,
+		       COALESCE(column_default, ''),
+		       is_identity = 'YES'
+		           OR is_generated = 'ALWAYS'
+		           OR COALESCE(column_default, '') LIKE 'nextval(%',
+		       is_generated = 'ALWAYS',
+		       is_identity = 'YES' AND COALESCE(identity_generation, '') = 'ALWAYS'
 		FROM information_schema.columns
 		WHERE table_name = $1
 		AND table_schema = $2
@@ -142,10 +153,22 @@ func (p *PostgresConnection) GetTableMetadata(
 	if err == nil {
 		defer colRows.Close()
 		for colRows.Next() {
-			var colName, colType string
-			if err := colRows.Scan(&colName, &colType); err == nil {
+			var colName, colType, colDefault string
+			var generated, computed, identity bool
+			if err := colRows.Scan(&colName, &colType, &colDefault, &generated, &computed, &identity); err == nil {
 				metadata.Columns = append(metadata.Columns, colName)
 				metadata.ColumnTypes = append(metadata.ColumnTypes, colType)
+				metadata.ColumnDefaults = append(metadata.ColumnDefaults, colDefault)
+				if generated {
+					metadata.GeneratedColumns = append(metadata.GeneratedColumns, colName)
+				}
+				if computed {
+					metadata.ComputedColumns = append(metadata.ComputedColumns, colName)
+				}
+				// GENERATED BY DEFAULT identities take a given value as is
+				if identity {
+					metadata.IdentityColumns = append(metadata.IdentityColumns, colName)
+				}
 			}
 		}
 	}
@@ -428,38 +451,35 @@ func (p *PostgresConnection) GetUniqueConstraints(tableName string) ([]string, e
 }
 
 func (p *PostgresConnection) BuildUpdateStatement(
-	tableName, columnName, currentValue, pkColumn, pkValue string,
+	tableName, columnName string, currentValue Cell, pkColumns, pkValues, pkTypes []string,
 ) string {
-	escapedValue := strings.ReplaceAll(currentValue, "'", "''")
+	value := CellLiteral(currentValue)
 
-	if pkColumn != "" && pkValue != "" {
-		escapedPkValue := strings.ReplaceAll(pkValue, "'", "''")
+	if hasPrimaryKeyValues(pkColumns, pkValues) {
 		return fmt.Sprintf(
-			"-- PostgreSQL UPDATE statement\nUPDATE %s\nSET %s = '%s'\nWHERE %s = '%s';",
+			"-- PostgreSQL UPDATE statement\nUPDATE %s\nSET %s = %s\nWHERE %s;",
 			tableName,
 			columnName,
-			escapedValue,
-			pkColumn,
-			escapedPkValue,
+			value,
+			PrimaryKeyWhere(pkColumns, pkValues, pkTypes, p.GetDbType()),
 		)
 	}
 
 	return fmt.Sprintf(
-		"-- PostgreSQL UPDATE statement\n-- No primary key specified. Edit WHERE clause manually.\nUPDATE %s\nSET %s = '%s'\nWHERE <condition>;",
+		"-- PostgreSQL UPDATE statement\n-- No primary key specified. Edit WHERE clause manually.\nUPDATE %s\nSET %s = %s\nWHERE <condition>;",
 		tableName,
 		columnName,
-		escapedValue,
+		value,
 	)
 }
 
 func (c *PostgresConnection) BuildDeleteStatement(
-	tableName, primaryKeyCol, pkValue string,
+	tableName string, pkColumns, pkValues, pkTypes []string,
 ) string {
 	return fmt.Sprintf(
-		"DELETE FROM %s\nWHERE %s = '%s';",
+		"DELETE FROM %s\nWHERE %s;",
 		tableName,
-		primaryKeyCol,
-		pkValue,
+		PrimaryKeyWhere(pkColumns, pkValues, pkTypes, c.GetDbType()),
 	)
 }
 
@@ -1,18 +1,109 @@
 package db
 
 import (
+	"fmt"
+	"sort"
 	"strconv"
+	"strings"
 )
 
 type Query struct {
 	Name        string            `yaml:"name"`
 	Id          int               `yaml:"id"`
 	SQL         string            `yaml:"sql"`
+	Description string            `yaml:"description,omitempty"`
+	Tags        []string          `yaml:"tags,omitempty"`
+	Folder      string            `yaml:"folder,omitempty"`   // Slash separated, like reports/monthly
+	Dialects    []string          `yaml:"dialects,omitempty"` // Database types a global query may run on, any when empty
+	Variants    map[string]string `yaml:"variants,omitempty"` // SQL for a database type, used instead of SQL there
 	TableName   string            `yaml:"table_name,omitempty"`
 	PrimaryKeys []string          `yaml:"primary_keys,omitempty"`
 	Metadata    map[string]string `yaml:"metadata,omitempty"`
 }
 
+// ForDialect returns the query to run on a database type: the variant
+// written for it, or the SQL. It fails when dialects rules the type out or
+// there is no SQL that fits
+func (q Query) ForDialect(dbType string) (Query, error) {
+	dbType = CanonicalDBType(dbType)
+
+	if len(q.Dialects) > 0 {
+		allowed := false
+		for _, d := range q.Dialects {
+			if CanonicalDBType(d) == dbType {
+				allowed = true
+				break
+			}
+		}
+		if !allowed {
+			return q, fmt.Errorf("query '%s' only runs on %s, not %s", q.Name, strings.Join(q.Dialects, ", "), dbType)
+		}
+	}
+
+	for dialect, sql := range q.Variants {
+		if CanonicalDBType(dialect) == dbType {
+			q.SQL = sql
+			return q, nil
+		}
+	}
+	if q.SQL == "" {
+		variants := make([]string, 0, len(q.Variants))
+		for dialect := range q.Variants {
+			variants = append(variants, dialect)
+		}
+		sort.Strings(variants)
+		return q, fmt.Errorf("query '%s' has no SQL for %s, only variants for %s", q.Name, dbType, strings.Join(variants, ", "))
+	}
+	return q, nil
+}
+
+// HasTag reports whether the query is tagged with tag, ignoring case
+func (q Query) HasTag(tag string) bool {
+	for _, t := range q.Tags {
+		if strings.EqualFold(t, tag) {
+			return true
+		}
+	}
+	return false
+}
+
+// InFolder reports whether the query is in folder or one of its subfolders
+func (q Query) InFolder(folder string) bool {
+	folder = CleanFolder(folder)
+	if folder == "" {
+		return true
+	}
+	return q.Folder == folder || strings.HasPrefix(q.Folder, folder+"/")
+}
+
+// CleanFolder normalizes a folder path, dropping empty and surrounding slashes
+func CleanFolder(folder string) string {
+	var parts []string
+	for _, part := range strings.Split(folder, "/") {
+		if part = strings.TrimSpace(part); part != "" {
+			parts = append(parts, part)
+		}
+	}
+	return strings.Join(parts, "/")
+}
+
+// ParseTags splits comma separated tags, dropping empty ones and duplicates
+func ParseTags(values ...string) []string {
+	var tags []string
+	seen := map[string]bool{}
+	for _, value := range values {
+		for _, tag := range strings.Split(value, ",") {
+			tag = strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(tag), "#"))
+			if tag == "" || seen[strings.ToLower(tag)] {
+				continue
+			}
+			seen[strings.ToLower(tag)] = true
+			tags = append(tags, tag)
+		}
+	}
+	return tags
+}
+
 func FindQueryWithSelector(queries map[string]Query, selector string) (Query, bool) {
 	if id, err := strconv.Atoi(selector); err == nil {
 		for _, q := range queries {
@@ -0,0 +1,39 @@
+package db
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestQueryForDialect(t *testing.T) {
+	q := Query{
+		Name: "active_users",
+		SQL:  "SELECT * FROM users WHERE active = 1",
+		Variants: map[string]string{
+			"postgresql": "SELECT * FROM users WHERE active",
+		},
+	}
+
+	got, err := q.ForDialect("postgres")
+	if err != nil || got.SQL != "SELECT * FROM users WHERE active" {
+		t.Errorf("ForDialect(postgres) = %q, %v, want the postgres variant", got.SQL, err)
+	}
+	got, err = q.ForDialect("mysql")
+	if err != nil || got.SQL != q.SQL {
+		t.Errorf("ForDialect(mysql) = %q, %v, want the plain SQL", got.SQL, err)
+	}
+
+	q.Dialects = []string{"postgres", "mariadb"}
+	if _, err := q.ForDialect("mysql"); err != nil {
+		t.Errorf("ForDialect(mysql) error = %v, mariadb should allow it", err)
+	}
+	if _, err := q.ForDialect("sqlite"); err == nil || !strings.Contains(err.Error(), "only runs on") {
+		t.Errorf("ForDialect(sqlite) error = %v, want a dialects error", err)
+	}
+
+	q.Dialects = nil
+	q.SQL = ""
+	if _, err := q.ForDialect("oracle"); err == nil || !strings.Contains(err.Error(), "no SQL for oracle") {
+		t.Errorf("ForDialect(oracle) error = %v, want a missing variant error", err)
+	}
+}
@@ -0,0 +1,140 @@
+package db
+
+import (
+	"database/sql"
+	"fmt"
+	"sync"
+)
+
+// RowIterator reads a result set incrementally, one page at a time, so large
+// results don't have to be held in memory before they are shown
+type RowIterator struct {
+	mu          sync.Mutex
+	rows        *sql.Rows
+	columns     []string
+	columnTypes []string
+	pageSize    int
+	values      []any
+	valuePtrs   []any
+	done        bool
+}
+
+// NewRowIterator wraps rows in an iterator returning pageSize rows per page.
+// A pageSize of 0 or less reads the whole result set in a single page
+func NewRowIterator(rows *sql.Rows, pageSize int) (*RowIterator, error) {
+	columns, err := rows.Columns()
+	if err != nil {
+		rows.Close()
+		return nil, fmt.Errorf("error getting columns: %w", err)
+	}
+
+	// Get column types from the result set
+	columnTypeObjects, err := rows.ColumnTypes()
+	if err != nil {
+		rows.Close()
+		return nil, fmt.Errorf("error getting column types: %w", err)
+	}
+
+	columnTypes := make([]string, len(columns))
+	for i, ct := range columnTypeObjects {
+		columnTypes[i] = ct.DatabaseTypeName()
+	}
+
+	values := make([]any, len(columns))
+	valuePtrs := make([]any, len(columns))
+	for i := range columns {
+		valuePtrs[i] = &values[i]
+	}
+
+	return &RowIterator{
+		rows:        rows,
+		columns:     columns,
+		columnTypes: columnTypes,
+		pageSize:    pageSize,
+		values:      values,
+		valuePtrs:   valuePtrs,
+	}, nil
+}
+
+func (it *RowIterator) Columns() []string { return it.columns }
+
+func (it *RowIterator) ColumnTypes() []string { return it.columnTypes }
+
+// NextPage reads up to one page of rows. Once the result set is exhausted the
+// underlying rows are closed and Done reports true
+func (it *RowIterator) NextPage() ([][]Cell, error) {
+	it.mu.Lock()
+	defer it.mu.Unlock()
+
+	if it.done {
+		return nil, nil
+	}
+
+	var data [][]Cell
+	for it.pageSize <= 0 || len(data) < it.pageSize {
+		if !it.rows.Next() {
+			it.done = true
+			break
+		}
+
+		if err := it.rows.Scan(it.valuePtrs...); err != nil {
+			it.done = true
+			it.rows.Close()
+			return data, fmt.Errorf("error scanning row %d: %w", len(data)+1, err)
+		}
+
+		rowData := make([]Cell, len(it.columns))
+		for i, val := range it.values {
+			rowData[i] = formatValue(val)
+		}
+		data = append(data, rowData)
+	}
+
+	if it.done {
+		err := it.rows.Err()
+		it.rows.Close()
+		if err != nil {
+			return data, fmt.Errorf("error during iteration: %w", err)
+		}
+	}
+
+	return data, nil
+}
+
+// ReadAll reads every remaining row regardless of the page size
+func (it *RowIterator) ReadAll() ([][]Cell, error) {
+	var data [][]Cell
+	for !it.Done() {
+		page, err := it.NextPage()
+		data = append(data, page...)
+		if err != nil {
+			return data, err
+		}
+	}
+	return data, nil
+}
+
+func (it *RowIterator) Done() bool {
+	it.mu.Lock()
+	defer it.mu.Unlock()
+	return it.done
+}
+
+// Close stops the iteration and releases the underlying rows
+func (it *RowIterator) Close() error {
+	it.mu.Lock()
+	defer it.mu.Unlock()
+	it.done = true
+	return it.rows.Close()
+}
+
+func formatValue(val any) Cell {
+	if val == nil {
+		return NullCell
+	}
+	// Handle byte slices (common with MySQL text/varchar columns)
+	if b, ok := val.([]byte); ok {
+		return TextCell(string(b))
+	}
+	return TextCell(fmt.Sprintf("%v", val))
+}
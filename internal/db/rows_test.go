@@ -0,0 +1,121 @@
+//go:build cgo
+
+package db
+
+import (
+	"database/sql"
+	"strings"
+	"testing"
+)
+
+// countingQuery returns the numbers 1 to 5 in column n
+const countingQuery = `WITH RECURSIVE seq(n) AS (SELECT 1 UNION ALL SELECT n + 1 FROM seq WHERE n < 5)
+SELECT n, CASE WHEN n = 2 THEN NULL ELSE 'row' || n END AS label FROM seq`
+
+func openRowIterator(t *testing.T, query string, pageSize int) (*sql.DB, *RowIterator) {
+	t.Helper()
+	conn, err := sql.Open("sqlite3", ":memory:")
+	if err != nil {
+		t.Fatalf("sql.Open() error: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	// Rows left open would hold the only connection
+	conn.SetMaxOpenConns(1)
+
+	rows, err := conn.Query(query)
+	if err != nil {
+		t.Fatalf("Query() error: %v", err)
+	}
+	iter, err := NewRowIterator(rows, pageSize)
+	if err != nil {
+		t.Fatalf("NewRowIterator() error: %v", err)
+	}
+	return conn, iter
+}
+
+func TestRowIteratorPaging(t *testing.T) {
+	_, iter := openRowIterator(t, countingQuery, 2)
+
+	if got := strings.Join(iter.Columns(), ","); got != "n,label" {
+		t.Errorf("Columns() = %q, want %q", got, "n,label")
+	}
+
+	var sizes []int
+	var all [][]Cell
+	for !iter.Done() {
+		page, err := iter.NextPage()
+		if err != nil {
+			t.Fatalf("NextPage() error: %v", err)
+		}
+		sizes = append(sizes, len(page))
+		all = append(all, page...)
+	}
+
+	// The last page holds what is left
+	if len(sizes) != 3 || sizes[0] != 2 || sizes[1] != 2 || sizes[2] != 1 {
+		t.Errorf("page sizes = %v, want [2 2 1]", sizes)
+	}
+	if len(all) != 5 || all[4][0].Text != "5" || all[0][1].Text != "row1" {
+		t.Errorf("rows = %v, want 1 to 5", all)
+	}
+	if !all[1][1].Null {
+		t.Errorf("NULL label read as %#v", all[1][1])
+	}
+
+	page, err := iter.NextPage()
+	if page != nil || err != nil {
+		t.Errorf("NextPage() after the end = %v, %v, want nil, nil", page, err)
+	}
+}
+
+func TestRowIteratorReadAll(t *testing.T) {
+	_, iter := openRowIterator(t, countingQuery, 2)
+
+	if _, err := iter.NextPage(); err != nil {
+		t.Fatalf("NextPage() error: %v", err)
+	}
+	rest, err := iter.ReadAll()
+	if err != nil {
+		t.Fatalf("ReadAll() error: %v", err)
+	}
+	if len(rest) != 3 || !iter.Done() {
+		t.Errorf("ReadAll() read %d rows, done %v, want 3 rows and done", len(rest), iter.Done())
+	}
+}
+
+func TestRowIteratorClose(t *testing.T) {
+	conn, iter := openRowIterator(t, countingQuery, 2)
+
+	if err := iter.Close(); err != nil {
+		t.Fatalf("Close() error: %v", err)
+	}
+	if !iter.Done() {
+		t.Error("Done() = false after Close()")
+	}
+	if page, err := iter.NextPage(); page != nil || err != nil {
+		t.Errorf("NextPage() after Close() = %v, %v, want nil, nil", page, err)
+	}
+
+	// The rows are released, so the connection is free for another query
+	if err := conn.QueryRow("SELECT 1").Scan(new(int)); err != nil {
+		t.Errorf("query after Close() error: %v", err)
+	}
+}
+
+func TestRowIteratorError(t *testing.T) {
+	// abs() of the smallest integer overflows when the third row is read
+	_, iter := openRowIterator(t,
+		`WITH RECURSIVE seq(n) AS (SELECT 1 UNION ALL SELECT n + 1 FROM seq WHERE n < 5)
+SELECT CASE WHEN n = 3 THEN abs(-9223372036854775808) ELSE n END FROM seq`, 10)
+
+	page, err := iter.NextPage()
+	if err == nil {
+		t.Fatal("NextPage() expected an error")
+	}
+	if len(page) != 2 {
+		t.Errorf("NextPage() returned %d rows before the error, want 2", len(page))
+	}
+	if !iter.Done() {
+		t.Error("Done() = false after an error")
+	}
+}
@@ -0,0 +1,299 @@
+package db
+
+import (
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// SplitStatements breaks a script into the statements to run one by one.
+// Semicolons inside strings, quoted identifiers, comments, dollar quoted
+// bodies and BEGIN ... END blocks don't end a statement. SQL Server scripts
+// are split into batches on GO lines instead, Oracle PL/SQL blocks end on a
+// line holding a single / and MySQL scripts may change the delimiter with
+// DELIMITER, like their command line clients do
+func SplitStatements(script, dbType string) []string {
+	s := splitter{dbType: CanonicalDBType(dbType), delimiter: ";"}
+	s.split(script)
+	return s.statements
+}
+
+type splitter struct {
+	dbType     string
+	delimiter  string
+	statements []string
+
+	current strings.Builder
+	blocks  []string // Open BEGIN and CASE keywords
+	plsql   bool     // Oracle PL/SQL unit, only ended by a / line
+}
+
+func (s *splitter) split(script string) {
+	i := 0
+	for i < len(script) {
+		if s.atLineStart(script, i) {
+			if n, ok := s.lineCommand(script, i); ok {
+				i += n
+				continue
+			}
+		}
+
+		c := script[i]
+		switch {
+		case strings.HasPrefix(script[i:], "--"):
+			i += s.copyComment(script[i:], 2, "\n")
+		case c == '#' && s.dbType == "mysql":
+			i += s.copyComment(script[i:], 1, "\n")
+		case strings.HasPrefix(script[i:], "/*"):
+			i += s.copyComment(script[i:], 2, "*/")
+		case c == '\'' || c == '"' || c == '`':
+			i += s.copyQuoted(script[i:], c)
+		case c == '$' && (s.dbType == "postgres" || s.dbType == "duckdb"):
+			i += s.copyDollarQuoted(script[i:])
+		case s.dbType != "sqlserver" && !s.plsql && len(s.blocks) == 0 && strings.HasPrefix(script[i:], s.delimiter):
+			s.flush()
+			i += len(s.delimiter)
+		case isWordChar(c):
+			i += s.copyWord(script[i:])
+		default:
+			s.current.WriteByte(c)
+			i++
+		}
+	}
+	s.flush()
+}
+
+// atLineStart reports whether only blanks precede i on its line
+func (s *splitter) atLineStart(script string, i int) bool {
+	for j := i - 1; j >= 0 && script[j] != '\n'; j-- {
+		if script[j] != ' ' && script[j] != '\t' && script[j] != '\r' {
+			return false
+		}
+	}
+	return true
+}
+
+// lineCommand handles the client commands taking a whole line: GO for SQL
+// Server, / for Oracle and DELIMITER for MySQL. It returns the length of the
+// line it consumed
+func (s *splitter) lineCommand(script string, i int) (int, bool) {
+	line, _, _ := strings.Cut(script[i:], "\n")
+	n := len(line)
+	if n < len(script[i:]) {
+		n++ // The newline
+	}
+	fields := strings.Fields(line)
+	if len(fields) == 0 {
+		return 0, false
+	}
+
+	switch s.dbType {
+	case "sqlserver":
+		if strings.EqualFold(fields[0], "GO") && len(fields) <= 2 {
+			count := 1
+			if len(fields) == 2 {
+				parsed, err := strconv.Atoi(fields[1])
+				if err != nil || parsed < 1 {
+					return 0, false
+				}
+				count = parsed
+			}
+			batch := strings.TrimSpace(s.current.String())
+			for range count - 1 {
+				s.add(batch)
+			}
+			s.flush()
+			return n, true
+		}
+	case "oracle":
+		if len(fields) == 1 && fields[0] == "/" {
+			s.flush()
+			return n, true
+		}
+	case "mysql":
+		if strings.EqualFold(fields[0], "DELIMITER") && len(fields) == 2 && len(s.blocks) == 0 {
+			s.flush()
+			s.delimiter = fields[1]
+			return n, true
+		}
+	}
+	return 0, false
+}
+
+// copyComment copies a comment opened by the first open bytes of rest. Line
+// comments stop before the newline, block comments include their */
+func (s *splitter) copyComment(rest string, open int, terminator string) int {
+	end := strings.Index(rest[open:], terminator)
+	if end < 0 {
+		s.current.WriteString(rest)
+		return len(rest)
+	}
+	end += open
+	if terminator != "\n" {
+		end += len(terminator)
+	}
+	s.current.WriteString(rest[:end])
+	return end
+}
+
+// copyQuoted copies a string or quoted identifier. Doubled quotes escape
+// the quote everywhere, backslashes only in MySQL and ClickHouse
+func (s *splitter) copyQuoted(rest string, quote byte) int {
+	backslash := s.dbType == "mysql" || s.dbType == "clickhouse"
+	i := 1
+	for i < len(rest) {
+		switch {
+		case backslash && rest[i] == '\\':
+			i += 2
+			continue
+		case rest[i] == quote && i+1 < len(rest) && rest[i+1] == quote:
+			i += 2
+			continue
+		case rest[i] == quote:
+			i++
+			s.current.WriteString(rest[:i])
+			return i
+		}
+		i++
+	}
+	s.current.WriteString(rest)
+	return len(rest)
+}
+
+// copyDollarQuoted copies a $tag$ ... $tag$ body, or a lone $ like the one
+// of a $1 placeholder
+func (s *splitter) copyDollarQuoted(rest string) int {
+	end := strings.IndexByte(rest[1:], '$')
+	if end < 0 || !isDollarTag(rest[1:end+1]) {
+		s.current.WriteByte('$')
+		return 1
+	}
+	tag := rest[:end+2]
+	closing := strings.Index(rest[len(tag):], tag)
+	if closing < 0 {
+		s.current.WriteString(rest)
+		return len(rest)
+	}
+	n := len(tag) + closing + len(tag)
+	s.current.WriteString(rest[:n])
+	return n
+}
+
+func isDollarTag(tag string) bool {
+	for i, r := range tag {
+		if !(unicode.IsLetter(r) || r == '_' || i > 0 && unicode.IsDigit(r)) {
+			return false
+		}
+	}
+	return true
+}
+
+// copyWord copies a keyword or identifier, keeping track of the blocks it
+// opens and closes
+func (s *splitter) copyWord(rest string) int {
+	n := 0
+	for n < len(rest) && isWordChar(rest[n]) {
+		n++
+	}
+	word := strings.ToUpper(rest[:n])
+	next := strings.ToUpper(nextWord(rest[n:]))
+
+	if s.dbType == "oracle" && s.stripLeadingComments(s.current.String()) == "" {
+		s.plsql = word == "DECLARE" || word == "BEGIN" || word == "CREATE" && isPLSQLUnit(rest[n:])
+	}
+
+	switch word {
+	case "BEGIN":
+		// BEGIN alone, or BEGIN TRANSACTION and the like, starts a transaction
+		switch next {
+		case "", "TRANSACTION", "TRAN", "WORK", "DEFERRED", "IMMEDIATE", "EXCLUSIVE", "ISOLATION", "READ", "NOT", "DEFERRABLE":
+		default:
+			s.blocks = append(s.blocks, word)
+		}
+	case "CASE":
+		s.blocks = append(s.blocks, word)
+	case "END":
+		// END IF, END LOOP and friends close blocks that were never counted
+		switch next {
+		case "IF", "LOOP", "WHILE", "REPEAT", "FOR":
+		default:
+			if len(s.blocks) > 0 {
+				s.blocks = s.blocks[:len(s.blocks)-1]
+			}
+		}
+	}
+
+	s.current.WriteString(rest[:n])
+	return n
+}
+
+// nextWord returns the word following the blanks at the start of rest, or
+// "" when something else comes first
+func nextWord(rest string) string {
+	rest = strings.TrimLeft(rest, " \t\r\n")
+	n := 0
+	for n < len(rest) && isWordChar(rest[n]) {
+		n++
+	}
+	return rest[:n]
+}
+
+// isPLSQLUnit reports whether CREATE is followed by a PL/SQL unit like
+// OR REPLACE PROCEDURE, whose body holds semicolons
+func isPLSQLUnit(rest string) bool {
+	fields := strings.Fields(strings.ToUpper(rest))
+	for _, field := range fields {
+		switch field {
+		case "OR", "REPLACE", "EDITIONABLE", "NONEDITIONABLE":
+			continue
+		case "PROCEDURE", "FUNCTION", "PACKAGE", "TRIGGER", "TYPE":
+			return true
+		}
+		return false
+	}
+	return false
+}
+
+func isWordChar(c byte) bool {
+	return c == '_' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9'
+}
+
+// flush ends the current statement
+func (s *splitter) flush() {
+	s.add(s.current.String())
+	s.current.Reset()
+	s.blocks = nil
+	s.plsql = false
+}
+
+// add keeps a statement unless it holds nothing but blanks and comments.
+// Comments leading it are dropped so the statement is recognized by its
+// first keyword
+func (s *splitter) add(statement string) {
+	statement = s.stripLeadingComments(statement)
+	if statement != "" {
+		s.statements = append(s.statements, statement)
+	}
+}
+
+func (s *splitter) stripLeadingComments(sql string) string {
+	for {
+		sql = strings.TrimSpace(sql)
+		switch {
+		case strings.HasPrefix(sql, "--") || strings.HasPrefix(sql, "#") && s.dbType == "mysql":
+			_, rest, ok := strings.Cut(sql, "\n")
+			if !ok {
+				return ""
+			}
+			sql = rest
+		case strings.HasPrefix(sql, "/*"):
+			_, rest, ok := strings.Cut(sql, "*/")
+			if !ok {
+				return ""
+			}
+			sql = rest
+		default:
+			return sql
+		}
+	}
+}
@@ -0,0 +1,64 @@
+package db
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSplitStatements(t *testing.T) {
+	tests := []struct {
+		name   string
+		dbType string
+		script string
+		want   []string
+	}{
+		{
+			name:   "quotes and comments",
+			dbType: "sqlite",
+			script: "-- setup\nINSERT INTO t VALUES ('a;b', \"c;d\"); /* ; */\nSELECT 1;\n\n;",
+			want:   []string{"INSERT INTO t VALUES ('a;b', \"c;d\")", "SELECT 1"},
+		},
+		{
+			name:   "trigger body",
+			dbType: "sqlite",
+			script: "BEGIN;\nCREATE TRIGGER tr AFTER INSERT ON t BEGIN\n  UPDATE t SET n = CASE WHEN n > 0 THEN n END;\nEND;\nCOMMIT;",
+			want: []string{
+				"BEGIN",
+				"CREATE TRIGGER tr AFTER INSERT ON t BEGIN\n  UPDATE t SET n = CASE WHEN n > 0 THEN n END;\nEND",
+				"COMMIT",
+			},
+		},
+		{
+			name:   "dollar quoting",
+			dbType: "postgresql",
+			script: "CREATE FUNCTION f() RETURNS int AS $body$ SELECT 1; $body$ LANGUAGE sql;\nSELECT $1;",
+			want:   []string{"CREATE FUNCTION f() RETURNS int AS $body$ SELECT 1; $body$ LANGUAGE sql", "SELECT $1"},
+		},
+		{
+			name:   "mysql delimiter",
+			dbType: "mysql",
+			script: "DELIMITER //\nCREATE PROCEDURE p() BEGIN SELECT 'it\\'s'; END//\nDELIMITER ;\nCALL p();",
+			want:   []string{"CREATE PROCEDURE p() BEGIN SELECT 'it\\'s'; END", "CALL p()"},
+		},
+		{
+			name:   "sqlserver batches",
+			dbType: "mssql",
+			script: "DECLARE @n int; SET @n = 1;\nGO\nINSERT INTO t VALUES (1)\ngo 2\nSELECT 'GO'",
+			want:   []string{"DECLARE @n int; SET @n = 1;", "INSERT INTO t VALUES (1)", "INSERT INTO t VALUES (1)", "SELECT 'GO'"},
+		},
+		{
+			name:   "oracle blocks",
+			dbType: "oracle",
+			script: "CREATE OR REPLACE PROCEDURE p IS n NUMBER; BEGIN n := 1; END;\n/\nSELECT 1 FROM dual;",
+			want:   []string{"CREATE OR REPLACE PROCEDURE p IS n NUMBER; BEGIN n := 1; END;", "SELECT 1 FROM dual"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := SplitStatements(tt.script, tt.dbType); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("SplitStatements() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
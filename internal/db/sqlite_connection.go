@@ -3,6 +3,7 @@
 package db
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
 	"strings"
@@ -56,17 +57,22 @@ func (s *SQLiteConnection) Query(queryName string, args ...any) (any, error) {
 }
 
 func (s *SQLiteConnection) ExecQuery(
+	ctx context.Context,
 	sql string,
 	args ...any,
 ) (*sql.Rows, error) {
-	return s.db.Query(sql, args...)
+	return s.db.QueryContext(ctx, sql, args...)
 }
 
-func (s *SQLiteConnection) Exec(sql string, args ...any) error {
-	_, err := s.db.Exec(sql, args...)
+func (s *SQLiteConnection) Exec(ctx context.Context, sql string, args ...any) error {
+	_, err := s.db.ExecContext(ctx, sql, args...)
 	return err
 }
 
+func (s *SQLiteConnection) BeginTx(ctx context.Context) (*sql.Tx, error) {
+	return s.db.BeginTx(ctx, nil)
+}
+
 func (s *SQLiteConnection) GetTableMetadata(
 	tableName string,
 ) (*TableMetadata, error) {
@@ -86,6 +92,9 @@ func (s *SQLiteConnection) GetTableMetadata(
 		TableName: tableName,
 	}
 
+	keyColumns := 0
+	keyNames := map[int]string{}
+	keyType := ""
 	for rows.Next() {
 		var cid int
 		var name, colType string
@@ -105,12 +114,26 @@ func (s *SQLiteConnection) GetTableMetadata(
 
 		metadata.Columns = append(metadata.Columns, name)
 		metadata.ColumnTypes = append(metadata.ColumnTypes, colType)
+		metadata.ColumnDefaults = append(metadata.ColumnDefaults, dfltValue.String)
 
-		if pk == 1 {
-			metadata.PrimaryKeys = append(metadata.PrimaryKeys, name)
+		if pk > 0 {
+			keyNames[pk] = name
+			keyColumns++
+			keyType = colType
 		}
 	}
 
+	// pk is the position of the column in the key, which can differ from the
+	// column order in composite keys
+	for pos := 1; pos <= keyColumns; pos++ {
+		metadata.PrimaryKeys = append(metadata.PrimaryKeys, keyNames[pos])
+	}
+
+	// A lone INTEGER PRIMARY KEY is the rowid, assigned on insert
+	if keyColumns == 1 && strings.EqualFold(keyType, "INTEGER") {
+		metadata.GeneratedColumns = append(metadata.GeneratedColumns, metadata.PrimaryKeys...)
+	}
+
 	// Fetch foreign keys
 	fks, err := s.GetForeignKeys(tableName)
 	if err == nil {
@@ -336,40 +359,35 @@ func (s *SQLiteConnection) GetUniqueConstraints(tableName string) ([]string, err
 }
 
 func (s *SQLiteConnection) BuildUpdateStatement(
-	tableName, columnName, currentValue, pkColumn, pkValue string,
+	tableName, columnName string, currentValue Cell, pkColumns, pkValues, pkTypes []string,
 ) string {
-	escapedValue := strings.ReplaceAll(currentValue, "'", "''")
+	value := CellLiteral(currentValue)
 
-	if pkColumn != "" && pkValue != "" {
-		escapedPkValue := strings.ReplaceAll(pkValue, "'", "''")
+	if hasPrimaryKeyValues(pkColumns, pkValues) {
 		return fmt.Sprintf(
-			"-- SQLite UPDATE statement\nUPDATE %s\nSET %s = '%s'\nWHERE %s = '%s';",
+			"-- SQLite UPDATE statement\nUPDATE %s\nSET %s = %s\nWHERE %s;",
 			tableName,
 			columnName,
-			escapedValue,
-			pkColumn,
-			escapedPkValue,
+			value,
+			PrimaryKeyWhere(pkColumns, pkValues, pkTypes, s.GetDbType()),
 		)
 	}
 
 	return fmt.Sprintf(
-		"-- SQLite UPDATE statement\n-- No primary key specified. Edit WHERE clause manually.\nUPDATE %s\nSET %s = '%s'\nWHERE <condition>;",
+		"-- SQLite UPDATE statement\n-- No primary key specified. Edit WHERE clause manually.\nUPDATE %s\nSET %s = %s\nWHERE <condition>;",
 		tableName,
 		columnName,
-		escapedValue,
+		value,
 	)
 }
 
 func (s *SQLiteConnection) BuildDeleteStatement(
-	tableName, primaryKeyCol, pkValue string,
+	tableName string, pkColumns, pkValues, pkTypes []string,
 ) string {
-	escapedPkValue := strings.ReplaceAll(pkValue, "'", "''")
-
 	return fmt.Sprintf(
-		"-- SQLite DELETE statement\n-- WARNING: This will permanently delete data!\n-- Ensure the WHERE clause is correct.\n\nDELETE FROM %s\nWHERE %s = '%s';",
+		"-- SQLite DELETE statement\n-- WARNING: This will permanently delete data!\n-- Ensure the WHERE clause is correct.\n\nDELETE FROM %s\nWHERE %s;",
 		tableName,
-		primaryKeyCol,
-		escapedPkValue,
+		PrimaryKeyWhere(pkColumns, pkValues, pkTypes, s.GetDbType()),
 	)
 }
 
@@ -3,6 +3,7 @@
 package db
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
 )
@@ -32,14 +33,18 @@ func (oc *SQLiteConnection) Query(queryName string, args ...any) (any, error) {
 	return nil, fmt.Errorf("SQLite driver not available: binary built without CGO")
 }
 
-func (oc *SQLiteConnection) ExecQuery(sql string, args ...any) (*sql.Rows, error) {
+func (oc *SQLiteConnection) ExecQuery(ctx context.Context, sql string, args ...any) (*sql.Rows, error) {
 	return nil, fmt.Errorf("SQLite driver not available: binary built without CGO")
 }
 
-func (oc *SQLiteConnection) Exec(sql string, args ...any) error {
+func (oc *SQLiteConnection) Exec(ctx context.Context, sql string, args ...any) error {
 	return fmt.Errorf("SQLite driver not available: binary built without CGO")
 }
 
+func (oc *SQLiteConnection) BeginTx(ctx context.Context) (*sql.Tx, error) {
+	return nil, fmt.Errorf("SQLite driver not available: binary built without CGO")
+}
+
 func (oc *SQLiteConnection) GetTableMetadata(tableName string) (*TableMetadata, error) {
 	return nil, fmt.Errorf("SQLite driver not available: binary built without CGO")
 }
@@ -48,7 +53,7 @@ func (oc *SQLiteConnection) GetInfoSQL(infoType string) string {
 	return ""
 }
 
-func (oc *SQLiteConnection) BuildUpdateStatement(tableName, columnName, currentValue, pkColumn, pkValue string) string {
+func (oc *SQLiteConnection) BuildUpdateStatement(tableName, columnName string, currentValue Cell, pkColumns, pkValues, pkTypes []string) string {
 	return "-- SQLite driver not available: binary built without CGO"
 }
 
@@ -56,7 +61,11 @@ func (oc *SQLiteConnection) ApplyRowLimit(sql string, limit int) string {
 	return sql
 }
 
-func (oc *SQLiteConnection) BuildDeleteStatement(tableName, primaryKeyCol, pkValue string) string {
+func (oc *SQLiteConnection) BuildDeleteStatement(tableName string, pkColumns, pkValues, pkTypes []string) string {
+	return "-- SQLite driver not available: binary built without CGO"
+}
+
+func (oc *SQLiteConnection) BuildInsertStatement(tableName string, columns, values []string) string {
 	return "-- SQLite driver not available: binary built without CGO"
 }
 
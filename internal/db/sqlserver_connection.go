@@ -2,6 +2,7 @@
 package db
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
 	"strings"
@@ -55,15 +56,19 @@ func (s *SQLServerConnection) Query(queryName string, args ...any) (any, error)
 	return s.db.Query(query.SQL, args...)
 }
 
-func (s *SQLServerConnection) ExecQuery(sql string, args ...any) (*sql.Rows, error) {
-	return s.db.Query(sql, args...)
+func (s *SQLServerConnection) ExecQuery(ctx context.Context, sql string, args ...any) (*sql.Rows, error) {
+	return s.db.QueryContext(ctx, sql, args...)
 }
 
-func (s *SQLServerConnection) Exec(sql string, args ...any) error {
-	_, err := s.db.Exec(sql, args...)
+func (s *SQLServerConnection) Exec(ctx context.Context, sql string, args ...any) error {
+	_, err := s.db.ExecContext(ctx, sql, args...)
 	return err
 }
 
+func (s *SQLServerConnection) BeginTx(ctx context.Context) (*sql.Tx, error) {
+	return s.db.BeginTx(ctx, nil)
+}
+
 func (s *SQLServerConnection) GetTableMetadata(tableName string) (*TableMetadata, error) {
 	if s.db == nil {
 		return nil, fmt.Errorf("database is not open")
@@ -104,7 +109,7 @@ func (s *SQLServerConnection) GetTableMetadata(tableName string) (*TableMetadata
 		TableName: tableName,
 	}
 
-	if rows.Next() {
+	for rows.Next() {
 		var pkColumn string
 		if err := rows.Scan(&pkColumn); err == nil {
 			metadata.PrimaryKeys = append(metadata.PrimaryKeys, pkColumn)
@@ -122,7 +127,16 @@ func (s *SQLServerConnection) GetTableMetadata(tableName string) (*TableMetadata
 			       WHEN NUMERIC_PRECISION IS NOT NULL
 			       THEN '(' + CAST(NUMERIC_PRECISION AS VARCHAR) + ')'
 			       ELSE ''
-		       END as FULL_TYPE
+		       END as FULL_TYPE,
+		       COALESCE(COLUMN_DEFAULT, ''),
+		       CASE
+			       WHEN COLUMNPROPERTY(OBJECT_ID(QUOTENAME(TABLE_SCHEMA) + '.' + QUOTENAME(TABLE_NAME)), COLUMN_NAME, 'IsIdentity') = 1
+			         OR COLUMNPROPERTY(OBJECT_ID(QUOTENAME(TABLE_SCHEMA) + '.' + QUOTENAME(TABLE_NAME)), COLUMN_NAME, 'IsComputed') = 1
+			       THEN 1
+			       ELSE 0
+		       END as IS_GENERATED,
+		       COALESCE(COLUMNPROPERTY(OBJECT_ID(QUOTENAME(TABLE_SCHEMA) + '.' + QUOTENAME(TABLE_NAME)), COLUMN_NAME, 'IsComputed'), 0) as IS_COMPUTED,
+		       COALESCE(COLUMNPROPERTY(OBJECT_ID(QUOTENAME(TABLE_SCHEMA) + '.' + QUOTENAME(TABLE_NAME)), COLUMN_NAME, 'IsIdentity'), 0) as IS_IDENTITY
 		FROM INFORMATION_SCHEMA.COLUMNS
 		WHERE TABLE_NAME = @p1
 		  AND TABLE_SCHEMA = @p2
@@ -133,10 +147,21 @@ func (s *SQLServerConnection) GetTableMetadata(tableName string) (*TableMetadata
 	if err == nil {
 		defer colRows.Close()
 		for colRows.Next() {
-			var colName, colType string
-			if err := colRows.Scan(&colName, &colType); err == nil {
+			var colName, colType, colDefault string
+			var generated, computed, identity int
+			if err := colRows.Scan(&colName, &colType, &colDefault, &generated, &computed, &identity); err == nil {
 				metadata.Columns = append(metadata.Columns, colName)
 				metadata.ColumnTypes = append(metadata.ColumnTypes, colType)
+				metadata.ColumnDefaults = append(metadata.ColumnDefaults, colDefault)
+				if generated == 1 {
+					metadata.GeneratedColumns = append(metadata.GeneratedColumns, colName)
+				}
+				if computed == 1 {
+					metadata.ComputedColumns = append(metadata.ComputedColumns, colName)
+				}
+				if identity == 1 {
+					metadata.IdentityColumns = append(metadata.IdentityColumns, colName)
+				}
 			}
 		}
 	}
@@ -382,46 +407,59 @@ func (s *SQLServerConnection) GetViews() ([]string, error) {
 	return views, nil
 }
 
-func (s *SQLServerConnection) BuildUpdateStatement(tableName, columnName, currentValue, pkColumn, pkValue string) string {
+func (s *SQLServerConnection) BuildUpdateStatement(tableName, columnName string, currentValue Cell, pkColumns, pkValues, pkTypes []string) string {
 	quotedTableName := fmt.Sprintf("%s", tableName)
 	quotedColumnName := fmt.Sprintf("%s", columnName)
 
-	escapedValue := strings.ReplaceAll(currentValue, "'", "''")
+	value := CellLiteral(currentValue)
 
-	if pkColumn != "" && pkValue != "" {
-		quotedPkColumn := fmt.Sprintf("%s", pkColumn)
-		escapedPkValue := strings.ReplaceAll(pkValue, "'", "''")
+	if hasPrimaryKeyValues(pkColumns, pkValues) {
 		return fmt.Sprintf(
-			"-- SQL Server UPDATE statement\nUPDATE %s\nSET %s = '%s'\nWHERE %s = '%s';",
+			"-- SQL Server UPDATE statement\nUPDATE %s\nSET %s = %s\nWHERE %s;",
 			quotedTableName,
 			quotedColumnName,
-			escapedValue,
-			quotedPkColumn,
-			escapedPkValue,
+			value,
+			PrimaryKeyWhere(pkColumns, pkValues, pkTypes, s.GetDbType()),
 		)
 	}
 
 	return fmt.Sprintf(
-		"-- SQL Server UPDATE statement\n-- No primary key specified. Edit WHERE clause manually.\nUPDATE %s\nSET %s = '%s'\nWHERE <condition>;",
+		"-- SQL Server UPDATE statement\n-- No primary key specified. Edit WHERE clause manually.\nUPDATE %s\nSET %s = %s\nWHERE <condition>;",
 		quotedTableName,
 		quotedColumnName,
-		escapedValue,
+		value,
 	)
 }
 
-func (s *SQLServerConnection) BuildDeleteStatement(tableName, primaryKeyCol, pkValue string) string {
+func (s *SQLServerConnection) BuildDeleteStatement(tableName string, pkColumns, pkValues, pkTypes []string) string {
 	quotedTableName := fmt.Sprintf("%s", tableName)
-	quotedPkColumn := fmt.Sprintf("%s", primaryKeyCol)
-	escapedPkValue := strings.ReplaceAll(pkValue, "'", "''")
 
 	return fmt.Sprintf(
-		"-- SQL Server DELETE statement\n-- WARNING:  This will permanently delete data!\n-- Ensure the WHERE clause is correct.\n\nDELETE FROM %s\nWHERE %s = '%s';",
+		"-- SQL Server DELETE statement\n-- WARNING:  This will permanently delete data!\n-- Ensure the WHERE clause is correct.\n\nDELETE FROM %s\nWHERE %s;",
 		quotedTableName,
-		quotedPkColumn,
-		escapedPkValue,
+		PrimaryKeyWhere(pkColumns, pkValues, pkTypes, s.GetDbType()),
+	)
+}
+
+func (s *SQLServerConnection) BuildInsertStatement(tableName string, columns, values []string) string {
+	quotedColumns := make([]string, len(columns))
+	for i, col := range columns {
+		quotedColumns[i] = quoteSQLServerIdentifier(col)
+	}
+
+	return fmt.Sprintf(
+		"-- SQL Server INSERT statement\nINSERT INTO %s (%s)\nVALUES (%s);",
+		tableName,
+		strings.Join(quotedColumns, ", "),
+		strings.Join(values, ", "),
 	)
 }
 
+// quoteSQLServerIdentifier brackets a name so reserved words and spaces work
+func quoteSQLServerIdentifier(name string) string {
+	return "[" + strings.ReplaceAll(name, "]", "]]") + "]"
+}
+
 func (s *SQLServerConnection) GetPlaceholder(paramIndex int) string {
 	return "@p" + fmt.Sprintf("%d", paramIndex)
 }
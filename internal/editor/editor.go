@@ -15,14 +15,26 @@ func GetEditorCommand() string {
 }
 
 func EditTempFile(content, prefix string) (string, error) {
+	editedContent, _, err := EditTempFileSaved(content, prefix)
+	return editedContent, err
+}
+
+// EditTempFileSaved is EditTempFile telling whether the file was saved, so
+// quitting the editor without saving (e.g., :q! in vim) can cancel
+func EditTempFileSaved(content, prefix string) (string, bool, error) {
 	tmpFile, err := CreateTempFile(prefix, content)
 	if err != nil {
-		return "", fmt.Errorf("create temp file: %w", err)
+		return "", false, fmt.Errorf("create temp file: %w", err)
 	}
 	tmpPath := tmpFile.Name()
 	defer os.Remove(tmpPath)
 	tmpFile.Close()
 
+	before, err := os.Stat(tmpPath)
+	if err != nil {
+		return "", false, fmt.Errorf("stat temp file: %w", err)
+	}
+
 	editorCmd := GetEditorCommand()
 	cmd := exec.Command(editorCmd, tmpPath)
 	cmd.Stdin = os.Stdin
@@ -30,15 +42,19 @@ func EditTempFile(content, prefix string) (string, error) {
 	cmd.Stderr = os.Stderr
 
 	if err := cmd.Run(); err != nil {
-		return "", fmt.Errorf("run editor: %w", err)
+		return "", false, fmt.Errorf("run editor: %w", err)
 	}
 
+	after, err := os.Stat(tmpPath)
+	if err != nil {
+		return "", false, fmt.Errorf("stat edited file: %w", err)
+	}
 	editedContent, err := ReadTempFile(tmpPath)
 	if err != nil {
-		return "", fmt.Errorf("read edited file: %w", err)
+		return "", false, fmt.Errorf("read edited file: %w", err)
 	}
 
-	return editedContent, nil
+	return editedContent, after.ModTime().After(before.ModTime()), nil
 }
 
 func EditTempFileWithTemplate(template, prefix string) (string, error) {
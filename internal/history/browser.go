@@ -0,0 +1,338 @@
+package history
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/eduardofuncao/squix/internal/parser"
+	"github.com/eduardofuncao/squix/internal/styles"
+)
+
+type Action int
+
+const (
+	ActionNone Action = iota
+	ActionRun
+	ActionEdit
+	ActionSave
+)
+
+// Selection is what the user picked in the browser
+type Selection struct {
+	Action Action
+	Entry  Entry
+	Name   string // Query name, for ActionSave
+}
+
+type BrowserModel struct {
+	entries   []Entry // Newest first
+	matches   []int   // Indexes into entries matching the search
+	cursor    int
+	offset    int
+	search    string
+	searching bool
+	naming    bool
+	name      string
+	width     int
+	height    int
+	selection Selection
+}
+
+func NewBrowserModel(entries []Entry) BrowserModel {
+	newestFirst := make([]Entry, len(entries))
+	for i, entry := range entries {
+		newestFirst[len(entries)-1-i] = entry
+	}
+
+	m := BrowserModel{entries: newestFirst, height: 24, width: 80}
+	m.applySearch()
+	return m
+}
+
+func (m BrowserModel) Init() tea.Cmd {
+	return nil
+}
+
+func (m BrowserModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+		m.scrollToCursor()
+		return m, nil
+	case tea.KeyMsg:
+		if m.naming {
+			return m.updateNaming(msg)
+		}
+		if m.searching {
+			return m.updateSearch(msg)
+		}
+		return m.updateList(msg)
+	}
+	return m, nil
+}
+
+func (m BrowserModel) updateList(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "ctrl+c", "q":
+		return m, tea.Quit
+	case "esc":
+		if m.search != "" {
+			m.search = ""
+			m.applySearch()
+			return m, nil
+		}
+		return m, tea.Quit
+	case "down", "j":
+		if m.cursor < len(m.matches)-1 {
+			m.cursor++
+		}
+	case "up", "k":
+		if m.cursor > 0 {
+			m.cursor--
+		}
+	case "g":
+		m.cursor = 0
+	case "G":
+		m.cursor = max(len(m.matches)-1, 0)
+	case "/":
+		m.searching = true
+	case "enter", "r":
+		return m.choose(ActionRun)
+	case "e":
+		return m.choose(ActionEdit)
+	case "s":
+		if len(m.matches) > 0 {
+			m.naming = true
+			m.name = ""
+		}
+	}
+	m.scrollToCursor()
+	return m, nil
+}
+
+func (m BrowserModel) updateSearch(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.Type {
+	case tea.KeyCtrlC:
+		return m, tea.Quit
+	case tea.KeyEsc:
+		m.searching = false
+		m.search = ""
+	case tea.KeyEnter:
+		m.searching = false
+	case tea.KeyBackspace:
+		if len(m.search) > 0 {
+			m.search = m.search[:len(m.search)-1]
+		}
+	case tea.KeyRunes, tea.KeySpace:
+		m.search += string(msg.Runes)
+	}
+	m.applySearch()
+	return m, nil
+}
+
+func (m BrowserModel) updateNaming(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.Type {
+	case tea.KeyCtrlC:
+		return m, tea.Quit
+	case tea.KeyEsc:
+		m.naming = false
+	case tea.KeyEnter:
+		name := strings.TrimSpace(m.name)
+		if name == "" {
+			return m, nil
+		}
+		m.naming = false
+		m.selection.Name = name
+		return m.choose(ActionSave)
+	case tea.KeyBackspace:
+		if len(m.name) > 0 {
+			m.name = m.name[:len(m.name)-1]
+		}
+	case tea.KeyRunes:
+		m.name += string(msg.Runes)
+	}
+	return m, nil
+}
+
+func (m BrowserModel) choose(action Action) (tea.Model, tea.Cmd) {
+	if len(m.matches) == 0 {
+		return m, nil
+	}
+	m.selection.Action = action
+	m.selection.Entry = m.entries[m.matches[m.cursor]]
+	return m, tea.Quit
+}
+
+// applySearch keeps the entries whose SQL, query name, connection or error
+// contain every word of the search, ignoring case
+func (m *BrowserModel) applySearch() {
+	words := strings.Fields(strings.ToLower(m.search))
+	m.matches = nil
+	for i, entry := range m.entries {
+		haystack := strings.ToLower(strings.Join([]string{entry.SQL, entry.QueryName, entry.Connection, entry.Error}, " "))
+		matched := true
+		for _, word := range words {
+			if !strings.Contains(haystack, word) {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			m.matches = append(m.matches, i)
+		}
+	}
+	m.cursor = 0
+	m.offset = 0
+}
+
+func (m BrowserModel) listHeight() int {
+	// Title, search line, detail pane and footer take the rest
+	return max(m.height-14, 3)
+}
+
+func (m *BrowserModel) scrollToCursor() {
+	visible := m.listHeight()
+	if m.cursor < m.offset {
+		m.offset = m.cursor
+	}
+	if m.cursor >= m.offset+visible {
+		m.offset = m.cursor - visible + 1
+	}
+}
+
+func (m BrowserModel) View() string {
+	var b strings.Builder
+
+	b.WriteString(styles.Title.Render("Query history"))
+	b.WriteString(styles.Faint.Render(fmt.Sprintf("  %d/%d", len(m.matches), len(m.entries))))
+	b.WriteString("\n")
+
+	if m.searching || m.search != "" {
+		cursor := ""
+		if m.searching {
+			cursor = "▏"
+		}
+		b.WriteString(styles.Title.Render("/") + m.search + cursor)
+	}
+	b.WriteString("\n")
+
+	if len(m.matches) == 0 {
+		b.WriteString(styles.Faint.Render("No matching entries"))
+		b.WriteString("\n")
+	}
+
+	end := min(m.offset+m.listHeight(), len(m.matches))
+	for i := m.offset; i < end; i++ {
+		line := m.renderEntryLine(m.entries[m.matches[i]], i == m.cursor)
+		b.WriteString(line)
+		b.WriteString("\n")
+	}
+
+	b.WriteString("\n")
+	if len(m.matches) > 0 {
+		b.WriteString(m.renderDetail(m.entries[m.matches[m.cursor]]))
+	}
+	b.WriteString("\n")
+
+	if m.naming {
+		b.WriteString(styles.Title.Render("Save as > ") + m.name + "▏")
+		b.WriteString("\n")
+		b.WriteString(styles.Faint.Render("Enter: save  Esc: cancel"))
+	} else {
+		b.WriteString(styles.Faint.Render("↑/k ↓/j: move  /: search  Enter/r: re-run  e: edit & run  s: save as query  q: quit"))
+	}
+
+	return b.String()
+}
+
+func (m BrowserModel) renderEntryLine(entry Entry, selected bool) string {
+	status := "✓"
+	rows := fmt.Sprintf("%6d rows", entry.Rows)
+	if entry.Failed() {
+		status = "✗"
+		rows = fmt.Sprintf("%11s", "")
+	}
+
+	sql := strings.Join(strings.Fields(entry.SQL), " ")
+	prefix := fmt.Sprintf("%s %s  %-12s %8s %s  ",
+		status,
+		entry.Time.Local().Format("2006-01-02 15:04"),
+		truncate(entry.Connection, 12),
+		formatDuration(entry.Duration),
+		rows,
+	)
+
+	line := prefix + truncate(sql, max(m.width-len([]rune(prefix)), 10))
+	switch {
+	case selected:
+		return styles.TableSelected.Render(line)
+	case entry.Failed():
+		return styles.Error.Render(status) + line[len(status):]
+	default:
+		return styles.Success.Render(status) + line[len(status):]
+	}
+}
+
+func (m BrowserModel) renderDetail(entry Entry) string {
+	var b strings.Builder
+
+	if entry.QueryName != "" {
+		b.WriteString(styles.Faint.Render("query: ") + entry.QueryName + "\n")
+	}
+	b.WriteString(parser.HighlightSQL(truncate(parser.FormatSQLWithLineBreaks(entry.SQL), 600)))
+	b.WriteString("\n")
+	if len(entry.Params) > 0 {
+		b.WriteString(styles.Faint.Render("params: ") + strings.Join(entry.Params, ", ") + "\n")
+	}
+	if len(entry.Values) > 0 {
+		names := slices.Sorted(maps.Keys(entry.Values))
+		values := make([]string, len(names))
+		for i, name := range names {
+			values[i] = name + "=" + entry.Values[name]
+		}
+		b.WriteString(styles.Faint.Render("params: ") + strings.Join(values, ", ") + "\n")
+	}
+	if entry.Failed() {
+		b.WriteString(styles.Error.Render("✗ " + entry.Error))
+		b.WriteString("\n")
+	}
+
+	return b.String()
+}
+
+func (m BrowserModel) GetSelection() Selection {
+	return m.selection
+}
+
+// Browse opens the history browser and returns the entry picked by the user,
+// with ActionNone if they quit without picking one
+func Browse(entries []Entry) (Selection, error) {
+	program := tea.NewProgram(NewBrowserModel(entries), tea.WithAltScreen())
+
+	finalModel, err := program.Run()
+	if err != nil {
+		return Selection{}, err
+	}
+
+	return finalModel.(BrowserModel).GetSelection(), nil
+}
+
+func formatDuration(d time.Duration) string {
+	return fmt.Sprintf("%.2fs", d.Seconds())
+}
+
+func truncate(s string, n int) string {
+	r := []rune(s)
+	if len(r) <= n {
+		return s
+	}
+	if n <= 1 {
+		return string(r[:n])
+	}
+	return string(r[:n-1]) + "…"
+}
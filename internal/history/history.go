@@ -0,0 +1,189 @@
+package history
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/eduardofuncao/squix/internal/config"
+)
+
+var HistoryFile = filepath.Join(config.CfgPath, "history.jsonl")
+
+// Entry is a single query execution, stored as one JSON line
+type Entry struct {
+	Time       time.Time         `json:"time"`
+	Connection string            `json:"connection"`
+	QueryName  string            `json:"query_name,omitempty"`
+	SQL        string            `json:"sql"`
+	Params     []string          `json:"params,omitempty"` // Values in placeholder order, from before Values
+	Values     map[string]string `json:"values,omitempty"` // Values of the :name parameters in SQL
+	Duration   time.Duration     `json:"duration"`
+	Rows       int               `json:"rows"`
+	Error      string            `json:"error,omitempty"`
+}
+
+func (e Entry) Failed() bool {
+	return e.Error != ""
+}
+
+type Filter struct {
+	Connection string
+	FailedOnly bool
+	Since      time.Time
+}
+
+func (f Filter) Match(e Entry) bool {
+	if f.Connection != "" && e.Connection != f.Connection {
+		return false
+	}
+	if f.FailedOnly && !e.Failed() {
+		return false
+	}
+	if !f.Since.IsZero() && e.Time.Before(f.Since) {
+		return false
+	}
+	return true
+}
+
+// Load reads every entry from the history file, oldest first. A missing file
+// is an empty history
+func Load() ([]Entry, error) {
+	file, err := os.Open(HistoryFile)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	defer file.Close()
+
+	var entries []Entry
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		line := scanner.Bytes()
+		if len(line) == 0 {
+			continue
+		}
+		var entry Entry
+		// Skip lines that were cut short, e.g. by a crash while writing
+		if err := json.Unmarshal(line, &entry); err != nil {
+			continue
+		}
+		entries = append(entries, entry)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("could not read history: %w", err)
+	}
+
+	return entries, nil
+}
+
+// Append records an entry as a line added to the end of the history file,
+// so squix running in several terminals doesn't lose entries. Once the file
+// holds a tenth more than size entries, it is trimmed to the newest size
+func Append(entry Entry, size int) error {
+	if size < 0 {
+		return nil
+	}
+	if err := os.MkdirAll(filepath.Dir(HistoryFile), 0755); err != nil {
+		return fmt.Errorf("failed to create config directory: %w", err)
+	}
+
+	line, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	file, err := os.OpenFile(HistoryFile, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+	// A single write, so lines of concurrent appends don't interleave
+	if _, err := file.Write(append(line, '\n')); err != nil {
+		file.Close()
+		return err
+	}
+	if err := file.Close(); err != nil {
+		return err
+	}
+
+	if size == 0 {
+		return nil
+	}
+	entries, err := Load()
+	if err != nil {
+		return err
+	}
+	if len(entries) <= size+max(size/10, 1) {
+		return nil
+	}
+	return save(entries[len(entries)-size:])
+}
+
+func save(entries []Entry) error {
+	var buf strings.Builder
+	for _, entry := range entries {
+		line, err := json.Marshal(entry)
+		if err != nil {
+			return err
+		}
+		buf.Write(line)
+		buf.WriteString("\n")
+	}
+
+	// Write to a temp file of its own first, so a crash can't leave a
+	// truncated history and another squix trimming at once can't clobber it
+	tmp, err := os.CreateTemp(filepath.Dir(HistoryFile), "history-*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.WriteString(buf.String()); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), HistoryFile)
+}
+
+// ParseSince accepts a duration relative to now (30m, 12h, 7d, 2w) or a date
+// (2006-01-02, 2006-01-02 15:04)
+func ParseSince(value string, now time.Time) (time.Time, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return time.Time{}, nil
+	}
+
+	for _, layout := range []string{"2006-01-02", "2006-01-02 15:04", time.RFC3339} {
+		if t, err := time.ParseInLocation(layout, value, time.Local); err == nil {
+			return t, nil
+		}
+	}
+
+	// time.ParseDuration has no unit for days or weeks
+	unit := value[len(value)-1]
+	if unit == 'd' || unit == 'w' {
+		n, err := strconv.Atoi(value[:len(value)-1])
+		if err == nil && n >= 0 {
+			days := n
+			if unit == 'w' {
+				days = n * 7
+			}
+			return now.AddDate(0, 0, -days), nil
+		}
+	}
+
+	if d, err := time.ParseDuration(value); err == nil && d >= 0 {
+		return now.Add(-d), nil
+	}
+
+	return time.Time{}, fmt.Errorf("invalid --since value '%s' (expected e.g. 2h, 7d, 2w or 2006-01-02)", value)
+}
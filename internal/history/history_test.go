@@ -0,0 +1,76 @@
+package history
+
+import (
+	"fmt"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestParseSince(t *testing.T) {
+	now := time.Date(2024, 3, 10, 12, 0, 0, 0, time.Local)
+
+	tests := []struct {
+		name    string
+		value   string
+		want    time.Time
+		wantErr bool
+	}{
+		{name: "Empty", value: "", want: time.Time{}},
+		{name: "Minutes", value: "30m", want: now.Add(-30 * time.Minute)},
+		{name: "Hours and minutes", value: "1h30m", want: now.Add(-90 * time.Minute)},
+		{name: "Days", value: "7d", want: now.AddDate(0, 0, -7)},
+		{name: "Weeks", value: " 2w ", want: now.AddDate(0, 0, -14)},
+		{name: "Date", value: "2024-03-01", want: time.Date(2024, 3, 1, 0, 0, 0, 0, time.Local)},
+		{name: "Date and time", value: "2024-03-01 15:04", want: time.Date(2024, 3, 1, 15, 4, 0, 0, time.Local)},
+		{name: "RFC 3339", value: "2024-03-01T15:04:05Z", want: time.Date(2024, 3, 1, 15, 4, 5, 0, time.UTC)},
+		{name: "Negative duration", value: "-2h", wantErr: true},
+		{name: "Negative days", value: "-3d", wantErr: true},
+		{name: "Unknown unit", value: "3y", wantErr: true},
+		{name: "Not a date", value: "2024-13-45", wantErr: true},
+		{name: "Words", value: "yesterday", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseSince(tt.value, now)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseSince(%q) error = %v, wantErr %v", tt.value, err, tt.wantErr)
+			}
+			if !tt.wantErr && !got.Equal(tt.want) {
+				t.Errorf("ParseSince(%q) = %v, want %v", tt.value, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAppendTrimsOldestEntries(t *testing.T) {
+	oldFile := HistoryFile
+	HistoryFile = filepath.Join(t.TempDir(), "history.jsonl")
+	t.Cleanup(func() { HistoryFile = oldFile })
+
+	for i := 1; i <= 11; i++ {
+		if err := Append(Entry{SQL: fmt.Sprintf("select %d", i)}, 10); err != nil {
+			t.Fatalf("Append() error = %v", err)
+		}
+	}
+	entries, err := Load()
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if len(entries) != 11 {
+		t.Fatalf("len(entries) = %d before the slack is used up, want 11", len(entries))
+	}
+
+	if err := Append(Entry{SQL: "select 12"}, 10); err != nil {
+		t.Fatalf("Append() error = %v", err)
+	}
+	entries, err = Load()
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if len(entries) != 10 || entries[0].SQL != "select 3" || entries[9].SQL != "select 12" {
+		t.Errorf("entries after trimming = %d, from %q to %q, want 10 from \"select 3\" to \"select 12\"",
+			len(entries), entries[0].SQL, entries[len(entries)-1].SQL)
+	}
+}
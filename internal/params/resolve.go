@@ -48,13 +48,19 @@ func ValidateCLIValues(cliValues, paramDefs map[string]string) error {
 
 // Reserved flags that cannot be used as parameter names
 var reservedFlags = map[string]bool{
-	"edit":    true,
-	"last":    true,
-	"l":       true,
-	"help":    true,
-	"h":       true,
-	"version": true,
-	"v":       true,
+	"edit":          true,
+	"last":          true,
+	"format":        true,
+	"continue":      true,
+	"stop-on-error": true,
+	"l":             true,
+	"help":          true,
+	"connection":    true,
+	"config":        true,
+	"no-color":      true,
+	"h":             true,
+	"version":       true,
+	"v":             true,
 }
 
 func ValidateParamNames(paramDefs map[string]string) error {
@@ -22,53 +22,49 @@ func SubstituteParameters(sql string, paramValues map[string]string, conn db.Dat
 		return sql, []any{}, nil
 	}
 
-	// Build ordered list of parameter values based on occurrence in SQL
+	// Numbered placeholders like $1 can repeat, so each param gets one
+	// index. Oracle and ? placeholders bind by position of occurrence, so a
+	// param used twice needs its value twice
+	positional := conn.GetPlaceholder(1) == conn.GetPlaceholder(2) || db.CanonicalDBType(conn.GetDbType()) == "oracle"
+
 	var orderedValues []any
+	var placeholders []string
 	paramIndex := make(map[string]int) // Maps param name to its index (1-based)
-	currentIndex := 1
 
-	// Process matches in order of first appearance
 	for _, match := range matches {
 		// match[2:4] = group 1 (param name)
 		paramName := sql[match[2]:match[3]]
 
-		// Only add each param once (in order of first appearance)
-		if _, exists := paramIndex[paramName]; !exists {
-			if value, ok := paramValues[paramName]; ok {
-				paramIndex[paramName] = currentIndex
-				orderedValues = append(orderedValues, value)
-				currentIndex++
-			} else {
-				return "", nil, fmt.Errorf("missing value for parameter: %s", paramName)
-			}
+		value, ok := paramValues[paramName]
+		if !ok {
+			return "", nil, fmt.Errorf("missing value for parameter: %s", paramName)
+		}
+		index, exists := paramIndex[paramName]
+		if !exists || positional {
+			orderedValues = append(orderedValues, value)
+			index = len(orderedValues)
+			paramIndex[paramName] = index
 		}
+		placeholders = append(placeholders, conn.GetPlaceholder(index))
 	}
 
 	// Now replace :param|default or :param with appropriate placeholders
-	result := replaceParamPlaceholders(sql, conn, paramIndex)
+	result := replaceParamPlaceholders(sql, placeholders)
 
 	return result, orderedValues, nil
 }
 
-// replaceParamPlaceholders replaces all :param|default or :param with DB-specific placeholders
-func replaceParamPlaceholders(sql string, conn db.DatabaseConnection, paramIndex map[string]int) string {
+// replaceParamPlaceholders replaces the nth :param|default or :param with
+// the nth of placeholders
+func replaceParamPlaceholders(sql string, placeholders []string) string {
 	// Use same regex as initial extraction to handle quoted strings
 	re := regexp.MustCompile(`:(\w+)(?:\|('(?:[^'\\]|\\.)*'|(?:[^'\s\\]+)))?`)
 
-	// Replace all matches at once using ReplaceAllStringFunc
+	next := 0
 	result := re.ReplaceAllStringFunc(sql, func(match string) string {
-		// Extract param name
-		paramName := strings.TrimPrefix(match, ":")
-		if pipeIdx := strings.Index(paramName, "|"); pipeIdx != -1 {
-			paramName = paramName[:pipeIdx]
-		}
-
-		// Get placeholder for this param
-		if index, ok := paramIndex[paramName]; ok {
-			return conn.GetPlaceholder(index)
-		}
-
-		return match
+		placeholder := placeholders[next]
+		next++
+		return placeholder
 	})
 
 	return result
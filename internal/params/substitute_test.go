@@ -0,0 +1,47 @@
+package params
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/eduardofuncao/squix/internal/db"
+)
+
+func TestSubstituteParameters(t *testing.T) {
+	postgres, _ := db.NewPostgresConnection("pg", "")
+	oracle, _ := db.NewOracleConnection("ora", "")
+	mysql, _ := db.NewMySQLConnection("my", "")
+
+	sql := "SELECT * FROM t WHERE a = :id OR b = :id|7 AND c = :name"
+	values := map[string]string{"id": "1", "name": "ann"}
+
+	tests := []struct {
+		name string
+		conn db.DatabaseConnection
+		want string
+		args []any
+	}{
+		{name: "Numbered", conn: postgres, want: "SELECT * FROM t WHERE a = $1 OR b = $1 AND c = $2", args: []any{"1", "ann"}},
+		{name: "Oracle", conn: oracle, want: "SELECT * FROM t WHERE a = :1 OR b = :2 AND c = :3", args: []any{"1", "1", "ann"}},
+		{name: "Question mark", conn: mysql, want: "SELECT * FROM t WHERE a = ? OR b = ? AND c = ?", args: []any{"1", "1", "ann"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, args, err := SubstituteParameters(sql, values, tt.conn)
+			if err != nil {
+				t.Fatalf("SubstituteParameters() error: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("SubstituteParameters() = %q, want %q", got, tt.want)
+			}
+			if !reflect.DeepEqual(args, tt.args) {
+				t.Errorf("SubstituteParameters() args = %#v, want %#v", args, tt.args)
+			}
+		})
+	}
+
+	if _, _, err := SubstituteParameters(sql, map[string]string{"id": "1"}, postgres); err == nil {
+		t.Error("SubstituteParameters() expected an error for a missing value")
+	}
+}
@@ -0,0 +1,104 @@
+package picker
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/eduardofuncao/squix/internal/db"
+)
+
+// match is a query that passed the filter, with the positions of the
+// matched runes in its name for highlighting
+type match struct {
+	query   db.Query
+	score   int
+	namePos map[int]bool
+}
+
+// fuzzyScore reports whether every rune of pattern appears in text in order,
+// ignoring case. Consecutive runes and runes at the start of a word score
+// higher, so "usr" ranks "users_report" above "sum_of_revenues"
+func fuzzyScore(pattern, text string) (int, []int, bool) {
+	p := []rune(strings.ToLower(pattern))
+	t := []rune(strings.ToLower(text))
+	if len(p) == 0 {
+		return 0, nil, true
+	}
+
+	score := 0
+	positions := make([]int, 0, len(p))
+	pi := 0
+	prev := -2
+	for ti := 0; ti < len(t) && pi < len(p); ti++ {
+		if t[ti] != p[pi] {
+			continue
+		}
+		score++
+		if ti == prev+1 {
+			score += 4
+		}
+		if ti == 0 || !unicode.IsLetter(t[ti-1]) && !unicode.IsDigit(t[ti-1]) {
+			score += 3
+		}
+		positions = append(positions, ti)
+		prev = ti
+		pi++
+	}
+
+	if pi < len(p) {
+		return 0, nil, false
+	}
+	// Prefer tighter matches
+	score -= (positions[len(positions)-1] - positions[0]) / 4
+	return score, positions, true
+}
+
+// filterQueries keeps the queries matching every word of the filter in their
+// name, id or SQL, best matches first. An empty filter keeps all of them,
+// ordered by id
+func filterQueries(queries []db.Query, filter string) []match {
+	terms := strings.Fields(filter)
+	var matches []match
+
+	for _, q := range queries {
+		m := match{query: q, namePos: map[int]bool{}}
+		matched := true
+
+		for _, term := range terms {
+			best := -1
+			if q.Id >= 0 && strconv.Itoa(q.Id) == term {
+				best = 50
+			}
+			// Name matches weigh more than matches deep inside the SQL
+			if score, positions, ok := fuzzyScore(term, q.Name); ok {
+				best = max(best, score*2)
+				for _, pos := range positions {
+					m.namePos[pos] = true
+				}
+			}
+			if score, _, ok := fuzzyScore(term, q.SQL); ok {
+				best = max(best, score)
+			}
+
+			if best < 0 {
+				matched = false
+				break
+			}
+			m.score += best
+		}
+
+		if matched {
+			matches = append(matches, m)
+		}
+	}
+
+	sort.SliceStable(matches, func(i, j int) bool {
+		if matches[i].score != matches[j].score {
+			return matches[i].score > matches[j].score
+		}
+		return matches[i].query.Id < matches[j].query.Id
+	})
+	return matches
+}
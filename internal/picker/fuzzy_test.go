@@ -0,0 +1,88 @@
+package picker
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/eduardofuncao/squix/internal/db"
+)
+
+func TestFuzzyScore(t *testing.T) {
+	tests := []struct {
+		name      string
+		pattern   string
+		text      string
+		positions []int
+		ok        bool
+	}{
+		{name: "Empty pattern", pattern: "", text: "users", ok: true},
+		{name: "Prefix", pattern: "use", text: "users", positions: []int{0, 1, 2}, ok: true},
+		{name: "Ignores case", pattern: "UR", text: "users_report", positions: []int{0, 3}, ok: true},
+		{name: "Out of order", pattern: "sru", text: "users", ok: false},
+		{name: "Missing rune", pattern: "usx", text: "users", ok: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, positions, ok := fuzzyScore(tt.pattern, tt.text)
+			if ok != tt.ok || !slices.Equal(positions, tt.positions) {
+				t.Errorf("fuzzyScore(%q, %q) = %v, %v, want %v, %v", tt.pattern, tt.text, positions, ok, tt.positions, tt.ok)
+			}
+		})
+	}
+}
+
+func TestFuzzyScoreOrdering(t *testing.T) {
+	tests := []struct {
+		pattern string
+		better  string
+		worse   string
+	}{
+		{pattern: "usr", better: "usr_totals", worse: "users_report"},
+		{pattern: "rep", better: "report", worse: "users_report"},
+		{pattern: "ord", better: "orders", worse: "food_records"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.pattern, func(t *testing.T) {
+			better, _, ok1 := fuzzyScore(tt.pattern, tt.better)
+			worse, _, ok2 := fuzzyScore(tt.pattern, tt.worse)
+			if !ok1 || !ok2 || better <= worse {
+				t.Errorf("fuzzyScore(%q): %q = %d, %q = %d, want the first higher", tt.pattern, tt.better, better, tt.worse, worse)
+			}
+		})
+	}
+}
+
+func TestFilterQueries(t *testing.T) {
+	queries := []db.Query{
+		{Name: "daily_orders", Id: 1, SQL: "SELECT * FROM orders WHERE day = :day"},
+		{Name: "users", Id: 2, SQL: "SELECT * FROM users"},
+		{Name: "user_orders", Id: 3, SQL: "SELECT * FROM orders JOIN users USING (user_id)"},
+		{Name: "revenue", Id: 4, SQL: "SELECT sum(total) FROM orders"},
+	}
+
+	tests := []struct {
+		name   string
+		filter string
+		want   []string
+	}{
+		{name: "Empty filter keeps id order", filter: "", want: []string{"daily_orders", "users", "user_orders", "revenue"}},
+		{name: "Name matches before SQL matches", filter: "orders", want: []string{"daily_orders", "user_orders", "revenue"}},
+		{name: "Every word must match", filter: "user ord", want: []string{"user_orders"}},
+		{name: "Id", filter: "4", want: []string{"revenue"}},
+		{name: "No match", filter: "zzz", want: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for _, m := range filterQueries(queries, tt.filter) {
+				got = append(got, m.query.Name)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("filterQueries(%q) = %v, want %v", tt.filter, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,277 @@
+package picker
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/atotto/clipboard"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/eduardofuncao/squix/internal/db"
+	"github.com/eduardofuncao/squix/internal/parser"
+	"github.com/eduardofuncao/squix/internal/styles"
+)
+
+type Action int
+
+const (
+	ActionNone Action = iota
+	ActionRun
+	ActionEdit
+	ActionDelete
+	ActionNew
+)
+
+// Selection is what the user picked, Query is empty for ActionNew and ActionNone
+type Selection struct {
+	Action Action
+	Query  db.Query
+}
+
+type Model struct {
+	title      string
+	queries    []db.Query
+	matches    []match
+	filter     string
+	cursor     int
+	offset     int
+	confirming bool
+	status     string
+	width      int
+	height     int
+	selection  Selection
+}
+
+func NewModel(title string, queries map[string]db.Query) Model {
+	sorted := make([]db.Query, 0, len(queries))
+	for _, q := range queries {
+		sorted = append(sorted, q)
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].Id < sorted[j].Id
+	})
+
+	m := Model{title: title, queries: sorted, height: 24, width: 80}
+	m.applyFilter()
+	return m
+}
+
+func (m Model) Init() tea.Cmd {
+	return nil
+}
+
+func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+		m.scrollToCursor()
+		return m, nil
+	case tea.KeyMsg:
+		m.status = ""
+		if m.confirming {
+			return m.updateConfirm(msg)
+		}
+		return m.updateList(msg)
+	}
+	return m, nil
+}
+
+// Typing always goes to the filter, so actions live on control keys
+func (m Model) updateList(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.Type {
+	case tea.KeyCtrlC:
+		return m, tea.Quit
+	case tea.KeyEsc:
+		if m.filter != "" {
+			m.filter = ""
+			m.applyFilter()
+			return m, nil
+		}
+		return m, tea.Quit
+	case tea.KeyDown, tea.KeyCtrlJ:
+		if m.cursor < len(m.matches)-1 {
+			m.cursor++
+		}
+	case tea.KeyUp, tea.KeyCtrlK:
+		if m.cursor > 0 {
+			m.cursor--
+		}
+	case tea.KeyEnter:
+		return m.choose(ActionRun)
+	case tea.KeyCtrlE:
+		return m.choose(ActionEdit)
+	case tea.KeyCtrlD:
+		if len(m.matches) > 0 {
+			m.confirming = true
+		}
+	case tea.KeyCtrlY:
+		m.copySelected()
+	case tea.KeyCtrlN:
+		m.selection.Action = ActionNew
+		return m, tea.Quit
+	case tea.KeyBackspace:
+		if len(m.filter) > 0 {
+			runes := []rune(m.filter)
+			m.filter = string(runes[:len(runes)-1])
+			m.applyFilter()
+		}
+	case tea.KeyRunes, tea.KeySpace:
+		m.filter += string(msg.Runes)
+		m.applyFilter()
+	}
+	m.scrollToCursor()
+	return m, nil
+}
+
+func (m Model) updateConfirm(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "ctrl+c":
+		return m, tea.Quit
+	case "y", "Y":
+		m.confirming = false
+		return m.choose(ActionDelete)
+	default:
+		m.confirming = false
+	}
+	return m, nil
+}
+
+func (m Model) choose(action Action) (tea.Model, tea.Cmd) {
+	if len(m.matches) == 0 {
+		return m, nil
+	}
+	m.selection.Action = action
+	m.selection.Query = m.matches[m.cursor].query
+	return m, tea.Quit
+}
+
+func (m *Model) copySelected() {
+	if len(m.matches) == 0 {
+		return
+	}
+	q := m.matches[m.cursor].query
+	if err := clipboard.WriteAll(q.SQL); err != nil {
+		m.status = styles.Error.Render("✗ Could not copy: " + err.Error())
+		return
+	}
+	m.status = styles.Success.Render(fmt.Sprintf("✓ Copied SQL of '%s'", q.Name))
+}
+
+func (m *Model) applyFilter() {
+	m.matches = filterQueries(m.queries, m.filter)
+	m.cursor = 0
+	m.offset = 0
+}
+
+func (m Model) listHeight() int {
+	// Half of the screen for the list, the rest for the preview
+	return max((m.height-6)/2, 3)
+}
+
+func (m *Model) scrollToCursor() {
+	visible := m.listHeight()
+	if m.cursor < m.offset {
+		m.offset = m.cursor
+	}
+	if m.cursor >= m.offset+visible {
+		m.offset = m.cursor - visible + 1
+	}
+}
+
+func (m Model) View() string {
+	var b strings.Builder
+
+	b.WriteString(styles.Title.Render(m.title))
+	b.WriteString(styles.Faint.Render(fmt.Sprintf("  %d/%d", len(m.matches), len(m.queries))))
+	b.WriteString("\n")
+	b.WriteString(styles.Title.Render("> ") + m.filter + "▏")
+	b.WriteString("\n")
+
+	if len(m.matches) == 0 {
+		b.WriteString(styles.Faint.Render("No matching queries"))
+		b.WriteString("\n")
+	}
+
+	end := min(m.offset+m.listHeight(), len(m.matches))
+	for i := m.offset; i < end; i++ {
+		b.WriteString(m.renderLine(m.matches[i], i == m.cursor))
+		b.WriteString("\n")
+	}
+
+	b.WriteString("\n")
+	if len(m.matches) > 0 {
+		b.WriteString(m.renderPreview(m.matches[m.cursor].query))
+	}
+	b.WriteString("\n")
+
+	switch {
+	case m.confirming:
+		name := m.matches[m.cursor].query.Name
+		b.WriteString(styles.Error.Render(fmt.Sprintf("Delete query '%s'? [y/N]", name)))
+	case m.status != "":
+		b.WriteString(m.status)
+	default:
+		b.WriteString(styles.Faint.Render("↑/↓: move  Enter: run  Ctrl+e: edit & run  Ctrl+d: delete  Ctrl+y: copy SQL  Ctrl+n: new query  Esc: quit"))
+	}
+
+	return b.String()
+}
+
+func (m Model) renderLine(mt match, selected bool) string {
+	id := fmt.Sprintf("%4d  ", mt.query.Id)
+	sql := strings.Join(strings.Fields(mt.query.SQL), " ")
+	room := max(m.width-len(id)-len([]rune(mt.query.Name))-2, 10)
+
+	if selected {
+		return styles.TableSelected.Render(id + mt.query.Name + "  " + truncate(sql, room))
+	}
+
+	var name strings.Builder
+	for i, r := range []rune(mt.query.Name) {
+		if mt.namePos[i] {
+			name.WriteString(styles.SearchMatch.Render(string(r)))
+		} else {
+			name.WriteRune(r)
+		}
+	}
+	return styles.Faint.Render(id) + styles.Title.Render(name.String()) + "  " + styles.Faint.Render(truncate(sql, room))
+}
+
+func (m Model) renderPreview(q db.Query) string {
+	lines := strings.Split(parser.FormatSQLWithLineBreaks(q.SQL), "\n")
+	// Whatever the list leaves free, minus the title, filter and footer
+	room := max(m.height-m.listHeight()-5, 3)
+	if len(lines) > room {
+		lines = append(lines[:room-1], "…")
+	}
+	return parser.HighlightSQL(strings.Join(lines, "\n")) + "\n"
+}
+
+func (m Model) GetSelection() Selection {
+	return m.selection
+}
+
+// Pick opens the picker over the given queries and returns the user's choice,
+// with ActionNone if they quit without picking one
+func Pick(title string, queries map[string]db.Query) (Selection, error) {
+	program := tea.NewProgram(NewModel(title, queries), tea.WithAltScreen())
+
+	finalModel, err := program.Run()
+	if err != nil {
+		return Selection{}, err
+	}
+
+	return finalModel.(Model).GetSelection(), nil
+}
+
+func truncate(s string, n int) string {
+	r := []rune(s)
+	if len(r) <= n {
+		return s
+	}
+	if n <= 1 {
+		return string(r[:n])
+	}
+	return string(r[:n-1]) + "…"
+}
@@ -0,0 +1,65 @@
+package run
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"sync"
+	"time"
+)
+
+var errQueryCancelled = errors.New("query cancelled")
+
+// withQueryCancel returns a context that is cancelled when Ctrl+C is pressed
+// or the timeout elapses, which makes the driver abort the statement on the
+// server. stop disarms both once the query has produced its results, so the
+// table view can keep paging through them, and cancel releases the context
+func withQueryCancel(timeout time.Duration) (ctx context.Context, stop func(), cancel func()) {
+	ctx, cancelCause := context.WithCancelCause(context.Background())
+
+	sigs := make(chan os.Signal, 1)
+	signal.Notify(sigs, os.Interrupt)
+
+	var timer *time.Timer
+	if timeout > 0 {
+		timer = time.AfterFunc(timeout, func() {
+			cancelCause(fmt.Errorf("query timed out after %s", timeout))
+		})
+	}
+
+	stopped := make(chan struct{})
+	go func() {
+		select {
+		case <-sigs:
+			cancelCause(errQueryCancelled)
+		case <-stopped:
+		}
+	}()
+
+	var once sync.Once
+	stop = func() {
+		once.Do(func() {
+			signal.Stop(sigs)
+			if timer != nil {
+				timer.Stop()
+			}
+			close(stopped)
+		})
+	}
+	cancel = func() {
+		stop()
+		cancelCause(nil)
+	}
+	return ctx, stop, cancel
+}
+
+// cancelReason replaces the driver error with the reason the query was
+// cancelled, drivers report it inconsistently
+func cancelReason(ctx context.Context, err error) error {
+	if cause := context.Cause(ctx); cause != nil {
+		return cause
+	}
+	return err
+}
@@ -24,65 +24,95 @@ type ExecutionParams struct {
 	Config       *config.Config
 	SaveCallback SaveQueryCallback
 	OnRerun      func(editedSQL string) error
-	Args         []any  // Arguments for parameterized queries
-	DisplaySQL   string // Human-readable SQL with values substituted (for TUI display)
+	Args         []any             // Arguments for parameterized queries
+	DisplaySQL   string            // Human-readable SQL with values substituted (for TUI display)
+	ParamSQL     string            // The SQL as written, with its :name parameters, for the history
+	ParamValues  map[string]string // Values of the :name parameters, for the history
+	Format       string            // Output format for stdout; empty renders the TUI
+
+	recordHistory bool // set by Execute
 }
 
 func ExecuteSelect(sql, queryName string, params ExecutionParams) error {
+	timeout, err := params.Config.QueryTimeoutFor(params.Connection.GetName())
+	if err != nil {
+		return err
+	}
+
 	start := time.Now()
 	done := make(chan struct{})
 	go spinner.CircleWaitWithTimer(done)
 
 	// Extract metadata if query provided
-	var tableName, primaryKey string
-	var applyRowLimit bool
-	if params.Query.Id != 0 || params.Query.Name != "" {
-		tableName, primaryKey = extractMetadata(params.Connection, params.Query)
-		applyRowLimit = true
+	// Table metadata is only needed for in-place editing in the TUI
+	var tableName string
+	var primaryKeys []string
+	if (params.Query.Id != 0 || params.Query.Name != "") && params.Format == "" {
+		tableName, primaryKeys = extractMetadata(params.Connection, params.Query)
 	}
 
-	// Apply row limit if requested
-	if applyRowLimit && params.Config.DefaultRowLimit > 0 {
-		sql = params.Connection.ApplyRowLimit(sql, params.Config.DefaultRowLimit)
-	}
+	ctx, stop, cancel := withQueryCancel(timeout)
+	defer cancel()
 
 	// Execute the query with or without parameters
-	var err error
-	var rows any
+	var rows *stdlib.Rows
 	if params.Args != nil && len(params.Args) > 0 {
-		rows, err = params.Connection.ExecQuery(sql, params.Args...)
+		rows, err = params.Connection.ExecQuery(ctx, sql, params.Args...)
 	} else {
-		rows, err = params.Connection.ExecQuery(sql)
+		rows, err = params.Connection.ExecQuery(ctx, sql)
 	}
 	if err != nil {
 		done <- struct{}{}
-		return fmt.Errorf("query execution failed: %w", err)
+		err = fmt.Errorf("query execution failed: %w", cancelReason(ctx, err))
+		params.record(sql, start, 0, err)
+		return err
 	}
 
-	// Format the results
-	columns, columnTypes, data, err := db.FormatTableDataWithTypes(rows.(*stdlib.Rows))
+	// Rows are fetched one page at a time, default_row_limit sets the page size
+	iter, err := db.NewRowIterator(rows, params.Config.DefaultRowLimit)
 	if err != nil {
 		done <- struct{}{}
-		return fmt.Errorf("formatting failed: %w", err)
+		err = fmt.Errorf("formatting failed: %w", err)
+		params.record(sql, start, 0, err)
+		return err
+	}
+	defer iter.Close()
+
+	columns, columnTypes := iter.Columns(), iter.ColumnTypes()
+	data, err := iter.NextPage()
+	if err != nil {
+		done <- struct{}{}
+		err = fmt.Errorf("query execution failed: %w", cancelReason(ctx, err))
+		params.record(sql, start, 0, err)
+		return err
 	}
 
 	done <- struct{}{}
 	elapsed := time.Since(start)
 
+	// The timeout and Ctrl+C cover the whole export, but not browsing the
+	// results in the table view
+	if params.Format != "" {
+		count, err := streamOutput(iter, params.Format, columns, data)
+		if err != nil {
+			err = cancelReason(ctx, err)
+		}
+		params.record(sql, start, count, err)
+		return err
+	}
+	stop()
+	params.record(sql, start, len(data), nil)
+
 	// Check for empty results
-	if len(data) == 0 {
+	if len(data) == 0 && iter.Done() {
 		fmt.Println("No results found")
 		return nil
 	}
 
 	// Create query object
-	q := db.Query{
-		Name: queryName,
-		SQL:  sql,
-	}
-	if params.Query.Id != 0 {
-		q.Id = params.Query.Id
-	}
+	q := params.Query
+	q.Name = queryName
+	q.SQL = sql
 
 	// Use DisplaySQL for TUI if available (shows actual values instead of placeholders)
 	if params.DisplaySQL != "" {
@@ -92,7 +122,7 @@ func ExecuteSelect(sql, queryName string, params ExecutionParams) error {
 	statusMessage := ""
 
 	for {
-		model, err := table.Render(columns, columnTypes, data, elapsed, params.Connection, tableName, primaryKey, q, params.Config.DefaultColumnWidth, params.Config.UIVisibility, params.SaveCallback, statusMessage)
+		model, err := table.Render(columns, columnTypes, data, iter, elapsed, params.Connection, tableName, primaryKeys, q, params.Config.DefaultColumnWidth, params.Config.UIVisibility, params.SaveCallback, statusMessage)
 		if err != nil {
 			return fmt.Errorf("error rendering table: %w", err)
 		}
@@ -101,6 +131,9 @@ func ExecuteSelect(sql, queryName string, params ExecutionParams) error {
 			return nil
 		}
 
+		// Release the cursor before the edited query runs
+		iter.Close()
+
 		err = params.OnRerun(model.GetEditedQuery().SQL)
 		if err == nil {
 			return nil
@@ -110,61 +143,100 @@ func ExecuteSelect(sql, queryName string, params ExecutionParams) error {
 	}
 }
 
-func ExecuteNonSelect(params ExecutionParams) {
+// streamOutput writes the first page and every remaining page to stdout as
+// they are read, so large exports don't have to fit in memory. It returns the
+// number of rows written
+func streamOutput(iter *db.RowIterator, format string, columns []string, firstPage [][]db.Cell) (int, error) {
+	out, err := table.NewOutputWriter(os.Stdout, format, columns)
+	if err != nil {
+		return 0, err
+	}
+
+	count := 0
+	page := firstPage
+	for {
+		if err := out.WriteRows(page); err != nil {
+			return count, err
+		}
+		count += len(page)
+		if iter.Done() {
+			break
+		}
+		page, err = iter.NextPage()
+		if err != nil {
+			return count, fmt.Errorf("formatting failed: %w", err)
+		}
+	}
+
+	return count, out.Close()
+}
+
+func ExecuteNonSelect(params ExecutionParams) error {
+	timeout, err := params.Config.QueryTimeoutFor(params.Connection.GetName())
+	if err != nil {
+		return err
+	}
+
+	ctx, _, cancel := withQueryCancel(timeout)
+	defer cancel()
+
 	start := time.Now()
 	done := make(chan struct{})
 	go spinner.CircleWaitWithTimer(done)
 
-	var err error
 	if params.Args != nil && len(params.Args) > 0 {
-		err = params.Connection.Exec(params.Query.SQL, params.Args...)
+		err = params.Connection.Exec(ctx, params.Query.SQL, params.Args...)
 	} else {
-		err = params.Connection.Exec(params.Query.SQL)
+		err = params.Connection.Exec(ctx, params.Query.SQL)
 	}
 	done <- struct{}{}
 	elapsed := time.Since(start)
 
 	if err != nil {
-		printError("Could not execute command: %v", err)
-		return
+		err = fmt.Errorf("could not execute command: %w", cancelReason(ctx, err))
+		params.record(params.Query.SQL, start, 0, err)
+		return err
 	}
+	params.record(params.Query.SQL, start, 0, nil)
 
-	fmt.Println(styles.Success.Render(fmt.Sprintf("✓ Command executed successfully in %.2fs", elapsed.Seconds())))
-	fmt.Println(styles.Faint.Render("\nExecuted SQL:"))
-	fmt.Println(parser.HighlightSQL(params.Query.SQL))
+	// Keep stdout clean when the output is meant for another program
+	out := os.Stdout
+	if params.Format != "" {
+		out = os.Stderr
+	}
+
+	fmt.Fprintln(out, styles.Success.Render(fmt.Sprintf("✓ Command executed successfully in %.2fs", elapsed.Seconds())))
+	fmt.Fprintln(out, styles.Faint.Render("\nExecuted SQL:"))
+	fmt.Fprintln(out, parser.HighlightSQL(params.Query.SQL))
+	return nil
 }
 
 func Execute(params ExecutionParams) error {
+	params.recordHistory = true
+
 	if err := params.Connection.Open(); err != nil {
-		return fmt.Errorf("could not open connection to %s/%s: %w", params.Connection.GetDbType(), params.Connection.GetName(), err)
+		err = fmt.Errorf("could not open connection to %s/%s: %w", params.Connection.GetDbType(), params.Connection.GetName(), err)
+		params.record(params.Query.SQL, time.Now(), 0, err)
+		return err
 	}
 	defer params.Connection.Close()
 
 	if IsSelectQuery(params.Query.SQL) {
 		return ExecuteSelect(params.Query.SQL, params.Query.Name, params)
 	} else {
-		ExecuteNonSelect(params)
-		return nil
+		return ExecuteNonSelect(params)
 	}
 }
 
-func extractMetadata(conn db.DatabaseConnection, query db.Query) (string, string) {
+func extractMetadata(conn db.DatabaseConnection, query db.Query) (string, []string) {
 	metadata, err := db.InferTableMetadata(conn, query)
 	if err == nil && metadata != nil {
-		// Return first primary key if available
-		pk := ""
-		if len(metadata.PrimaryKeys) > 0 {
-			pk = metadata.PrimaryKeys[0]
-		}
-		return metadata.TableName, pk
+		// Composite keys come with every column, in key order
+		return metadata.TableName, metadata.PrimaryKeys
 	}
 
 	fmt.Fprintf(os.Stderr, styles.Faint.Render("Warning: Could not extract table metadata %v\n"), err)
-	return "", ""
-}
-
-func printError(format string, args ...interface{}) {
-	fmt.Fprintf(os.Stderr, format+"\n", args...)
+	return "", nil
 }
 
 func formatQueryError(err error) string {
@@ -0,0 +1,47 @@
+package run
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/eduardofuncao/squix/internal/history"
+	"github.com/eduardofuncao/squix/internal/styles"
+)
+
+// record adds the execution to the query history when it was started through
+// Execute. Failing to write the history only prints a warning
+func (p ExecutionParams) record(sql string, start time.Time, rows int, execErr error) {
+	if !p.recordHistory {
+		return
+	}
+
+	entry := history.Entry{
+		Time:       start,
+		Connection: p.Connection.GetName(),
+		SQL:        sql,
+		Duration:   time.Since(start),
+		Rows:       rows,
+	}
+	// Placeholder names like <inline> or <edited> aren't worth keeping
+	if !strings.HasPrefix(p.Query.Name, "<") {
+		entry.QueryName = p.Query.Name
+	}
+	// Kept by name, so a rerun of the edited SQL finds the values again
+	if p.ParamValues != nil {
+		entry.SQL = p.ParamSQL
+		entry.Values = p.ParamValues
+	} else {
+		for _, arg := range p.Args {
+			entry.Params = append(entry.Params, fmt.Sprint(arg))
+		}
+	}
+	if execErr != nil {
+		entry.Error = execErr.Error()
+	}
+
+	if err := history.Append(entry, p.Config.History.Size); err != nil {
+		fmt.Fprintln(os.Stderr, styles.Faint.Render(fmt.Sprintf("Warning: could not record history: %v", err)))
+	}
+}
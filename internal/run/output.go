@@ -0,0 +1,37 @@
+package run
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/eduardofuncao/squix/internal/table"
+)
+
+// ResolveOutputFormat validates the requested output format. When no format
+// is given and stdout is not a terminal (pipes, redirects, cron), the plain
+// format is picked so results can be consumed without the TUI
+func ResolveOutputFormat(requested string) (string, error) {
+	format := strings.ToLower(strings.TrimSpace(requested))
+
+	if format == "" {
+		if !isTerminal(os.Stdout) {
+			return "plain", nil
+		}
+		return "", nil
+	}
+
+	if !table.IsValidOutputFormat(format) {
+		return "", fmt.Errorf("unknown output format '%s' (expected one of: %s)", requested, strings.Join(table.OutputFormats, ", "))
+	}
+
+	return format, nil
+}
+
+func isTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	if err != nil {
+		return false
+	}
+	return info.Mode()&os.ModeCharDevice != 0
+}
@@ -11,7 +11,7 @@ import (
 // Priority:
 //  1. Last query (if --last/-l flag)
 //  2. Inline SQL (if selector looks like SQL)
-//  3. Saved query by name/ID
+//  3. Saved query by name/ID, on the connection then in the global library
 //  4. Create new in editor (default)
 func ResolveQuery(flags Flags, cfg *config.Config, currentConn string, conn db.DatabaseConnection) (ResolvedQuery, error) {
 	// Priority 1: Last query with --last/-l flag
@@ -39,14 +39,20 @@ func ResolveQuery(flags Flags, cfg *config.Config, currentConn string, conn db.D
 
 	// Priority 3: Saved query by name/ID
 	if flags.Selector != "" {
-		q, found := db.FindQueryWithSelector(conn.GetQueries(), flags.Selector)
+		if q, found := db.FindQueryWithSelector(conn.GetQueries(), flags.Selector); found {
+			return ResolvedQuery{
+				Query:    q,
+				Saveable: true,
+			}, nil
+		}
+
+		q, found := cfg.Queries[flags.Selector]
 		if !found {
 			return ResolvedQuery{}, fmt.Errorf("could not find query with name/id: %v", flags.Selector)
 		}
-		return ResolvedQuery{
-			Query:    q,
-			Saveable: true,
-		}, nil
+		// The library is keyed by name, which the entry may leave out
+		q.Name = flags.Selector
+		return ResolveGlobalQuery(q, conn)
 	}
 
 	// Priority 4: Default - will create new query in editor (squix run with no args)
@@ -57,6 +63,20 @@ func ResolveQuery(flags Flags, cfg *config.Config, currentConn string, conn db.D
 	}, nil
 }
 
+// ResolveGlobalQuery picks the SQL of a global query for the connection's
+// database type. The result is a plain query for this run, without an id so
+// saving it from the table asks for a name on the connection
+func ResolveGlobalQuery(q db.Query, conn db.DatabaseConnection) (ResolvedQuery, error) {
+	q, err := q.ForDialect(conn.GetDbType())
+	if err != nil {
+		return ResolvedQuery{}, err
+	}
+	q.Id = -1
+	q.Dialects = nil
+	q.Variants = nil
+	return ResolvedQuery{Query: q, Global: true}, nil
+}
+
 func ShouldCreateNewQuery(resolved ResolvedQuery) bool {
 	return resolved.Query.Name == "<new>" && resolved.Query.SQL == ""
 }
@@ -0,0 +1,140 @@
+package run
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/eduardofuncao/squix/internal/db"
+	"github.com/eduardofuncao/squix/internal/spinner"
+	"github.com/eduardofuncao/squix/internal/styles"
+)
+
+// ExecuteScript runs the statements of a script one after the other,
+// printing how each went. The first failure stops the script unless
+// continueOnError is set. When the last statement is a query its results are
+// shown the way Execute shows them
+func ExecuteScript(statements []string, continueOnError bool, params ExecutionParams) error {
+	params.recordHistory = true
+
+	if len(statements) == 0 {
+		return fmt.Errorf("the script has no statements")
+	}
+
+	if err := params.Connection.Open(); err != nil {
+		err = fmt.Errorf("could not open connection to %s/%s: %w", params.Connection.GetDbType(), params.Connection.GetName(), err)
+		params.record(statements[0], time.Now(), 0, err)
+		return err
+	}
+	// Statements run strictly one at a time, so the pool keeps handing out
+	// the same session and BEGIN ... COMMIT or temporary tables work
+	defer params.Connection.Close()
+
+	// Keep stdout clean when the output is meant for another program
+	out := os.Stdout
+	if params.Format != "" {
+		out = os.Stderr
+	}
+
+	start := time.Now()
+	failed := 0
+	for i, statement := range statements {
+		label := fmt.Sprintf("[%d/%d]", i+1, len(statements))
+
+		if i == len(statements)-1 && IsSelectQuery(statement) {
+			fmt.Fprintln(out, styles.Faint.Render(fmt.Sprintf("  %s %s", label, statementSummary(statement))))
+			params.Query.SQL = statement
+			if err := ExecuteSelect(statement, params.Query.Name, params); err != nil {
+				return fmt.Errorf("statement %d of %d failed: %w", i+1, len(statements), err)
+			}
+			break
+		}
+
+		rows, elapsed, err := executeStatement(statement, params)
+		if err != nil {
+			fmt.Fprintln(out, styles.Error.Render(fmt.Sprintf("✗ %s %s", label, statementSummary(statement))))
+			fmt.Fprintln(out, styles.Error.Render("  "+err.Error()))
+			if !continueOnError {
+				return fmt.Errorf("statement %d of %d failed, stopping (use --continue to run the rest)", i+1, len(statements))
+			}
+			failed++
+			continue
+		}
+
+		status := fmt.Sprintf("✓ %s %s", label, statementSummary(statement))
+		if rows >= 0 {
+			status += fmt.Sprintf(" → %d rows", rows)
+		}
+		fmt.Fprintln(out, styles.Success.Render(status)+styles.Faint.Render(fmt.Sprintf(" (%.2fs)", elapsed.Seconds())))
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("%d of %d statements failed", failed, len(statements))
+	}
+	if !IsSelectQuery(statements[len(statements)-1]) {
+		fmt.Fprintln(out, styles.Success.Render(fmt.Sprintf("✓ Script executed successfully in %.2fs", time.Since(start).Seconds())))
+	}
+	return nil
+}
+
+// executeStatement runs a statement of a script. Queries before the last
+// statement are read through and only their rows counted, other statements
+// report -1 rows
+func executeStatement(statement string, params ExecutionParams) (int, time.Duration, error) {
+	timeout, err := params.Config.QueryTimeoutFor(params.Connection.GetName())
+	if err != nil {
+		return 0, 0, err
+	}
+
+	ctx, _, cancel := withQueryCancel(timeout)
+	defer cancel()
+
+	start := time.Now()
+	done := make(chan struct{})
+	go spinner.CircleWaitWithTimer(done)
+
+	count := -1
+	if IsSelectQuery(statement) {
+		count, err = countRows(ctx, params.Connection, statement)
+	} else {
+		err = params.Connection.Exec(ctx, statement)
+	}
+	done <- struct{}{}
+	elapsed := time.Since(start)
+
+	if err != nil {
+		err = cancelReason(ctx, err)
+	}
+	params.record(statement, start, max(count, 0), err)
+	return count, elapsed, err
+}
+
+func countRows(ctx context.Context, conn db.DatabaseConnection, statement string) (int, error) {
+	rows, err := conn.ExecQuery(ctx, statement)
+	if err != nil {
+		return 0, err
+	}
+	defer rows.Close()
+
+	count := 0
+	for rows.Next() {
+		count++
+	}
+	return count, rows.Err()
+}
+
+// statementSummary is the first line of a statement, shortened to fit a
+// status line
+func statementSummary(statement string) string {
+	line, _, multiline := strings.Cut(statement, "\n")
+	line = strings.TrimSpace(line)
+	if runes := []rune(line); len(runes) > 60 {
+		return string(runes[:57]) + "..."
+	}
+	if multiline {
+		return line + " ..."
+	}
+	return line
+}
@@ -6,9 +6,12 @@ type Flags struct {
 	EditMode  bool
 	LastQuery bool
 	Selector  string
+	Format    string // non-interactive output format (csv, json, ...), empty for TUI
 }
 
 type ResolvedQuery struct {
 	Query    db.Query
 	Saveable bool // will be saved to config file
+	Global   bool // from the global library, only kept as the last query
+	Edited   bool // changed in the editor before running
 }
@@ -2,22 +2,28 @@ package spinner
 
 import (
 	"fmt"
+	"os"
 	"time"
 
 	"github.com/eduardofuncao/squix/internal/styles"
 )
 
 func Wait(done chan struct{}) {
+	if !stderrIsTerminal() {
+		<-done
+		return
+	}
+
 	spinnerStages := []string{"▉", "▊", "▋", "▌", "▍", "▎", "▏", "▎", "▍", "▌", "▋", "▊", "▉"}
 	var passed time.Duration = 0
 	for {
 		for _, s := range spinnerStages {
 			select {
 			case <-done:
-				fmt.Print("\r")
+				fmt.Fprint(os.Stderr, "\r\033[2K")
 				return
 			default:
-				fmt.Printf("\r%s %.2fs", s, passed.Seconds())
+				fmt.Fprintf(os.Stderr, "\r%s %.2fs", s, passed.Seconds())
 				passed += 100 * time.Millisecond
 				time.Sleep(100 * time.Millisecond)
 			}
@@ -26,16 +32,21 @@ func Wait(done chan struct{}) {
 }
 
 func CircleWait(done chan struct{}) {
+	if !stderrIsTerminal() {
+		<-done
+		return
+	}
+
 	// Custom pulsing animation
 	stages := []string{" ", ".", "o", "O", "@", "*"}
 	for {
 		for _, s := range stages {
 			select {
 			case <-done:
-				fmt.Print("\r")
+				fmt.Fprint(os.Stderr, "\r\033[2K")
 				return
 			default:
-				fmt.Printf("\r%s Checking...", styles.Success.Render(s))
+				fmt.Fprintf(os.Stderr, "\r%s Checking...", styles.Success.Render(s))
 				time.Sleep(100 * time.Millisecond)
 			}
 		}
@@ -43,6 +54,11 @@ func CircleWait(done chan struct{}) {
 }
 
 func CircleWaitWithTimer(done chan struct{}) {
+	if !stderrIsTerminal() {
+		<-done
+		return
+	}
+
 	// Custom pulsing animation with timer
 	stages := []string{" ", ".", "o", "O", "@", "*"}
 	var passed time.Duration = 0
@@ -50,13 +66,23 @@ func CircleWaitWithTimer(done chan struct{}) {
 		for _, s := range stages {
 			select {
 			case <-done:
-				fmt.Print("\r")
+				fmt.Fprint(os.Stderr, "\r\033[2K")
 				return
 			default:
-				fmt.Printf("\r%s %.2fs", styles.Success.Render(s), passed.Seconds())
+				fmt.Fprintf(os.Stderr, "\r%s %.2fs", styles.Success.Render(s), passed.Seconds())
 				passed += 100 * time.Millisecond
 				time.Sleep(100 * time.Millisecond)
 			}
 		}
 	}
 }
+
+// stderrIsTerminal reports whether the spinner can be drawn, so redirected
+// output (logs, CI) doesn't fill up with animation frames
+func stderrIsTerminal() bool {
+	info, err := os.Stderr.Stat()
+	if err != nil {
+		return false
+	}
+	return info.Mode()&os.ModeCharDevice != 0
+}
@@ -14,7 +14,7 @@ var (
 	Title, Success, Error, Faint, Separator lipgloss.Style
 	SQLKeyword, SQLString, SearchMatch lipgloss.Style
 	TableSelected, TableHeader, TableCell, TableBorder lipgloss.Style
-	TableCopiedBlink, TableUpdated, TableDeleted lipgloss.Style
+	TableCopiedBlink, TableUpdated, TableDeleted, TableNull lipgloss.Style
 	TableName, PrimaryKeyLabel lipgloss.Style
 	BelongsToStyle, HasManyStyle, HasOneStyle, HasManyToManyStyle, CardinalityStyle, TreeConnector lipgloss.Style
 )
@@ -37,6 +37,20 @@ func InitScheme(schemeName string, custom *ColorScheme) {
 	reloadAllStyles()
 }
 
+// DisableColors drops every color for --no-color. Bold, reverse and
+// underline stay so the selected cell and search matches remain visible
+func DisableColors() {
+	ActiveScheme = ColorScheme{}
+	reloadAllStyles()
+
+	SearchMatch = lipgloss.NewStyle().Bold(true).Underline(true)
+	TableSelected = lipgloss.NewStyle().Reverse(true).Bold(true)
+	TableCopiedBlink = TableSelected
+	TableUpdated = lipgloss.NewStyle().Bold(true).Underline(true)
+	TableDeleted = lipgloss.NewStyle().Strikethrough(true)
+	HasManyToManyStyle = lipgloss.NewStyle().Bold(true)
+}
+
 // reloadAllStyles updates all style variables based on ActiveScheme
 func reloadAllStyles() {
 	Title = lipgloss.NewStyle().
@@ -84,6 +98,11 @@ func reloadAllStyles() {
 	TableBorder = lipgloss.NewStyle().
 		Foreground(lipgloss.Color(ActiveScheme.Muted))
 
+	// SQL NULL, set apart from a cell holding the text "NULL"
+	TableNull = lipgloss.NewStyle().
+		Foreground(lipgloss.Color(ActiveScheme.Muted)).
+		Italic(true)
+
 	TableCopiedBlink = lipgloss.NewStyle().
 		Background(lipgloss.Color(ActiveScheme.Highlight)).
 		Foreground(lipgloss.Color(ActiveScheme.Primary)).
@@ -0,0 +1,246 @@
+package table
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/eduardofuncao/squix/internal/db"
+	"github.com/eduardofuncao/squix/internal/styles"
+	"github.com/eduardofuncao/squix/internal/undo"
+)
+
+// u and D in visual mode update the current column of every selected row, or
+// delete them. The editor gets one statement per row, and they run one after
+// the other in the edit transaction
+
+func (m Model) bulkEdit(action string) (tea.Model, tea.Cmd) {
+	if m.tableName == "" || !m.hasPrimaryKey() {
+		m.statusMessage = styles.Error.Render("✗ Editing several rows needs a table with a primary key")
+		return m, nil
+	}
+
+	minRow, maxRow, _, _ := m.getSelectionBounds()
+	col := m.selectedCol
+
+	var statements []string
+	for row := minRow; row <= maxRow && row < m.numRows(); row++ {
+		rowModel := m
+		rowModel.selectedRow = row
+		var stmt string
+		if action == undo.ActionUpdate {
+			stmt = rowModel.buildUpdateStatement()
+		} else {
+			stmt = rowModel.buildDeleteStatement()
+		}
+		// The dialect comments would repeat for every row
+		stmt = stripCommentLines(stmt)
+		if !strings.HasSuffix(stmt, ";") {
+			stmt += ";"
+		}
+		statements = append(statements, stmt)
+	}
+
+	// SQL Server scripts are split into batches on GO lines only
+	separator := "\n\n"
+	if m.dbConnection.GetDbType() == "sqlserver" {
+		separator = "\nGO\n\n"
+	}
+
+	rows := pluralRows(len(statements))
+	var header string
+	if action == undo.ActionUpdate {
+		header = fmt.Sprintf("-- UPDATE %s of %s, one statement per row\n", m.columns[col], rows)
+	} else {
+		header = fmt.Sprintf("-- DELETE %s, one statement per row\n-- WARNING: This will permanently delete data!\n", rows)
+	}
+	header += `-- Remove a statement to leave its row alone
+-- To cancel, exit without saving (e.g., :q! in vim)
+--
+`
+	content := header + strings.Join(statements, separator) + "\n"
+
+	hint := CursorAtWhereClause
+	if action == undo.ActionUpdate {
+		hint = CursorAtUpdateValue
+	}
+	m.visualMode = false
+
+	return m.editInEditor("squix-bulk-"+action+"-*.sql", content, hint, func(sql string, cancelled bool) tea.Msg {
+		return bulkEditCompleteMsg{sql: sql, action: action, colIndex: col, cancelled: cancelled}
+	})
+}
+
+type bulkEditCompleteMsg struct {
+	sql       string
+	action    string
+	colIndex  int
+	cancelled bool
+}
+
+func (m Model) handleBulkEditComplete(msg bulkEditCompleteMsg) (tea.Model, tea.Cmd) {
+	statements := db.SplitStatements(msg.sql, m.dbConnection.GetDbType())
+	if msg.cancelled || len(statements) == 0 {
+		m.statusMessage = styles.Error.Render(fmt.Sprintf("✗ Bulk %s cancelled", msg.action))
+		return m, m.blinkCmd()
+	}
+
+	// Check them all first so a typo doesn't leave the batch half done
+	for i, stmt := range statements {
+		validate := validateDeleteStatement
+		if msg.action == undo.ActionUpdate {
+			validate = validateUpdateStatement
+		}
+		if err := validate(stmt, m.primaryKeyCols); err != nil {
+			m.statusMessage = styles.Error.Render(fmt.Sprintf("✗ Statement %d of %d: %v", i+1, len(statements), err))
+			return m, nil
+		}
+	}
+
+	m.lastExecutedQuery = m.cleanSQLForDisplay(msg.sql)
+
+	affected := 0
+	for i, stmt := range statements {
+		var entry undo.Entry
+		var err error
+		if msg.action == undo.ActionUpdate {
+			m, entry, err = m.executeUpdate(stmt)
+		} else {
+			m, entry, err = m.executeDelete(stmt)
+		}
+		if err != nil {
+			m = m.refreshLayout()
+			m.statusMessage = styles.Error.Render(fmt.Sprintf("✗ Statement %d of %d failed: %v (%s changed before it)",
+				i+1, len(statements), err, pluralRows(affected)))
+			return m, tea.ClearScreen
+		}
+
+		affected += len(entry.Rows)
+		if msg.action == undo.ActionUpdate {
+			m = m.applyBulkUpdate(entry, m.extractNewValue(stmt, m.columns[msg.colIndex]), msg.colIndex)
+		} else {
+			m = m.applyBulkDelete(entry)
+		}
+	}
+
+	verb := "Deleted"
+	if msg.action == undo.ActionUpdate {
+		verb = "Updated"
+	}
+	m.statusMessage = styles.Success.Render(fmt.Sprintf("✓ %s %s with %d statements", verb, pluralRows(affected), len(statements)))
+	return m.clampSelection().refreshLayout(), tea.ClearScreen
+}
+
+// applyBulkUpdate sets the new value on the loaded rows a statement changed,
+// found by their primary key in its before image
+func (m Model) applyBulkUpdate(entry undo.Entry, newValue db.Cell, col int) Model {
+	var changes []pendingChange
+	for _, row := range m.loadedRowsIn(entry) {
+		changes = append(changes, pendingChange{row: row, col: col, old: row[col]})
+		row[col] = newValue
+	}
+	return m.addBulkPending(entry, changes)
+}
+
+// applyBulkDelete takes the rows a statement deleted out of the table
+func (m Model) applyBulkDelete(entry undo.Entry) Model {
+	var changes []pendingChange
+	for _, row := range m.loadedRowsIn(entry) {
+		index := indexOfRow(m.data, row)
+		if m.allData != nil {
+			index = indexOfRow(m.allData, row)
+		}
+		changes = append(changes, pendingChange{row: row, deleted: true, index: index})
+
+		if i := indexOfRow(m.data, row); i >= 0 {
+			m = m.removeRow(i)
+		} else if i := indexOfRow(m.allData, row); i >= 0 {
+			// Hidden by the filter
+			m.allData = append(m.allData[:i], m.allData[i+1:]...)
+		}
+	}
+	return m.addBulkPending(entry, changes)
+}
+
+// addBulkPending records the loaded rows a statement changed. The before
+// image goes with the last of them, so U takes the statement back at once
+func (m Model) addBulkPending(entry undo.Entry, changes []pendingChange) Model {
+	if len(changes) == 0 {
+		// The rows aren't loaded, only the database changed
+		return m.addPending(pendingChange{journal: entry})
+	}
+	changes[len(changes)-1].journal = entry
+	for _, change := range changes {
+		m = m.addPending(change)
+	}
+	return m
+}
+
+// loadedRowsIn finds the loaded rows whose primary key is in the before image
+func (m Model) loadedRowsIn(entry undo.Entry) [][]db.Cell {
+	pkCols := keyColumnIndexes(m.columns, m.primaryKeyCols)
+	entryPKs := keyColumnIndexes(entry.Columns, entry.PrimaryKeys)
+	if pkCols == nil || entryPKs == nil {
+		return nil
+	}
+
+	keys := map[string]bool{}
+	for _, row := range entry.Rows {
+		keys[rowKey(row, entryPKs)] = true
+	}
+
+	loaded := m.data
+	if m.allData != nil {
+		loaded = m.allData
+	}
+	var rows [][]db.Cell
+	for _, row := range loaded {
+		if keys[rowKey(row, pkCols)] {
+			rows = append(rows, row)
+		}
+	}
+	return rows
+}
+
+// keyColumnIndexes finds every key column in columns, nil when one is missing
+func keyColumnIndexes(columns, keys []string) []int {
+	if len(keys) == 0 {
+		return nil
+	}
+	indexes := make([]int, len(keys))
+	for k, key := range keys {
+		indexes[k] = slices.IndexFunc(columns, func(col string) bool { return strings.EqualFold(col, key) })
+		if indexes[k] < 0 {
+			return nil
+		}
+	}
+	return indexes
+}
+
+// rowKey joins the key values of a row, to look composite keys up in a map
+func rowKey(row []db.Cell, indexes []int) string {
+	values := make([]string, len(indexes))
+	for k, i := range indexes {
+		values[k] = row[i].Text
+	}
+	return strings.Join(values, "\x00")
+}
+
+func stripCommentLines(sql string) string {
+	var lines []string
+	for line := range strings.SplitSeq(sql, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if trimmed != "" && !strings.HasPrefix(trimmed, "--") {
+			lines = append(lines, line)
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+func pluralRows(n int) string {
+	if n == 1 {
+		return "1 row"
+	}
+	return fmt.Sprintf("%d rows", n)
+}
@@ -2,13 +2,13 @@ package table
 
 import (
 	"fmt"
-	"os"
 	"regexp"
 	"strings"
 	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/eduardofuncao/squix/internal/styles"
+	"github.com/eduardofuncao/squix/internal/undo"
 )
 
 func (m Model) deleteRow() (tea.Model, tea.Cmd) {
@@ -16,23 +16,12 @@ func (m Model) deleteRow() (tea.Model, tea.Cmd) {
 		return m, nil
 	}
 
-	if m.primaryKeyCol == "" {
+	if !m.hasPrimaryKey() {
 		return m, nil
 	}
 
 	deleteStmt := m.buildDeleteStatement()
 
-	editorCmd := os.Getenv("EDITOR")
-	if editorCmd == "" {
-		editorCmd = "vim"
-	}
-
-	tmpFile, err := os.CreateTemp("", "squix-delete-*.sql")
-	if err != nil {
-		return m, nil
-	}
-	tmpPath := tmpFile.Name()
-
 	header := `-- DELETE Statement
 -- WARNING: This will permanently delete data!
 -- To cancel, exit without saving (e.g., :q! in vim)
@@ -40,55 +29,14 @@ func (m Model) deleteRow() (tea.Model, tea.Cmd) {
 `
 	content := header + deleteStmt
 
-	if _, err := tmpFile.Write([]byte(content)); err != nil {
-		tmpFile.Close()
-		os.Remove(tmpPath)
-		return m, nil
-	}
-	tmpFile.Close()
-
-	// Get file modification time before editor
-	beforeModTime, err := os.Stat(tmpPath)
-	if err != nil {
-		os.Remove(tmpPath)
-		return m, nil
-	}
-
 	rowToDelete := m.selectedRow
 
-	// Build command with cursor at WHERE clause
-	cmd := buildEditorCommand(editorCmd, tmpPath, content, CursorAtWhereClause)
-
-	return m, tea.ExecProcess(cmd, func(err error) tea.Msg {
-		// Check if file was modified
-		afterModTime, statErr := os.Stat(tmpPath)
-		if statErr != nil {
-			os.Remove(tmpPath)
-			return nil
-		}
-
-		// If file wasn't modified, user cancelled (exited without saving)
-		if afterModTime.ModTime().Equal(beforeModTime.ModTime()) || afterModTime.ModTime().Before(beforeModTime.ModTime()) {
-			os.Remove(tmpPath)
-			// Return a message that will show "cancelled" status
-			return deleteCompleteMsg{
-				sql:       "",
-				rowIndex:  rowToDelete,
-				cancelled: true,
-			}
-		}
-
-		editedSQL, readErr := os.ReadFile(tmpPath)
-		os.Remove(tmpPath)
-
-		if err != nil || readErr != nil {
-			return nil
-		}
-
+	// Open the editor with the cursor at the WHERE clause
+	return m.editInEditor("squix-delete-*.sql", content, CursorAtWhereClause, func(sql string, cancelled bool) tea.Msg {
 		return deleteCompleteMsg{
-			sql:       string(editedSQL),
+			sql:       sql,
 			rowIndex:  rowToDelete,
-			cancelled: false,
+			cancelled: cancelled,
 		}
 	})
 }
@@ -109,20 +57,26 @@ func (m Model) handleDeleteComplete(msg deleteCompleteMsg) (tea.Model, tea.Cmd)
 		})
 	}
 
-	if err := validateDeleteStatement(msg.sql); err != nil {
-		printError("Delete validation failed: %v", err)
+	if err := validateDeleteStatement(msg.sql, m.primaryKeyCols); err != nil {
+		m.statusMessage = styles.Error.Render(fmt.Sprintf("✗ Delete validation failed: %v", err))
 		return m, nil
 	}
 
 	m.lastExecutedQuery = m.cleanSQLForDisplay(msg.sql)
 
-	if err := m.executeDelete(msg.sql); err != nil {
-		printError("Could not execute delete: %v", err)
-		return m, nil
+	m, entry, err := m.executeDelete(msg.sql)
+	if err != nil {
+		return m.editFailed("delete", err)
 	}
 
 	// Successfully deleted - update the model data
-	m.data = append(m.data[:msg.rowIndex], m.data[msg.rowIndex+1:]...)
+	row := m.data[msg.rowIndex]
+	index := msg.rowIndex
+	if m.allData != nil {
+		index = indexOfRow(m.allData, row)
+	}
+	m = m.addPending(pendingChange{journal: entry, row: row, deleted: true, index: index})
+	m = m.removeRow(msg.rowIndex)
 	if m.selectedRow >= m.numRows() && m.numRows() > 0 {
 		m.selectedRow = m.numRows() - 1
 	}
@@ -140,36 +94,23 @@ func (m Model) handleDeleteComplete(msg deleteCompleteMsg) (tea.Model, tea.Cmd)
 }
 
 func (m Model) buildDeleteStatement() string {
-	pkValue := ""
-	var multipleMatches bool
-
-	if m.primaryKeyCol != "" {
-		for i, col := range m.columns {
-			if col == m.primaryKeyCol {
-				pkValue = m.data[m.selectedRow][i]
-				break
-			}
-		}
-	}
-
-	if m.primaryKeyCol != "" && pkValue == "" {
-		pkValue, multipleMatches = m.fetchPrimaryKeyValue()
-	}
+	pkValues, multipleMatches := m.primaryKeyValues()
 
 	stmt := m.dbConnection.BuildDeleteStatement(
 		m.tableName,
-		m.primaryKeyCol,
-		pkValue,
+		m.primaryKeyCols,
+		pkValues,
+		m.primaryKeyTypes(),
 	)
 
-	if multipleMatches && pkValue != "" {
+	if multipleMatches && len(pkValues) > 0 {
 		stmt = fmt.Sprintf("-- Warning: Multiple rows matched the WHERE clause, using PK from first match\n%s", stmt)
 	}
 
 	return stmt
 }
 
-func (m Model) executeDelete(sql string) error {
+func (m Model) executeDelete(sql string) (Model, undo.Entry, error) {
 	var result strings.Builder
 	for line := range strings.SplitSeq(sql, "\n") {
 		trimmed := strings.TrimSpace(line)
@@ -183,13 +124,13 @@ func (m Model) executeDelete(sql string) error {
 	cleanSQL = strings.TrimSuffix(cleanSQL, ";")
 
 	if cleanSQL == "" {
-		return fmt.Errorf("no SQL to execute")
+		return m, undo.Entry{}, fmt.Errorf("no SQL to execute")
 	}
 
-	return m.dbConnection. Exec(cleanSQL)
+	return m.execJournaled(undo.ActionDelete, cleanSQL)
 }
 
-  func validateDeleteStatement(sql string) error {
+  func validateDeleteStatement(sql string, pkColumns []string) error {
       var result strings.Builder
       for line := range strings.SplitSeq(sql, "\n") {
           trimmed := strings.TrimSpace(line)
@@ -235,5 +176,5 @@ func (m Model) executeDelete(sql string) error {
           return fmt.Errorf("DELETE statement must include a WHERE clause for safety")
       }
 
-      return nil
+      return checkKeyColumns("DELETE", cleanSQL, pkColumns)
   }
@@ -0,0 +1,77 @@
+package table
+
+import (
+	"fmt"
+	"os"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/eduardofuncao/squix/internal/styles"
+)
+
+// editInEditor opens content in $EDITOR through a temp file named after
+// pattern, the cursor placed by hint. done turns the saved file into the
+// message for the model, or is told the edit was cancelled when the editor
+// exits without saving. A temp file that can't be set up is reported on the
+// status line
+func (m Model) editInEditor(pattern, content string, hint cursorPositionHint, done func(sql string, cancelled bool) tea.Msg) (tea.Model, tea.Cmd) {
+	editorCmd := os.Getenv("EDITOR")
+	if editorCmd == "" {
+		editorCmd = "vim"
+	}
+
+	tmpPath, err := writeEditFile(pattern, content)
+	if err != nil {
+		m.statusMessage = styles.Error.Render(fmt.Sprintf("✗ Could not open the editor: %v", err))
+		return m, nil
+	}
+
+	// Get file modification time before editor
+	beforeModTime, err := os.Stat(tmpPath)
+	if err != nil {
+		os.Remove(tmpPath)
+		m.statusMessage = styles.Error.Render(fmt.Sprintf("✗ Could not open the editor: %v", err))
+		return m, nil
+	}
+
+	cmd := buildEditorCommand(editorCmd, tmpPath, content, hint)
+
+	return m, tea.ExecProcess(cmd, func(err error) tea.Msg {
+		defer os.Remove(tmpPath)
+
+		afterModTime, statErr := os.Stat(tmpPath)
+		if statErr != nil {
+			return nil
+		}
+
+		// If file wasn't modified, user cancelled (exited without saving)
+		if !afterModTime.ModTime().After(beforeModTime.ModTime()) {
+			return done("", true)
+		}
+
+		editedSQL, readErr := os.ReadFile(tmpPath)
+		if err != nil || readErr != nil {
+			return nil
+		}
+		return done(string(editedSQL), false)
+	})
+}
+
+// writeEditFile writes content to a new temp file, returning its path
+func writeEditFile(pattern, content string) (string, error) {
+	tmpFile, err := os.CreateTemp("", pattern)
+	if err != nil {
+		return "", err
+	}
+	tmpPath := tmpFile.Name()
+
+	if _, err := tmpFile.WriteString(content); err != nil {
+		tmpFile.Close()
+		os.Remove(tmpPath)
+		return "", err
+	}
+	if err := tmpFile.Close(); err != nil {
+		os.Remove(tmpPath)
+		return "", err
+	}
+	return tmpPath, nil
+}
@@ -0,0 +1,21 @@
+package table
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestEditInEditorReportsTempFileErrors(t *testing.T) {
+	t.Setenv("TMPDIR", filepath.Join(t.TempDir(), "missing"))
+
+	updated, cmd := Model{}.editInEditor("squix-test-*.sql", "SELECT 1", CursorAtEndOfFile, func(string, bool) tea.Msg { return nil })
+	if cmd != nil {
+		t.Error("editInEditor() started the editor without a temp file")
+	}
+	if status := updated.(Model).statusMessage; !strings.Contains(status, "Could not open the editor") {
+		t.Errorf("statusMessage = %q, want the temp file error", status)
+	}
+}
@@ -90,5 +90,5 @@ func (m Model) handleQueryEditComplete(msg queryEditCompleteMsg) (tea.Model, tea
 	m.editedQuery = msg.sql
 	m.shouldRerunQuery = true
 
-	return m, tea.Quit
+	return m.quit()
 }
@@ -36,6 +36,7 @@ const (
 	CursorAtUpdateValue cursorPositionHint = iota // Inside the value in UPDATE SET col = 'value'
 	CursorAtWhereClause                            // Inside the value in WHERE col = 'value'
 	CursorAtEndOfFile                              // At the end of the file
+	CursorAtInsertValues                           // Inside VALUES (...) of an INSERT
 )
 
 func findCursorPosition(content string, hint cursorPositionHint) (line int, col int) {
@@ -44,7 +45,7 @@ func findCursorPosition(content string, hint cursorPositionHint) (line int, col
 	switch hint {
 	case CursorAtUpdateValue:
 		// Look for:  SET column = 'value'
-		re := regexp.MustCompile(`SET\s+\w+\s*=\s*'`)
+		re := regexp.MustCompile(`SET\s+\w+\s*=\s*'?`)
 		for i, lineText := range lines {
 			match := re.FindStringIndex(lineText)
 			if match != nil {
@@ -73,6 +74,17 @@ func findCursorPosition(content string, hint cursorPositionHint) (line int, col
 		}
 		return len(lines), 1
 
+	case CursorAtInsertValues:
+		// Look for: VALUES ( and position after the parenthesis
+		re := regexp.MustCompile(`(?i)VALUES\s*\(`)
+		for i, lineText := range lines {
+			match := re.FindStringIndex(lineText)
+			if match != nil {
+				return i + 1, match[1] + 1
+			}
+		}
+		return len(lines), 1
+
 	case CursorAtEndOfFile:
 		for i := len(lines) - 1; i >= 0; i-- {
 			if strings.TrimSpace(lines[i]) != "" {
@@ -1,6 +1,7 @@
 package table
 
 import (
+	"bytes"
 	"encoding/csv"
 	"encoding/json"
 	"fmt"
@@ -9,6 +10,7 @@ import (
 
 	"github.com/atotto/clipboard"
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/eduardofuncao/squix/internal/db"
 )
 
 type exportFormat string
@@ -85,9 +87,9 @@ func (m Model) executeExportForFormat(key string) (Model, tea.Cmd) {
 		headers = append(headers, m.columns[col])
 	}
 
-	rows := make([][]string, 0)
+	rows := make([][]db.Cell, 0)
 	for row := minRow; row <= maxRow; row++ {
-		dataRow := make([]string, 0)
+		dataRow := make([]db.Cell, 0)
 		for col := minCol; col <= maxCol; col++ {
 			dataRow = append(dataRow, m.data[row][col])
 		}
@@ -114,26 +116,26 @@ func (m Model) executeExportForFormat(key string) (Model, tea.Cmd) {
 	}
 }
 
-func (m Model) formatExportContent(headers []string, rows [][]string, format exportFormat) (string, error) {
+func (m Model) formatExportContent(headers []string, rows [][]db.Cell, format exportFormat) (string, error) {
 	switch format {
 	case exportCSV:
-		return m.formatCSV(headers, rows)
+		return formatCSV(headers, rows)
 	case exportJSON:
-		return m.formatJSON(headers, rows)
+		return formatJSON(headers, rows)
 	case exportTSV:
-		return m.formatTSV(headers, rows)
+		return formatTSV(headers, rows)
 	case exportHTML:
 		return m.formatHTML(headers, rows)
 	case exportSQL:
 		return m.formatSQL(headers, rows)
 	case exportMarkdown:
-		return m.formatMarkdown(headers, rows)
+		return formatMarkdown(headers, rows)
 	default:
-		return m.formatCSV(headers, rows)
+		return formatCSV(headers, rows)
 	}
 }
 
-func (m Model) formatCSV(headers []string, rows [][]string) (string, error) {
+func formatCSV(headers []string, rows [][]db.Cell) (string, error) {
 	var buf strings.Builder
 	writer := csv.NewWriter(&buf)
 
@@ -142,7 +144,7 @@ func (m Model) formatCSV(headers []string, rows [][]string) (string, error) {
 	}
 
 	for _, row := range rows {
-		if err := writer.Write(row); err != nil {
+		if err := writer.Write(db.CellStrings(row)); err != nil {
 			return "", err
 		}
 	}
@@ -155,17 +157,44 @@ func (m Model) formatCSV(headers []string, rows [][]string) (string, error) {
 	return buf.String(), nil
 }
 
-func (m Model) formatJSON(headers []string, rows [][]string) (string, error) {
-	objects := make([]map[string]string, 0, len(rows))
+// jsonRow is a row as a JSON object, with its keys in column order
+type jsonRow struct {
+	headers []string
+	cells   []db.Cell
+}
 
-	for _, row := range rows {
-		obj := make(map[string]string)
-		for i, header := range headers {
-			if i < len(row) {
-				obj[header] = row[i]
-			}
+func (r jsonRow) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, header := range r.headers {
+		if i >= len(r.cells) {
+			break
+		}
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		key, err := json.Marshal(header)
+		if err != nil {
+			return nil, err
+		}
+		value, err := json.Marshal(r.cells[i])
+		if err != nil {
+			return nil, err
 		}
-		objects = append(objects, obj)
+		buf.Write(key)
+		buf.WriteByte(':')
+		buf.Write(value)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+// formatJSON writes SQL NULL as null, and every other value as a string
+func formatJSON(headers []string, rows [][]db.Cell) (string, error) {
+	objects := make([]jsonRow, 0, len(rows))
+
+	for _, row := range rows {
+		objects = append(objects, jsonRow{headers: headers, cells: row})
 	}
 
 	data, err := json.MarshalIndent(objects, "", "  ")
@@ -176,18 +205,18 @@ func (m Model) formatJSON(headers []string, rows [][]string) (string, error) {
 	return string(data), nil
 }
 
-func (m Model) formatTSV(headers []string, rows [][]string) (string, error) {
+func formatTSV(headers []string, rows [][]db.Cell) (string, error) {
 	var buf strings.Builder
 
 	buf.WriteString(strings.Join(headers, "\t") + "\n")
 	for _, row := range rows {
-		buf.WriteString(strings.Join(row, "\t") + "\n")
+		buf.WriteString(strings.Join(db.CellStrings(row), "\t") + "\n")
 	}
 
 	return buf.String(), nil
 }
 
-func (m Model) formatHTML(headers []string, rows [][]string) (string, error) {
+func (m Model) formatHTML(headers []string, rows [][]db.Cell) (string, error) {
 	var buf strings.Builder
 
 	// HTML document structure
@@ -237,7 +266,7 @@ func (m Model) formatHTML(headers []string, rows [][]string) (string, error) {
 		}
 		buf.WriteString(fmt.Sprintf("<tr%s>\n", rowClass))
 		for _, cell := range row {
-			buf.WriteString(fmt.Sprintf("<td>%s</td>\n", escapeHTML(cell)))
+			buf.WriteString(fmt.Sprintf("<td>%s</td>\n", escapeHTML(cell.String())))
 		}
 		buf.WriteString("</tr>\n")
 	}
@@ -259,11 +288,17 @@ func escapeHTML(s string) string {
 	return s
 }
 
-func (m Model) formatSQL(headers []string, rows [][]string) (string, error) {
+func (m Model) formatSQL(headers []string, rows [][]db.Cell) (string, error) {
 	if m.tableName == "" {
 		return "", fmt.Errorf("no table name available for SQL export")
 	}
 
+	dbType := ""
+	if m.dbConnection != nil {
+		dbType = m.dbConnection.GetDbType()
+	}
+	columnTypes := m.columnTypesByName()
+
 	var buf strings.Builder
 
 	for _, row := range rows {
@@ -271,19 +306,15 @@ func (m Model) formatSQL(headers []string, rows [][]string) (string, error) {
 
 		columns := make([]string, 0, len(headers))
 		for _, header := range headers {
-			columns = append(columns, fmt.Sprintf(`"%s"`, header))
+			columns = append(columns, db.QuoteIdentifier(header, dbType))
 		}
 
 		buf.WriteString(strings.Join(columns, ", "))
 		buf.WriteString(") VALUES (")
 
 		values := make([]string, 0, len(row))
-		for _, val := range row {
-			if val == "" || val == "NULL" {
-				values = append(values, "NULL")
-			} else {
-				values = append(values, fmt.Sprintf("'%s'", strings.ReplaceAll(val, "'", "''")))
-			}
+		for i, val := range row {
+			values = append(values, db.TypedLiteral(val, columnTypes[strings.ToLower(headers[i])], dbType))
 		}
 
 		buf.WriteString(strings.Join(values, ", "))
@@ -293,7 +324,7 @@ func (m Model) formatSQL(headers []string, rows [][]string) (string, error) {
 	return buf.String(), nil
 }
 
-func (m Model) formatMarkdown(headers []string, rows [][]string) (string, error) {
+func formatMarkdown(headers []string, rows [][]db.Cell) (string, error) {
 	var buf strings.Builder
 
 	buf.WriteString("|")
@@ -311,7 +342,7 @@ func (m Model) formatMarkdown(headers []string, rows [][]string) (string, error)
 	for _, row := range rows {
 		buf.WriteString("|")
 		for _, cell := range row {
-			buf.WriteString(" " + cell + " |")
+			buf.WriteString(" " + cell.String() + " |")
 		}
 		buf.WriteString("\n")
 	}
@@ -0,0 +1,70 @@
+package table
+
+import (
+	"testing"
+
+	"github.com/eduardofuncao/squix/internal/db"
+)
+
+func TestExportNullCells(t *testing.T) {
+	headers := []string{"name", "note"}
+	rows := [][]db.Cell{{db.TextCell("NULL"), db.NullCell}}
+
+	got, err := formatJSON(headers, rows)
+	if err != nil {
+		t.Fatalf("formatJSON() error: %v", err)
+	}
+	want := "[\n  {\n    \"name\": \"NULL\",\n    \"note\": null\n  }\n]"
+	if got != want {
+		t.Errorf("formatJSON() = %q, want %q", got, want)
+	}
+
+	m := Model{tableName: "users"}
+	got, err = m.formatSQL(headers, rows)
+	if err != nil {
+		t.Fatalf("formatSQL() error: %v", err)
+	}
+	want = "INSERT INTO users (\"name\", \"note\") VALUES ('NULL', NULL);\n"
+	if got != want {
+		t.Errorf("formatSQL() = %q, want %q", got, want)
+	}
+}
+
+func TestExportSQLDialect(t *testing.T) {
+	headers := []string{"id", "name", "price", "active"}
+	rows := [][]db.Cell{{db.TextCell("1"), db.TextCell("it's"), db.TextCell("9.50"), db.TextCell("true")}}
+	columnTypes := []string{"INT", "VARCHAR", "DECIMAL", "BOOL"}
+
+	mysql, _ := db.NewMySQLConnection("my", "")
+	sqlserver, _ := db.NewSQLServerConnection("ms", "")
+
+	tests := []struct {
+		name string
+		conn db.DatabaseConnection
+		want string
+	}{
+		{
+			name: "MySQL",
+			conn: mysql,
+			want: "INSERT INTO users (`id`, `name`, `price`, `active`) VALUES (1, 'it''s', 9.50, TRUE);\n",
+		},
+		{
+			name: "SQL Server",
+			conn: sqlserver,
+			want: "INSERT INTO users ([id], [name], [price], [active]) VALUES (1, 'it''s', 9.50, 1);\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := Model{tableName: "users", dbConnection: tt.conn, columns: headers, columnTypes: columnTypes}
+			got, err := m.formatSQL(headers, rows)
+			if err != nil {
+				t.Fatalf("formatSQL() error: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("formatSQL() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
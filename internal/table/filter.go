@@ -0,0 +1,165 @@
+package table
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/eduardofuncao/squix/internal/db"
+	"github.com/eduardofuncao/squix/internal/styles"
+)
+
+// allColumns as filterCol matches a row when any of its cells matches
+const allColumns = -1
+
+// startFilter opens the filter prompt for the selected column, or for every
+// column with F
+func (m Model) startFilter(col int) Model {
+	m.prompt = promptFilter
+	m.filterCol = col
+	m.promptInput = ""
+	if m.filterActive() && m.filterCol == col {
+		m.promptInput = m.filterExpr
+	}
+	return m
+}
+
+func (m Model) filterActive() bool {
+	return m.filterMatch != nil
+}
+
+func (m Model) filterScope(col int) string {
+	if col == allColumns || col >= m.numCols() {
+		return "*"
+	}
+	return m.columns[col]
+}
+
+func (m Model) applyFilterInput(expr string) (Model, tea.Cmd) {
+	expr = strings.TrimSpace(expr)
+	if expr == "" {
+		return m.clearFilter(), nil
+	}
+
+	match, err := parseFilter(expr)
+	if err != nil {
+		m.statusMessage = styles.Error.Render(fmt.Sprintf("✗ Invalid filter: %v", err))
+		return m, nil
+	}
+
+	m.filterExpr = expr
+	m.filterMatch = match
+	m = m.rebuildRows()
+
+	m.selectedRow = 0
+	m.offsetY = 0
+	m.visualMode = false
+	m = m.refreshLayout()
+
+	return m.loadMoreIfNeeded()
+}
+
+func (m Model) clearFilter() Model {
+	if !m.filterActive() {
+		return m
+	}
+
+	var current []db.Cell
+	if m.selectedRow < len(m.data) {
+		current = m.data[m.selectedRow]
+	}
+
+	m.filterExpr = ""
+	m.filterMatch = nil
+	m = m.rebuildRows()
+	m.offsetY = 0
+	m.selectedRow = 0
+	m = m.refreshLayout()
+
+	// Stay on the same row once all rows are back
+	if selected := indexOfRow(m.data, current); selected >= 0 {
+		m = m.scrollTo(selected, m.selectedCol)
+	}
+	return m
+}
+
+func (m Model) filterRows(rows [][]db.Cell) [][]db.Cell {
+	filtered := [][]db.Cell{}
+	for _, row := range rows {
+		if m.rowMatchesFilter(row) {
+			filtered = append(filtered, row)
+		}
+	}
+	return filtered
+}
+
+func (m Model) rowMatchesFilter(row []db.Cell) bool {
+	if m.filterCol != allColumns && m.filterCol < len(row) {
+		return m.filterMatch(row[m.filterCol].String())
+	}
+	for _, cell := range row {
+		if m.filterMatch(cell.String()) {
+			return true
+		}
+	}
+	return false
+}
+
+// parseFilter turns a filter expression into a cell matcher. Expressions
+// starting with >, >=, <, <=, = or != compare the cell with the operand,
+// numerically when the operand is a number. Anything else is a regex
+func parseFilter(expr string) (func(string) bool, error) {
+	for _, op := range []string{">=", "<=", "!=", ">", "<", "="} {
+		if !strings.HasPrefix(expr, op) {
+			continue
+		}
+		operand := strings.TrimSpace(strings.TrimPrefix(expr, op))
+		if operand == "" {
+			return nil, fmt.Errorf("missing value after %s", op)
+		}
+		return compareFilter(op, operand), nil
+	}
+
+	re := compileSearch(expr)
+	return re.MatchString, nil
+}
+
+func compareFilter(op, operand string) func(string) bool {
+	number, err := strconv.ParseFloat(operand, 64)
+	numeric := err == nil
+
+	return func(cell string) bool {
+		var cmp int
+		if numeric {
+			value, err := strconv.ParseFloat(strings.TrimSpace(cell), 64)
+			if err != nil {
+				// NULLs and text never satisfy a numeric comparison
+				return op == "!="
+			}
+			switch {
+			case value < number:
+				cmp = -1
+			case value > number:
+				cmp = 1
+			}
+		} else {
+			cmp = strings.Compare(strings.ToLower(cell), strings.ToLower(operand))
+		}
+
+		switch op {
+		case ">":
+			return cmp > 0
+		case ">=":
+			return cmp >= 0
+		case "<":
+			return cmp < 0
+		case "<=":
+			return cmp <= 0
+		case "!=":
+			return cmp != 0
+		default:
+			return cmp == 0
+		}
+	}
+}
@@ -0,0 +1,74 @@
+package table
+
+import (
+	"testing"
+
+	"github.com/eduardofuncao/squix/internal/db"
+)
+
+func TestParseFilter(t *testing.T) {
+	tests := []struct {
+		name  string
+		expr  string
+		cell  string
+		match bool
+	}{
+		{name: "Greater than number", expr: ">100", cell: "150", match: true},
+		{name: "Greater than compares numerically", expr: ">100", cell: "99", match: false},
+		{name: "Greater or equal", expr: ">= 100", cell: "100", match: true},
+		{name: "Less than decimal", expr: "<1.5", cell: "1.25", match: true},
+		{name: "NULL never passes numeric comparison", expr: ">0", cell: "NULL", match: false},
+		{name: "Not equal keeps non-numeric cells", expr: "!=0", cell: "NULL", match: true},
+		{name: "Equals text ignores case", expr: "=Active", cell: "active", match: true},
+		{name: "Text comparison", expr: "<m", cell: "alice", match: true},
+		{name: "Regex", expr: "^bo.$", cell: "bob", match: true},
+		{name: "Regex ignores case without uppercase", expr: "alice", cell: "Alice Smith", match: true},
+		{name: "Regex matches case with uppercase", expr: "Alice", cell: "alice", match: false},
+		{name: "Invalid regex is literal", expr: "a(b", cell: "xa(by", match: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			match, err := parseFilter(tt.expr)
+			if err != nil {
+				t.Fatalf("parseFilter(%q) error: %v", tt.expr, err)
+			}
+			if got := match(tt.cell); got != tt.match {
+				t.Errorf("parseFilter(%q)(%q) = %v, want %v", tt.expr, tt.cell, got, tt.match)
+			}
+		})
+	}
+}
+
+func TestParseFilterMissingOperand(t *testing.T) {
+	if _, err := parseFilter(">="); err == nil {
+		t.Error("parseFilter(\">=\") expected an error")
+	}
+}
+
+func TestFilterKeepsUnfilteredRows(t *testing.T) {
+	m := Model{
+		columns: []string{"id", "name"},
+		data: [][]db.Cell{
+			{db.TextCell("1"), db.TextCell("ann")},
+			{db.TextCell("2"), db.TextCell("bob")},
+			{db.TextCell("3"), db.TextCell("carl")},
+		},
+	}
+
+	m.filterCol = 0
+	m, _ = m.applyFilterInput(">1")
+	if m.numRows() != 2 || len(m.allData) != 3 {
+		t.Fatalf("after filter got %d visible of %d rows, want 2 of 3", m.numRows(), len(m.allData))
+	}
+
+	m = m.removeRow(0)
+	if len(m.allData) != 2 || m.allData[1][1].Text != "carl" {
+		t.Errorf("removeRow did not drop the row from the unfiltered data: %v", m.allData)
+	}
+
+	m = m.clearFilter()
+	if m.filterActive() || m.numRows() != 2 {
+		t.Errorf("clearFilter left %d rows, filter active %v", m.numRows(), m.filterActive())
+	}
+}
@@ -0,0 +1,239 @@
+package table
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/eduardofuncao/squix/internal/db"
+	"github.com/eduardofuncao/squix/internal/styles"
+)
+
+// insertRow opens an INSERT for the table in the editor. The template lists
+// every column the database doesn't fill in itself, with its default or
+// NULL, or with the values of the current row when duplicating it
+func (m Model) insertRow(duplicate bool) (tea.Model, tea.Cmd) {
+	if m.tableName == "" || m.dbConnection == nil || m.isTablesList {
+		return m, nil
+	}
+	if duplicate && (m.selectedRow < 0 || m.selectedRow >= m.numRows()) {
+		return m, nil
+	}
+
+	insertStmt, skipped := m.buildInsertStatement(duplicate)
+
+	header := "-- INSERT Statement\n"
+	if len(skipped) > 0 {
+		header += fmt.Sprintf("-- Left out, filled in by the database: %s\n", strings.Join(skipped, ", "))
+	}
+	header += "-- To cancel, exit without saving (e.g., :q! in vim)\n--\n"
+	content := header + insertStmt
+
+	return m.editInEditor("squix-insert-*.sql", content, CursorAtInsertValues, func(sql string, cancelled bool) tea.Msg {
+		return insertCompleteMsg{sql: sql, cancelled: cancelled}
+	})
+}
+
+// Message sent when insert editor completes
+type insertCompleteMsg struct {
+	sql       string
+	cancelled bool
+}
+
+func (m Model) handleInsertComplete(msg insertCompleteMsg) (tea.Model, tea.Cmd) {
+	if msg.cancelled {
+		m.statusMessage = styles.Error.Render("✗ Insert cancelled")
+		return m, tea.Tick(time.Millisecond*500, func(t time.Time) tea.Msg {
+			return blinkMsg{}
+		})
+	}
+
+	if err := validateInsertStatement(msg.sql); err != nil {
+		m.statusMessage = styles.Error.Render(fmt.Sprintf("✗ Insert validation failed: %v", err))
+		return m, nil
+	}
+
+	cleanSQL := m.cleanSQLForDisplay(msg.sql)
+	m.lastExecutedQuery = cleanSQL
+
+	m, err := m.execEdit(strings.TrimSuffix(cleanSQL, ";"))
+	if err != nil {
+		return m.editFailed("insert", err)
+	}
+
+	// Show the new row below the current one, with the values as written.
+	// Whatever the database fills in shows once the query runs again
+	row := make([]db.Cell, len(m.columns))
+	if columns, values, ok := parseInsertValues(cleanSQL); ok {
+		for i, col := range m.columns {
+			if j := slices.IndexFunc(columns, func(c string) bool { return strings.EqualFold(c, col) }); j >= 0 {
+				row[i] = values[j]
+			}
+		}
+	}
+	m = m.insertLoadedRow(row)
+
+	m.statusMessage = styles.Success.Render("✓ Inserted 1 row")
+	m = m.addPending(pendingChange{row: row, inserted: true})
+
+	m.blinkUpdatedCell = true
+	m.updatedRow = m.selectedRow
+	m.updatedCol = m.selectedCol
+
+	return m.refreshLayout(), tea.Batch(
+		tea.ClearScreen,
+		m.blinkCmd(),
+	)
+}
+
+// buildInsertStatement fills the INSERT template from the table metadata,
+// returning the columns it left out
+func (m Model) buildInsertStatement(duplicate bool) (string, []string) {
+	columns := m.columns
+	var defaults, generated []string
+	if metadata, err := m.dbConnection.GetTableMetadata(m.tableName); err == nil && len(metadata.Columns) > 0 {
+		columns = metadata.Columns
+		defaults = metadata.ColumnDefaults
+		generated = metadata.GeneratedColumns
+	}
+
+	var insertColumns, values, skipped []string
+	for i, col := range columns {
+		if slices.ContainsFunc(generated, func(g string) bool { return strings.EqualFold(g, col) }) {
+			skipped = append(skipped, col)
+			continue
+		}
+
+		value := "NULL"
+		if i < len(defaults) && defaults[i] != "" {
+			value = defaults[i]
+		}
+		// A copied primary key would only clash with the original row, so
+		// every key column keeps its default
+		if duplicate && !m.isPrimaryKey(col) {
+			if j := slices.IndexFunc(m.columns, func(c string) bool { return strings.EqualFold(c, col) }); j >= 0 {
+				value = db.CellLiteral(m.data[m.selectedRow][j])
+			}
+		}
+
+		insertColumns = append(insertColumns, col)
+		values = append(values, value)
+	}
+
+	return m.dbConnection.BuildInsertStatement(m.tableName, insertColumns, values), skipped
+}
+
+// insertLoadedRow adds a row below the current one and selects it
+func (m Model) insertLoadedRow(row []db.Cell) Model {
+	if m.allData == nil {
+		index := min(m.selectedRow+1, len(m.data))
+		m.data = slices.Insert(m.data, index, row)
+		m.selectedRow = index
+		return m
+	}
+
+	index := len(m.allData)
+	if m.selectedRow < m.numRows() {
+		if i := indexOfRow(m.allData, m.data[m.selectedRow]); i >= 0 {
+			index = i + 1
+		}
+	}
+	m.allData = slices.Insert(m.allData, index, row)
+	m = m.rebuildRows()
+	if i := indexOfRow(m.data, row); i >= 0 {
+		m.selectedRow = i
+	}
+	return m
+}
+
+var insertPattern = regexp.MustCompile(`(?is)^INSERT\s+INTO\s+\S+\s*\((.*?)\)\s*VALUES\s*\((.*)\)\s*;?\s*$`)
+
+// parseInsertValues reads the columns and values of a single row INSERT.
+// Quoted literals are unquoted, a bare NULL is NULL and anything else is
+// kept as written
+func parseInsertValues(sql string) ([]string, []db.Cell, bool) {
+	match := insertPattern.FindStringSubmatch(strings.TrimSpace(sql))
+	if match == nil {
+		return nil, nil, false
+	}
+
+	columns := splitSQLList(match[1])
+	literals := splitSQLList(match[2])
+	if len(columns) != len(literals) {
+		return nil, nil, false
+	}
+
+	for i, col := range columns {
+		columns[i] = strings.Trim(col, "\"`[]")
+	}
+	values := make([]db.Cell, len(literals))
+	for i, value := range literals {
+		if strings.HasPrefix(value, "N'") {
+			value = value[1:]
+		}
+		switch {
+		case len(value) >= 2 && strings.HasPrefix(value, "'") && strings.HasSuffix(value, "'"):
+			values[i] = db.TextCell(strings.ReplaceAll(value[1:len(value)-1], "''", "'"))
+		case strings.EqualFold(value, "NULL"):
+			values[i] = db.NullCell
+		default:
+			values[i] = db.TextCell(value)
+		}
+	}
+	return columns, values, true
+}
+
+// splitSQLList splits on the commas outside of quotes and parentheses
+func splitSQLList(list string) []string {
+	var items []string
+	var current strings.Builder
+	var quote rune
+	depth := 0
+
+	for _, r := range list {
+		switch {
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			}
+		case r == '\'' || r == '"' || r == '`':
+			quote = r
+		case r == '(':
+			depth++
+		case r == ')':
+			depth--
+		case r == ',' && depth == 0:
+			items = append(items, strings.TrimSpace(current.String()))
+			current.Reset()
+			continue
+		}
+		current.WriteRune(r)
+	}
+	return append(items, strings.TrimSpace(current.String()))
+}
+
+func validateInsertStatement(sql string) error {
+	var result strings.Builder
+	for line := range strings.SplitSeq(sql, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if !strings.HasPrefix(trimmed, "--") && trimmed != "" {
+			result.WriteString(trimmed)
+			result.WriteString(" ")
+		}
+	}
+	cleanSQL := strings.TrimSpace(result.String())
+
+	if cleanSQL == "" {
+		return fmt.Errorf("empty SQL statement")
+	}
+
+	insertRegex := regexp.MustCompile(`(?i)^INSERT\s+INTO\s+`)
+	if !insertRegex.MatchString(cleanSQL) {
+		return fmt.Errorf("not a valid INSERT statement (expected INSERT INTO)")
+	}
+
+	return nil
+}
@@ -0,0 +1,76 @@
+package table
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/eduardofuncao/squix/internal/db"
+)
+
+func TestParseInsertValues(t *testing.T) {
+	tests := []struct {
+		name    string
+		sql     string
+		columns []string
+		values  []db.Cell
+	}{
+		{
+			name:    "Literals and NULL",
+			sql:     "INSERT INTO users (name, age) VALUES ('ann', NULL);",
+			columns: []string{"name", "age"},
+			values:  []db.Cell{db.TextCell("ann"), db.NullCell},
+		},
+		{
+			name:    "Quoted NULL is text",
+			sql:     "INSERT INTO users (name, age) VALUES ('NULL', null)",
+			columns: []string{"name", "age"},
+			values:  []db.Cell{db.TextCell("NULL"), db.NullCell},
+		},
+		{
+			name:    "Commas and quotes inside strings",
+			sql:     "INSERT INTO users (name, note) VALUES ('O''Brien, Pat', 'a (b)')",
+			columns: []string{"name", "note"},
+			values:  []db.Cell{db.TextCell("O'Brien, Pat"), db.TextCell("a (b)")},
+		},
+		{
+			name:    "Expressions kept as written",
+			sql:     "INSERT INTO logs (at, total) VALUES (CURRENT_TIMESTAMP, round(1.5, 0))",
+			columns: []string{"at", "total"},
+			values:  []db.Cell{db.TextCell("CURRENT_TIMESTAMP"), db.TextCell("round(1.5, 0)")},
+		},
+		{
+			name:    "Quoted identifiers",
+			sql:     "INSERT INTO dbo.users ([name], [order]) VALUES (N'ann', 1);",
+			columns: []string{"name", "order"},
+			values:  []db.Cell{db.TextCell("ann"), db.TextCell("1")},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			columns, values, ok := parseInsertValues(tt.sql)
+			if !ok {
+				t.Fatalf("parseInsertValues(%q) failed", tt.sql)
+			}
+			if !slices.Equal(columns, tt.columns) || !slices.Equal(values, tt.values) {
+				t.Errorf("parseInsertValues(%q) = %q, %v, want %q, %v", tt.sql, columns, values, tt.columns, tt.values)
+			}
+		})
+	}
+}
+
+func TestInvalidInsertKeepsTableOpen(t *testing.T) {
+	m := Model{columns: []string{"id", "name"}}
+
+	model, _ := m.handleInsertComplete(insertCompleteMsg{sql: "UPDATE users SET name = 'x'"})
+	if got := model.(Model).statusMessage; !strings.Contains(got, "INSERT INTO") {
+		t.Errorf("insert status = %q, want the validation error", got)
+	}
+}
+
+func TestParseInsertValuesMismatch(t *testing.T) {
+	if _, _, ok := parseInsertValues("INSERT INTO users (name, age) VALUES ('ann')"); ok {
+		t.Error("expected a column count mismatch to fail")
+	}
+}
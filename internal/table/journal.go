@@ -0,0 +1,210 @@
+package table
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/eduardofuncao/squix/internal/db"
+	"github.com/eduardofuncao/squix/internal/styles"
+	"github.com/eduardofuncao/squix/internal/undo"
+)
+
+// Every UPDATE and DELETE made here keeps the rows it touches as they were
+// before, read by the WHERE clause of the statement. Committed changes go
+// to the undo journal, where U or squix undo find them
+
+var whereClausePattern = regexp.MustCompile(`(?is)\bWHERE\b\s+(.+?)\s*;?\s*$`)
+
+// beforeImage reads the rows a statement is about to change. The primary
+// key is what lets the journal put updated rows back
+func (m Model) beforeImage(action, statement string) (undo.Entry, error) {
+	if m.tableName == "" || !m.hasPrimaryKey() {
+		return undo.Entry{}, fmt.Errorf("the table has no primary key")
+	}
+	match := whereClausePattern.FindStringSubmatch(statement)
+	if match == nil {
+		return undo.Entry{}, fmt.Errorf("the statement has no WHERE clause")
+	}
+
+	query, args := m.bindEdit(fmt.Sprintf("SELECT * FROM %s WHERE %s", m.tableName, match[1]))
+	rows, err := m.queryRows(context.Background(), query, args...)
+	if err != nil {
+		return undo.Entry{}, err
+	}
+	columns, columnTypes, data, err := db.FormatTableDataWithTypes(rows)
+	if err != nil {
+		return undo.Entry{}, err
+	}
+
+	entry := undo.Entry{
+		Time:        time.Now(),
+		Connection:  m.dbConnection.GetName(),
+		DBType:      m.dbConnection.GetDbType(),
+		Table:       m.tableName,
+		Action:      action,
+		SQL:         statement,
+		PrimaryKeys: m.primaryKeyCols,
+		Columns:     columns,
+		ColumnTypes: columnTypes,
+		Rows:        data,
+	}
+	if action == undo.ActionUpdate {
+		entry.Changed = db.AssignedColumns(statement)
+	}
+	// Computed and identity columns can't simply be written back
+	if metadata, err := m.dbConnection.GetTableMetadata(m.tableName); err == nil && metadata != nil {
+		entry.Computed = metadata.ComputedColumns
+		entry.Identity = metadata.IdentityColumns
+	}
+	return entry, nil
+}
+
+// execJournaled runs an edit like execEdit, with its literals bound, reading
+// the rows it changes first inside the same transaction
+func (m Model) execJournaled(action, statement string) (Model, undo.Entry, error) {
+	m, err := m.beginEdit()
+	if err != nil {
+		return m, undo.Entry{}, err
+	}
+	entry, captureErr := m.beforeImage(action, statement)
+	bound, args := m.bindEdit(statement)
+	if m, err = m.execEdit(bound, args...); err != nil {
+		return m, undo.Entry{}, err
+	}
+	if captureErr != nil {
+		m.statusMessage = styles.Faint.Render(fmt.Sprintf("No undo for this change: %v", captureErr))
+	}
+	return m, entry, nil
+}
+
+// journal writes the before images of committed changes to the undo journal,
+// returning a warning for the status line when that fails
+func journal(changes []pendingChange) string {
+	var entries []undo.Entry
+	for _, change := range changes {
+		if len(change.journal.Rows) > 0 {
+			entries = append(entries, change.journal)
+		}
+	}
+	if err := undo.Append(entries...); err != nil {
+		return fmt.Sprintf(", but the undo journal could not be written: %v", err)
+	}
+	return ""
+}
+
+// undoChange takes back the newest change: a pending one inside the
+// transaction, otherwise the newest one in the undo journal. The statements
+// reverting it open in the editor first
+func (m Model) undoChange() (tea.Model, tea.Cmd) {
+	if m.dbConnection == nil || m.isTablesList {
+		return m, nil
+	}
+
+	var entry undo.Entry
+	fromPending := len(m.pending) > 0
+	if fromPending {
+		entry = m.pending[len(m.pending)-1].journal
+		if len(entry.Rows) == 0 {
+			m.statusMessage = styles.Error.Render("✗ The last change can't be undone, use :rollback")
+			return m, nil
+		}
+	} else {
+		last, ok, err := undo.Last(m.dbConnection.GetName())
+		if err != nil {
+			m.statusMessage = styles.Error.Render(fmt.Sprintf("✗ %v", err))
+			return m, nil
+		}
+		if !ok {
+			m.statusMessage = styles.Faint.Render("Nothing to undo")
+			return m, nil
+		}
+		entry = last
+	}
+
+	content := entry.Preview() + `
+-- Save and exit to run the statements above
+-- To cancel, exit without saving (e.g., :q! in vim)
+`
+
+	return m.editInEditor("squix-undo-*.sql", content, CursorAtEndOfFile, func(sql string, cancelled bool) tea.Msg {
+		if cancelled {
+			return undoCompleteMsg{cancelled: true}
+		}
+		return undoCompleteMsg{sql: sql, entry: entry, pending: fromPending}
+	})
+}
+
+type undoCompleteMsg struct {
+	sql       string
+	entry     undo.Entry
+	pending   bool // The change was still pending in the transaction
+	cancelled bool
+}
+
+func (m Model) handleUndoComplete(msg undoCompleteMsg) (tea.Model, tea.Cmd) {
+	if msg.cancelled {
+		m.statusMessage = styles.Error.Render("✗ Undo cancelled")
+		return m, m.blinkCmd()
+	}
+
+	if msg.pending {
+		return m.undoPending(msg.sql)
+	}
+
+	// A transaction left open by a failed edit would lock the rows
+	if m.tx != nil {
+		m.tx.Rollback()
+		m.tx = nil
+	}
+	m.releaseRowSource()
+
+	if err := msg.entry.Run(m.dbConnection, msg.sql); err != nil {
+		if errors.Is(err, undo.ErrNothingToRun) {
+			m.statusMessage = styles.Error.Render("✗ Undo cancelled")
+			return m, m.blinkCmd()
+		}
+		m.statusMessage = styles.Error.Render(fmt.Sprintf("✗ Undo failed: %v", err))
+		return m, nil
+	}
+	status := fmt.Sprintf("✓ Undid the %s on %s, rerun the query to see it", msg.entry.Action, msg.entry.Table)
+	if err := undo.MarkUndone(msg.entry); err != nil {
+		status += fmt.Sprintf(", but the undo journal could not be updated: %v", err)
+	}
+	m.statusMessage = styles.Success.Render(status)
+	return m, tea.ClearScreen
+}
+
+// undoPending runs the statements reverting the newest pending change in the
+// transaction, bound like the change itself, and puts its rows back in the
+// table
+func (m Model) undoPending(script string) (tea.Model, tea.Cmd) {
+	statements := db.SplitStatements(script, m.dbConnection.GetDbType())
+	if len(statements) == 0 {
+		m.statusMessage = styles.Error.Render("✗ Undo cancelled")
+		return m, m.blinkCmd()
+	}
+
+	entry := m.pending[len(m.pending)-1].journal
+	for _, statement := range statements {
+		var err error
+		bound, args := entry.Bind(statement, m.dbConnection.GetPlaceholder)
+		if m, err = m.execEdit(bound, args...); err != nil {
+			m.statusMessage = styles.Error.Render(fmt.Sprintf("✗ Undo failed: %v", err))
+			return m, nil
+		}
+	}
+
+	last := len(m.pending) - 1
+	change := m.pending[last]
+	m.pending = m.pending[:last]
+	m = m.restoreRow(change)
+	if m.allData != nil {
+		m = m.rebuildRows()
+	}
+	m.statusMessage = styles.Success.Render(fmt.Sprintf("✓ Undid the %s on %s", change.journal.Action, change.journal.Table))
+	return m.clampSelection().refreshLayout(), tea.ClearScreen
+}
@@ -1,6 +1,8 @@
 package table
 
 import (
+	"database/sql"
+	"regexp"
 	"strings"
 	"time"
 
@@ -22,7 +24,9 @@ type Model struct {
 	columns           []string
 	columnTypes       []string
 	columnFKs         []string  // Maps column index to FK reference (e.g., "people.id")
-	data              [][]string
+	data              [][]db.Cell
+	rowSource         RowSource
+	loadingMore       bool
 	elapsed           time.Duration
 	blinkCopiedCell   bool
 	visualMode        bool
@@ -30,7 +34,7 @@ type Model struct {
 	visualStartCol    int
 	dbConnection      db.DatabaseConnection
 	tableName         string
-	primaryKeyCol     string
+	primaryKeyCols    []string
 	blinkUpdatedCell  bool
 	updatedRow        int
 	updatedCol        int
@@ -53,6 +57,22 @@ type Model struct {
 	exportWaiting     exportWaitingFormatState
 	exportStatus      string
 	uiVisibility      config.UIVisibility
+	prompt            promptKind
+	promptInput       string
+	searchRe          *regexp.Regexp
+	searchOrigin      searchOrigin
+	allData           [][]db.Cell // Loaded rows in query order while a filter or sort is active
+	filterExpr        string
+	filterCol         int
+	filterMatch       func(string) bool
+	sortCol           int
+	sortOrder         sortOrder
+	colWidths         []int
+	customWidths      map[string]int // Widths set with + and -, by column name
+	tx                *sql.Tx        // Open while edits are pending
+	pending           []pendingChange
+	autocommit        bool // The database has no transactions, edits apply right away
+	confirmQuit       bool
 }
 
 type blinkMsg struct{}
@@ -60,10 +80,11 @@ type blinkMsg struct{}
 func New(
 	columns []string,
 	passedColumnTypes []string,
-	data [][]string,
+	data [][]db.Cell,
 	elapsed time.Duration,
 	conn db.DatabaseConnection,
-	tableName, primaryKeyCol string,
+	tableName string,
+	primaryKeyCols []string,
 	query db.Query,
 	columnWidth int,
 	visibility config.UIVisibility,
@@ -116,7 +137,7 @@ func New(
 		}
 	}
 
-	return Model{
+	m := Model{
 		selectedRow:      0,
 		selectedCol:      0,
 		offsetX:          0,
@@ -129,7 +150,7 @@ func New(
 		visualMode:       false,
 		dbConnection:     conn,
 		tableName:        tableName,
-		primaryKeyCol:    primaryKeyCol,
+		primaryKeyCols:   primaryKeyCols,
 		currentQuery:     query,
 		shouldRerunQuery: false,
 		editedQuery:      "",
@@ -137,6 +158,7 @@ func New(
 		isTablesList:     false,
 		uiVisibility:     visibility,
 	}
+	return m.initColumnWidths()
 }
 
 func (m Model) Init() tea.Cmd {
@@ -10,6 +10,8 @@ import (
 
 	"github.com/atotto/clipboard"
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/eduardofuncao/squix/internal/db"
+	"github.com/eduardofuncao/squix/internal/styles"
 )
 
 func (m Model) moveUp() Model {
@@ -35,9 +37,7 @@ func (m Model) moveDown() Model {
 func (m Model) moveLeft() Model {
 	if m.selectedCol > 0 {
 		m.selectedCol--
-		if m.selectedCol < m.offsetX {
-			m.offsetX = m.selectedCol
-		}
+		m = m.ensureColVisible()
 	}
 	return m
 }
@@ -45,9 +45,7 @@ func (m Model) moveLeft() Model {
 func (m Model) moveRight() Model {
 	if m.selectedCol < m.numCols()-1 {
 		m.selectedCol++
-		if m.selectedCol >= m.offsetX+m.visibleCols {
-			m.offsetX = m.selectedCol - m.visibleCols + 1
-		}
+		m = m.ensureColVisible()
 	}
 	return m
 }
@@ -55,15 +53,12 @@ func (m Model) moveRight() Model {
 func (m Model) jumpToFirstCol() Model {
 	m.selectedCol = 0
 	m.offsetX = 0
-	return m
+	return m.ensureColVisible()
 }
 
 func (m Model) jumpToLastCol() Model {
 	m.selectedCol = m.numCols() - 1
-	if m.visibleCols < m.numCols() {
-		m.offsetX = m.numCols() - m.visibleCols
-	}
-	return m
+	return m.ensureColVisible()
 }
 
 func (m Model) jumpToFirstRow() Model {
@@ -144,16 +139,32 @@ func (m Model) copySelection() (Model, tea.Cmd) {
 	for row := minRow; row <= maxRow; row++ {
 		dataRow := make([]string, 0)
 		for col := minCol; col <= maxCol; col++ {
-			dataRow = append(dataRow, m.data[row][col])
+			dataRow = append(dataRow, m.data[row][col].String())
 		}
 		allRows = append(allRows, dataRow)
 	}
 
-	numCols := maxCol - minCol + 1
-	colWidths := make([]int, numCols)
+	content := alignColumns(allRows)
+	clipboard.WriteAll(content)
+
+	m.visualMode = false
+	m.blinkCopiedCell = true
+
+	return m, func() tea.Msg {
+		time.Sleep(200 * time.Millisecond)
+		return blinkMsg{}
+	}
+}
 
+// alignColumns pads every cell to the width of its column and joins the
+// rows with newlines, producing a plain text grid
+func alignColumns(allRows [][]string) string {
+	colWidths := []int{}
 	for _, row := range allRows {
 		for i, cell := range row {
+			if i >= len(colWidths) {
+				colWidths = append(colWidths, 0)
+			}
 			if len(cell) > colWidths[i] {
 				colWidths[i] = len(cell)
 			}
@@ -164,12 +175,13 @@ func (m Model) copySelection() (Model, tea.Cmd) {
 
 	for rowIdx, row := range allRows {
 		for colIdx, cell := range row {
-			paddedCell := fmt.Sprintf("%-*s", colWidths[colIdx], cell)
-			result.WriteString(paddedCell)
-
-			if colIdx < len(row)-1 {
-				result.WriteString("  ")
+			// The last column isn't padded, so lines carry no trailing spaces
+			if colIdx == len(row)-1 {
+				result.WriteString(cell)
+				break
 			}
+			result.WriteString(fmt.Sprintf("%-*s", colWidths[colIdx], cell))
+			result.WriteString("  ")
 		}
 
 		if rowIdx < len(allRows)-1 {
@@ -177,16 +189,7 @@ func (m Model) copySelection() (Model, tea.Cmd) {
 		}
 	}
 
-	content := result.String()
-	clipboard.WriteAll(content)
-
-	m.visualMode = false
-	m.blinkCopiedCell = true
-
-	return m, func() tea.Msg {
-		time.Sleep(200 * time.Millisecond)
-		return blinkMsg{}
-	}
+	return result.String()
 }
 
 func (m Model) showDetailView() Model {
@@ -198,7 +201,7 @@ func (m Model) showDetailView() Model {
 	cellValue := m.data[m.selectedRow][m.selectedCol]
 
 	// Tentar formatar como JSON
-	formattedValue := formatValueIfJSON(cellValue)
+	formattedValue := formatValueIfJSON(cellValue.String())
 
 	m.detailViewMode = true
 	m.detailViewContent = formattedValue
@@ -223,27 +226,20 @@ func (m Model) editFromDetailView() (Model, tea.Cmd) {
 	currentValue := m.data[m.selectedRow][m.selectedCol]
 
 	// If the content is formatted (JSON), use the formatted value
-	if m.detailViewContent != currentValue {
+	if m.detailViewContent != currentValue.String() {
 		// It's formatted, use the formatted content
-		currentValue = m.detailViewContent
+		currentValue = db.TextCell(m.detailViewContent)
 	}
 
-	pkValue := ""
-	if m.primaryKeyCol != "" {
-		for i, col := range m.columns {
-			if col == m.primaryKeyCol {
-				pkValue = m.data[m.selectedRow][i]
-				break
-			}
-		}
-	}
+	pkValues, _ := m.primaryKeyValues()
 
 	updateStmt := m.dbConnection.BuildUpdateStatement(
 		m.tableName,
 		columnName,
 		currentValue,
-		m.primaryKeyCol,
-		pkValue,
+		m.primaryKeyCols,
+		pkValues,
+		m.primaryKeyTypes(),
 	)
 
 	editorCmd := os.Getenv("EDITOR")
@@ -290,8 +286,8 @@ func (m Model) handleDetailViewEditComplete(
 	msg detailViewEditCompleteMsg,
 ) (tea.Model, tea.Cmd) {
 	// Validar o UPDATE statement
-	if err := validateUpdateStatement(msg.sql); err != nil {
-		printError("Update validation failed: %v", err)
+	if err := validateUpdateStatement(msg.sql, m.primaryKeyCols); err != nil {
+		m.statusMessage = styles.Error.Render(fmt.Sprintf("✗ Update validation failed: %v", err))
 		m.detailViewMode = false
 		return m, nil
 	}
@@ -300,14 +296,16 @@ func (m Model) handleDetailViewEditComplete(
 	newValue := m.extractNewValue(msg.sql, m.columns[msg.colIndex])
 
 	// Executar update
-	if err := m.executeUpdate(msg.sql); err != nil {
-		printError("Could not execute update: %v", err)
-		m.detailViewMode = false
-		return m, nil
+	m.detailViewMode = false
+	m, entry, err := m.executeUpdate(msg.sql)
+	if err != nil {
+		return m.editFailed("update", err)
 	}
 
 	// Atualizar dados locais
-	m.data[m.selectedRow][m.selectedCol] = newValue
+	row := m.data[m.selectedRow]
+	m = m.addPending(pendingChange{journal: entry, row: row, col: m.selectedCol, old: row[m.selectedCol]})
+	row[m.selectedCol] = newValue
 
 	// Close detail view and return to table with highlighted cell
 	m.detailViewMode = false
@@ -0,0 +1,155 @@
+package table
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/eduardofuncao/squix/internal/db"
+)
+
+// OutputFormats lists the formats accepted by WriteOutput, used when results
+// are printed to stdout instead of being shown in the TUI
+var OutputFormats = []string{"csv", "json", "ndjson", "tsv", "markdown", "plain"}
+
+func IsValidOutputFormat(format string) bool {
+	for _, f := range OutputFormats {
+		if f == format {
+			return true
+		}
+	}
+	return false
+}
+
+// WriteOutput formats the result set with the given format and writes it to w
+func WriteOutput(w io.Writer, format string, headers []string, rows [][]db.Cell) error {
+	var content string
+	var err error
+
+	switch format {
+	case "csv":
+		content, err = formatCSV(headers, rows)
+	case "json":
+		content, err = formatJSON(headers, rows)
+		content += "\n"
+	case "ndjson":
+		content, err = formatNDJSON(headers, rows)
+	case "tsv":
+		content, err = formatTSV(headers, rows)
+	case "markdown":
+		content, err = formatMarkdown(headers, rows)
+	case "plain":
+		lines := [][]string{headers}
+		for _, row := range rows {
+			lines = append(lines, db.CellStrings(row))
+		}
+		content = alignColumns(lines) + "\n"
+	default:
+		return fmt.Errorf("unknown output format '%s' (expected one of: %s)", format, strings.Join(OutputFormats, ", "))
+	}
+	if err != nil {
+		return err
+	}
+
+	_, err = io.WriteString(w, content)
+	return err
+}
+
+// OutputWriter writes a result set page by page as it is fetched. csv, tsv
+// and ndjson are streamed straight away, while formats that need the whole
+// result set (json, markdown, plain) are buffered until Close
+type OutputWriter struct {
+	w         io.Writer
+	format    string
+	headers   []string
+	csvWriter *csv.Writer
+	started   bool
+	pending   [][]db.Cell
+}
+
+func NewOutputWriter(w io.Writer, format string, headers []string) (*OutputWriter, error) {
+	if !IsValidOutputFormat(format) {
+		return nil, fmt.Errorf("unknown output format '%s' (expected one of: %s)", format, strings.Join(OutputFormats, ", "))
+	}
+	return &OutputWriter{w: w, format: format, headers: headers}, nil
+}
+
+func (o *OutputWriter) WriteRows(rows [][]db.Cell) error {
+	switch o.format {
+	case "csv":
+		if o.csvWriter == nil {
+			o.csvWriter = csv.NewWriter(o.w)
+		}
+		if !o.started {
+			if err := o.csvWriter.Write(o.headers); err != nil {
+				return err
+			}
+		}
+		for _, row := range rows {
+			if err := o.csvWriter.Write(db.CellStrings(row)); err != nil {
+				return err
+			}
+		}
+		o.csvWriter.Flush()
+		if err := o.csvWriter.Error(); err != nil {
+			return err
+		}
+	case "tsv":
+		var buf strings.Builder
+		if !o.started {
+			buf.WriteString(strings.Join(o.headers, "\t") + "\n")
+		}
+		for _, row := range rows {
+			buf.WriteString(strings.Join(db.CellStrings(row), "\t") + "\n")
+		}
+		if _, err := io.WriteString(o.w, buf.String()); err != nil {
+			return err
+		}
+	case "ndjson":
+		content, err := formatNDJSON(o.headers, rows)
+		if err != nil {
+			return err
+		}
+		if _, err := io.WriteString(o.w, content); err != nil {
+			return err
+		}
+	default:
+		o.pending = append(o.pending, rows...)
+	}
+
+	o.started = true
+	return nil
+}
+
+// Close writes out buffered formats, and the header of streamed formats when
+// the result set was empty
+func (o *OutputWriter) Close() error {
+	switch o.format {
+	case "csv", "tsv":
+		if !o.started {
+			return o.WriteRows(nil)
+		}
+		return nil
+	case "ndjson":
+		return nil
+	default:
+		return WriteOutput(o.w, o.format, o.headers, o.pending)
+	}
+}
+
+func formatNDJSON(headers []string, rows [][]db.Cell) (string, error) {
+	var buf strings.Builder
+
+	for _, row := range rows {
+		line, err := json.Marshal(jsonRow{headers: headers, cells: row})
+		if err != nil {
+			return "", err
+		}
+		buf.Write(line)
+		buf.WriteString("\n")
+	}
+
+	return buf.String(), nil
+}
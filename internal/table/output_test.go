@@ -0,0 +1,51 @@
+package table
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/eduardofuncao/squix/internal/db"
+)
+
+func TestWriteOutputColumnOrder(t *testing.T) {
+	headers := []string{"name", "id", "age"}
+	rows := [][]db.Cell{
+		{db.TextCell("ann"), db.TextCell("1"), db.TextCell("30")},
+		{db.TextCell("bob"), db.TextCell("2"), db.NullCell},
+	}
+
+	tests := []struct {
+		format string
+		want   string
+	}{
+		{
+			format: "ndjson",
+			want:   "{\"name\":\"ann\",\"id\":\"1\",\"age\":\"30\"}\n{\"name\":\"bob\",\"id\":\"2\",\"age\":null}\n",
+		},
+		{
+			format: "plain",
+			want:   "name  id  age\nann   1   30\nbob   2   NULL\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.format, func(t *testing.T) {
+			var buf strings.Builder
+			if err := WriteOutput(&buf, tt.format, headers, rows); err != nil {
+				t.Fatalf("WriteOutput(%s) error: %v", tt.format, err)
+			}
+			if got := buf.String(); got != tt.want {
+				t.Errorf("WriteOutput(%s) = %q, want %q", tt.format, got, tt.want)
+			}
+		})
+	}
+
+	var buf strings.Builder
+	if err := WriteOutput(&buf, "json", headers, rows[:1]); err != nil {
+		t.Fatalf("WriteOutput(json) error: %v", err)
+	}
+	if got := buf.String(); strings.Index(got, `"name"`) > strings.Index(got, `"id"`) ||
+		strings.Index(got, `"id"`) > strings.Index(got, `"age"`) {
+		t.Errorf("WriteOutput(json) keys out of column order: %s", got)
+	}
+}
@@ -0,0 +1,81 @@
+package table
+
+import (
+	"fmt"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/eduardofuncao/squix/internal/db"
+	"github.com/eduardofuncao/squix/internal/styles"
+)
+
+// RowSource supplies further pages of a result set on demand, so only the
+// rows the user scrolls to are fetched from the database
+type RowSource interface {
+	NextPage() ([][]db.Cell, error)
+	Done() bool
+	Close() error
+}
+
+type rowsLoadedMsg struct {
+	rows [][]db.Cell
+	err  error
+}
+
+func (m Model) hasMoreRows() bool {
+	return m.rowSource != nil && !m.rowSource.Done()
+}
+
+// loadMoreIfNeeded fetches the next page in the background once the
+// selection gets within a screen of the last loaded row
+func (m Model) loadMoreIfNeeded() (Model, tea.Cmd) {
+	if m.loadingMore || !m.hasMoreRows() {
+		return m, nil
+	}
+	if m.selectedRow < m.numRows()-m.visibleRows-1 {
+		return m, nil
+	}
+
+	m.loadingMore = true
+	source := m.rowSource
+	return m, func() tea.Msg {
+		rows, err := source.NextPage()
+		return rowsLoadedMsg{rows: rows, err: err}
+	}
+}
+
+func (m Model) handleRowsLoaded(msg rowsLoadedMsg) (tea.Model, tea.Cmd) {
+	m.loadingMore = false
+	m = m.appendRows(msg.rows)
+
+	if msg.err != nil {
+		m.statusMessage = styles.Error.Render(fmt.Sprintf("✗ Could not load more rows: %v", msg.err))
+	}
+
+	// Rows may now fill space that was left empty before
+	m = m.refreshLayout()
+
+	return m.loadMoreIfNeeded()
+}
+
+// releaseRowSource stops paging before a write on databases where an open
+// cursor holds a read lock on the whole file, otherwise the write would fail
+// with "database is locked"
+func (m Model) releaseRowSource() {
+	if !m.hasMoreRows() || m.dbConnection == nil {
+		return
+	}
+	if m.dbConnection.GetDbType() == "sqlite" {
+		m.rowSource.Close()
+	}
+}
+
+func (m Model) rowCountLabel() string {
+	more := ""
+	if m.hasMoreRows() {
+		more = "+"
+	}
+	if m.filterActive() {
+		return fmt.Sprintf("%d/%d%s", m.numRows(), len(m.allData), more)
+	}
+	return fmt.Sprintf("%d%s", m.numRows(), more)
+}
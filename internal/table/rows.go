@@ -0,0 +1,94 @@
+package table
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/eduardofuncao/squix/internal/db"
+)
+
+// While a filter or a sort is active, allData keeps every loaded row in the
+// order it came from the database and data is the filtered, sorted view of
+// it. Both share the row slices, so editing a cell in data updates allData
+
+func (m Model) viewActive() bool {
+	return m.filterActive() || m.sortActive()
+}
+
+// rebuildRows recomputes data from allData after the filter or sort changed
+func (m Model) rebuildRows() Model {
+	if m.allData == nil {
+		m.allData = m.data
+	}
+
+	if !m.viewActive() {
+		m.data = m.allData
+		m.allData = nil
+		return m
+	}
+
+	rows := m.allData
+	if m.filterActive() {
+		rows = m.filterRows(rows)
+	} else {
+		rows = append([][]db.Cell{}, rows...)
+	}
+	if m.sortActive() {
+		m.sortRows(rows)
+	}
+	m.data = rows
+	return m
+}
+
+// appendRows adds a freshly loaded page, filtered and sorted into the view
+func (m Model) appendRows(rows [][]db.Cell) Model {
+	if !m.viewActive() {
+		m.data = append(m.data, rows...)
+		return m
+	}
+	m.allData = append(m.allData, rows...)
+	if m.sortActive() {
+		return m.rebuildRows()
+	}
+	m.data = append(m.data, m.filterRows(rows)...)
+	return m
+}
+
+// removeRow drops a visible row, and the same row from the unfiltered data
+func (m Model) removeRow(index int) Model {
+	if m.allData != nil {
+		if i := indexOfRow(m.allData, m.data[index]); i >= 0 {
+			m.allData = append(m.allData[:i], m.allData[i+1:]...)
+		}
+	}
+	m.data = append(m.data[:index], m.data[index+1:]...)
+	return m
+}
+
+// loadedRows counts every loaded row, visible or not
+func (m Model) loadedRows() int {
+	if m.allData != nil {
+		return len(m.allData)
+	}
+	return len(m.data)
+}
+
+// refreshLayout recomputes the visible rows after the row count changed
+func (m Model) refreshLayout() Model {
+	if m.width > 0 {
+		m = m.handleWindowResize(tea.WindowSizeMsg{Width: m.width, Height: m.height})
+	}
+	return m
+}
+
+// indexOfRow finds a row by identity, since the view shares its row slices
+// with allData
+func indexOfRow(rows [][]db.Cell, row []db.Cell) int {
+	if len(row) == 0 {
+		return -1
+	}
+	for i, r := range rows {
+		if len(r) > 0 && &r[0] == &row[0] {
+			return i
+		}
+	}
+	return -1
+}
@@ -28,12 +28,9 @@ func (m Model) saveQuery() (tea.Model, tea.Cmd) {
 	// Check if this is a named query that should be overwritten
 	if m.isNamedQuery() {
 		// Overwrite the existing query
-		queryToSave := db.Query{
-			Name: m.currentQuery.Name,
-
-			SQL: sqlToSave,
-			Id:  m.currentQuery.Id,
-		}
+		queryToSave := m.currentQuery
+		queryToSave.SQL = sqlToSave
+		queryToSave.Metadata = m.queryMetadata()
 
 		if m.saveQueryCallback != nil {
 			savedQuery, err := m.saveQueryCallback(queryToSave)
@@ -96,9 +93,10 @@ func (m Model) saveQuery() (tea.Model, tea.Cmd) {
 
 		// Save with the new name
 		queryToSave := db.Query{
-			Name: name,
-			SQL:  sqlToSave,
-			Id:   -1, // New query
+			Name:     name,
+			SQL:      sqlToSave,
+			Id:       -1, // New query
+			Metadata: m.queryMetadata(),
 		}
 
 		var savedQuery db.Query
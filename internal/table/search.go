@@ -0,0 +1,245 @@
+package table
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/eduardofuncao/squix/internal/styles"
+)
+
+type promptKind int
+
+const (
+	promptNone promptKind = iota
+	promptSearch
+	promptFilter
+	promptCommand
+)
+
+// searchOrigin remembers where the cursor was when a search prompt opened,
+// so Esc can put it back after incremental jumps
+type searchOrigin struct {
+	row, col         int
+	offsetY, offsetX int
+	search           *regexp.Regexp
+}
+
+func (m Model) startSearch() Model {
+	m.prompt = promptSearch
+	m.promptInput = ""
+	m.searchOrigin = searchOrigin{
+		row:     m.selectedRow,
+		col:     m.selectedCol,
+		offsetY: m.offsetY,
+		offsetX: m.offsetX,
+		search:  m.searchRe,
+	}
+	return m
+}
+
+func (m Model) handlePromptKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.Type {
+	case tea.KeyCtrlC:
+		m.prompt = promptNone
+		return m.quit()
+	case tea.KeyEsc:
+		if m.prompt == promptSearch {
+			m = m.restoreSearchOrigin()
+		}
+		m.prompt = promptNone
+		return m, nil
+	case tea.KeyEnter:
+		kind := m.prompt
+		m.prompt = promptNone
+		switch kind {
+		case promptFilter:
+			return m.applyFilterInput(m.promptInput)
+		case promptCommand:
+			return m.runCommand(m.promptInput)
+		}
+		return m.commitSearch()
+	case tea.KeyBackspace:
+		if m.promptInput == "" {
+			if m.prompt == promptSearch {
+				m = m.restoreSearchOrigin()
+			}
+			m.prompt = promptNone
+			return m, nil
+		}
+		runes := []rune(m.promptInput)
+		m.promptInput = string(runes[:len(runes)-1])
+	case tea.KeyRunes, tea.KeySpace:
+		m.promptInput += string(msg.Runes)
+	default:
+		return m, nil
+	}
+
+	if m.prompt == promptSearch {
+		m = m.incrementalSearch()
+	}
+	return m, nil
+}
+
+// incrementalSearch jumps to the first match at or after the position the
+// search started from while the pattern is being typed
+func (m Model) incrementalSearch() Model {
+	origin := m.searchOrigin
+	m.selectedRow, m.selectedCol = origin.row, origin.col
+	m.offsetY, m.offsetX = origin.offsetY, origin.offsetX
+
+	if m.promptInput == "" {
+		m.searchRe = origin.search
+		return m
+	}
+
+	m.searchRe = compileSearch(m.promptInput)
+	if row, col, ok := m.findMatch(origin.row, origin.col, true, true); ok {
+		m = m.scrollTo(row, col)
+	}
+	return m
+}
+
+func (m Model) commitSearch() (Model, tea.Cmd) {
+	if m.promptInput == "" {
+		m.searchRe = m.searchOrigin.search
+		return m, nil
+	}
+
+	m.searchRe = compileSearch(m.promptInput)
+	if _, _, ok := m.findMatch(m.searchOrigin.row, m.searchOrigin.col, true, true); !ok {
+		m.statusMessage = styles.Error.Render("✗ Pattern not found: " + m.promptInput)
+	}
+	return m, nil
+}
+
+func (m Model) restoreSearchOrigin() Model {
+	origin := m.searchOrigin
+	m.selectedRow, m.selectedCol = origin.row, origin.col
+	m.offsetY, m.offsetX = origin.offsetY, origin.offsetX
+	m.searchRe = origin.search
+	return m
+}
+
+// nextMatch moves to the next (n) or previous (N) matching cell, wrapping
+// around the loaded rows like vim does
+func (m Model) nextMatch(forward bool) (Model, tea.Cmd) {
+	if m.searchRe == nil {
+		m.statusMessage = styles.Faint.Render("No previous search, press / to search")
+		return m, nil
+	}
+
+	row, col, ok := m.findMatch(m.selectedRow, m.selectedCol, forward, false)
+	if !ok {
+		m.statusMessage = styles.Error.Render("✗ Pattern not found: " + m.searchRe.String())
+		return m, nil
+	}
+
+	m.statusMessage = ""
+	if forward && (row < m.selectedRow || row == m.selectedRow && col <= m.selectedCol) {
+		m.statusMessage = styles.Faint.Render("search hit BOTTOM, continuing at TOP")
+	}
+	if !forward && (row > m.selectedRow || row == m.selectedRow && col >= m.selectedCol) {
+		m.statusMessage = styles.Faint.Render("search hit TOP, continuing at BOTTOM")
+	}
+
+	return m.scrollTo(row, col).loadMoreIfNeeded()
+}
+
+// findMatch scans cells row by row starting next to (row, col), or at it
+// when inclusive is set, and wraps around once
+func (m Model) findMatch(row, col int, forward, inclusive bool) (int, int, bool) {
+	cols := m.numCols()
+	total := m.numRows() * cols
+	if total == 0 || m.searchRe == nil {
+		return 0, 0, false
+	}
+
+	start := row*cols + col
+	step := 1
+	if !forward {
+		step = -1
+	}
+	first := 1
+	if inclusive {
+		first = 0
+	}
+
+	for i := first; i <= total; i++ {
+		pos := ((start+i*step)%total + total) % total
+		r, c := pos/cols, pos%cols
+		if m.searchRe.MatchString(m.data[r][c].String()) {
+			return r, c, true
+		}
+	}
+	return 0, 0, false
+}
+
+func (m Model) clearSearch() Model {
+	m.searchRe = nil
+	return m
+}
+
+// scrollTo selects a cell and scrolls just enough to bring it on screen
+func (m Model) scrollTo(row, col int) Model {
+	m.selectedRow = row
+	m.selectedCol = col
+
+	if row < m.offsetY {
+		m.offsetY = row
+	}
+	if row >= m.offsetY+m.visibleRows {
+		m.offsetY = row - m.visibleRows + 1
+	}
+	return m.ensureColVisible()
+}
+
+// compileSearch treats the pattern as a regex, or literally when it isn't a
+// valid one. Like vim's smartcase, it only matches case when the pattern has
+// an uppercase letter
+func compileSearch(pattern string) *regexp.Regexp {
+	flags := "(?i)"
+	if strings.IndexFunc(pattern, unicode.IsUpper) >= 0 {
+		flags = ""
+	}
+
+	re, err := regexp.Compile(flags + pattern)
+	if err != nil {
+		re = regexp.MustCompile(flags + regexp.QuoteMeta(pattern))
+	}
+	return re
+}
+
+// highlightMatches renders a formatted cell with every search match in the
+// SearchMatch style
+func (m Model) highlightMatches(content string, base func(...string) string) string {
+	locs := m.searchRe.FindAllStringIndex(content, -1)
+	if len(locs) == 0 {
+		return base(content)
+	}
+
+	var b strings.Builder
+	last := 0
+	for _, loc := range locs {
+		if loc[0] == loc[1] {
+			continue
+		}
+		b.WriteString(base(content[last:loc[0]]))
+		b.WriteString(styles.SearchMatch.Render(content[loc[0]:loc[1]]))
+		last = loc[1]
+	}
+	b.WriteString(base(content[last:]))
+	return b.String()
+}
+
+func (m Model) renderPrompt() string {
+	label := "/"
+	switch m.prompt {
+	case promptFilter:
+		label = "filter " + m.filterScope(m.filterCol) + ": "
+	case promptCommand:
+		label = ":"
+	}
+	return styles.Title.Render(label) + m.promptInput + "▏"
+}
@@ -0,0 +1,185 @@
+package table
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/eduardofuncao/squix/internal/db"
+	"github.com/eduardofuncao/squix/internal/styles"
+)
+
+type sortOrder int
+
+const (
+	sortNone sortOrder = iota
+	sortAsc
+	sortDesc
+)
+
+// sortKind is how the cells of a column compare
+type sortKind int
+
+const (
+	sortByText sortKind = iota
+	sortByNumber
+	sortByTime
+	// Unknown types compare as numbers when both cells are numbers
+	sortByGuess
+)
+
+// sortKindOf classifies a column by its database type name
+func sortKindOf(columnType string) sortKind {
+	upper := strings.ToUpper(columnType)
+	containsAny := func(parts ...string) bool {
+		for _, part := range parts {
+			if strings.Contains(upper, part) {
+				return true
+			}
+		}
+		return false
+	}
+
+	switch {
+	case upper == "":
+		return sortByGuess
+	case containsAny("CHAR", "TEXT", "STRING", "CLOB"):
+		return sortByText
+	case containsAny("INT", "SERIAL", "DECIMAL", "NUMERIC", "FLOAT", "DOUBLE", "REAL", "NUMBER", "MONEY"):
+		return sortByNumber
+	case containsAny("DATE", "TIME"):
+		return sortByTime
+	case containsAny("BOOL", "BIT", "BLOB", "BINARY", "BYTEA", "RAW", "IMAGE", "JSON", "UUID", "GUID",
+		"ARRAY", "[]", "ENUM", "SET", "XML", "GEOMETRY", "POINT", "POLYGON", "LINE"):
+		return sortByText
+	}
+	return sortByGuess
+}
+
+// Layouts tried when sorting date and time columns, %v of a time.Time first
+var sortTimeLayouts = []string{
+	"2006-01-02 15:04:05.999999999 -0700 MST",
+	time.RFC3339Nano,
+	"2006-01-02 15:04:05.999999999",
+	"2006-01-02T15:04:05.999999999",
+	"2006-01-02",
+	"15:04:05.999999999",
+}
+
+func (m Model) sortActive() bool {
+	return m.sortOrder != sortNone
+}
+
+// cycleSort sorts the loaded rows by the selected column, cycling through
+// ascending, descending and the original order
+func (m Model) cycleSort() (Model, tea.Cmd) {
+	if m.numCols() == 0 {
+		return m, nil
+	}
+
+	if m.sortCol != m.selectedCol {
+		m.sortCol = m.selectedCol
+		m.sortOrder = sortAsc
+	} else {
+		m.sortOrder = (m.sortOrder + 1) % 3
+	}
+
+	m = m.rebuildRows()
+	m.visualMode = false
+	m.selectedRow = 0
+	m.offsetY = 0
+
+	// Sorting only reorders what was fetched so far
+	if m.sortActive() && m.hasMoreRows() {
+		m.statusMessage = styles.Faint.Render(fmt.Sprintf(
+			"Sorted the %d loaded rows, press O to re-run the query with ORDER BY", m.loadedRows()))
+	}
+	return m, nil
+}
+
+// rerunSorted pushes the current sort down to the database as an ORDER BY
+// and re-runs the query, so rows that were not fetched yet are sorted too
+func (m Model) rerunSorted() (Model, tea.Cmd) {
+	if !m.sortActive() {
+		m.statusMessage = styles.Faint.Render("Press S on a column to choose a sort first")
+		return m, nil
+	}
+	if m.isTablesList {
+		return m, nil
+	}
+
+	m.editedQuery = db.ApplyOrderBy(m.currentQuery.SQL, m.sortCol+1, m.sortOrder == sortDesc)
+	m.shouldRerunQuery = true
+	return m.quit()
+}
+
+func (m Model) sortRows(rows [][]db.Cell) {
+	col := m.sortCol
+	kind := sortByGuess
+	if col < len(m.columnTypes) {
+		kind = sortKindOf(m.columnTypes[col])
+	}
+	desc := m.sortOrder == sortDesc
+
+	sort.SliceStable(rows, func(i, j int) bool {
+		a, b := rows[i][col], rows[j][col]
+		// NULLs go last whatever the direction
+		if a.Null || b.Null {
+			return !a.Null && b.Null
+		}
+		cmp := compareCells(a.Text, b.Text, kind)
+		if desc {
+			return cmp > 0
+		}
+		return cmp < 0
+	})
+}
+
+// compareCells compares two cells as numbers or times when the column type
+// says so, or when both look like numbers, and as text otherwise
+func compareCells(a, b string, kind sortKind) int {
+	switch kind {
+	case sortByTime:
+		ta, errA := parseSortTime(a)
+		tb, errB := parseSortTime(b)
+		if errA == nil && errB == nil {
+			return ta.Compare(tb)
+		}
+	case sortByNumber, sortByGuess:
+		fa, errA := strconv.ParseFloat(strings.TrimSpace(a), 64)
+		fb, errB := strconv.ParseFloat(strings.TrimSpace(b), 64)
+		if errA == nil && errB == nil {
+			switch {
+			case fa < fb:
+				return -1
+			case fa > fb:
+				return 1
+			}
+			return 0
+		}
+	}
+	return strings.Compare(strings.ToLower(a), strings.ToLower(b))
+}
+
+func parseSortTime(value string) (time.Time, error) {
+	for _, layout := range sortTimeLayouts {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("not a time: %s", value)
+}
+
+// sortIndicator marks the sorted column in the header
+func (m Model) sortIndicator(col int) string {
+	if !m.sortActive() || col != m.sortCol {
+		return ""
+	}
+	if m.sortOrder == sortDesc {
+		return "▼ "
+	}
+	return "▲ "
+}
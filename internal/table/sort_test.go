@@ -0,0 +1,48 @@
+package table
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/eduardofuncao/squix/internal/db"
+)
+
+func TestSortRows(t *testing.T) {
+	tests := []struct {
+		name       string
+		columnType string
+		order      sortOrder
+		values     []string
+		want       []string
+	}{
+		{name: "Integers", columnType: "INT4", order: sortAsc, values: []string{"10", "9", "", "100"}, want: []string{"9", "10", "100", "NULL"}},
+		{name: "Decimals descending", columnType: "NUMERIC(10,2)", order: sortDesc, values: []string{"2.5", "", "10.25"}, want: []string{"10.25", "2.5", "NULL"}},
+		{name: "Text that looks like numbers", columnType: "VARCHAR(20)", order: sortAsc, values: []string{"10", "9", "100"}, want: []string{"10", "100", "9"}},
+		{name: "Dates", columnType: "DATE", order: sortAsc, values: []string{"2024-03-01", "2023-12-31"}, want: []string{"2023-12-31", "2024-03-01"}},
+		{name: "Unknown type guesses numbers", columnType: "", order: sortAsc, values: []string{"10", "9"}, want: []string{"9", "10"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// An empty value stands for NULL
+			rows := make([][]db.Cell, len(tt.values))
+			for i, v := range tt.values {
+				rows[i] = []db.Cell{db.TextCell(v)}
+				if v == "" {
+					rows[i][0] = db.NullCell
+				}
+			}
+
+			m := Model{columnTypes: []string{tt.columnType}, sortOrder: tt.order}
+			m.sortRows(rows)
+
+			var got []string
+			for _, row := range rows {
+				got = append(got, row[0].String())
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("sortRows(%s) = %v, want %v", tt.columnType, got, tt.want)
+			}
+		})
+	}
+}
@@ -11,10 +11,12 @@ import (
 func Render(
 	columns []string,
 	columnTypes []string,
-	data [][]string,
+	data [][]db.Cell,
+	source RowSource,
 	elapsed time.Duration,
 	conn db.DatabaseConnection,
-	tableName, primaryKeyCol string,
+	tableName string,
+	primaryKeyCols []string,
 	query db.Query,
 	columnWidth int,
 	visibility config.UIVisibility,
@@ -28,12 +30,13 @@ func Render(
 		elapsed,
 		conn,
 		tableName,
-		primaryKeyCol,
+		primaryKeyCols,
 		query,
 		columnWidth,
 		visibility,
 	)
 	model.saveQueryCallback = saveCallback
+	model.rowSource = source
 	if len(initialStatus) > 0 && initialStatus[0] != "" {
 		model.statusMessage = initialStatus[0]
 	}
@@ -47,14 +50,14 @@ func Render(
 
 func RenderTablesList(
 	columns []string,
-	data [][]string,
+	data [][]db.Cell,
 	elapsed time.Duration,
 	conn db.DatabaseConnection,
 	query db.Query,
 	columnWidth int,
 	visibility config.UIVisibility,
 ) (Model, error) {
-	model := New(columns, nil, data, elapsed, conn, "", "", query, columnWidth, visibility)
+	model := New(columns, nil, data, elapsed, conn, "", nil, query, columnWidth, visibility)
 	model.isTablesList = true
 	p := tea.NewProgram(model)
 	finalModel, err := p.Run()
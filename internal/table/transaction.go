@@ -0,0 +1,304 @@
+package table
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/eduardofuncao/squix/internal/db"
+	"github.com/eduardofuncao/squix/internal/styles"
+	"github.com/eduardofuncao/squix/internal/undo"
+)
+
+// Updates and deletes made in the table view run in a transaction opened by
+// the first of them. They stay pending until :commit or :rollback, and
+// quitting with pending changes asks which one it should be. Databases
+// without transactions apply every change right away
+
+// pendingChange is an edit waiting for the transaction to end, with what it
+// takes to put the loaded rows back if it is rolled back
+type pendingChange struct {
+	journal  undo.Entry // Rows as they were before the change
+	row      []db.Cell  // The edited row, shared with data
+	col      int
+	old      db.Cell // Cell value before an update
+	deleted  bool
+	inserted bool
+	index    int // Position of a deleted row among the loaded rows, in query order
+}
+
+// beginEdit starts the edit transaction unless it is open already or the
+// database has none
+func (m Model) beginEdit() (Model, error) {
+	m.releaseRowSource()
+	if m.tx != nil || m.autocommit {
+		return m, nil
+	}
+
+	tx, err := m.dbConnection.BeginTx(context.Background())
+	switch {
+	case errors.Is(err, db.ErrNoTransactions):
+		m.autocommit = true
+	case err != nil:
+		return m, fmt.Errorf("could not start a transaction: %w", err)
+	default:
+		m.tx = tx
+	}
+	return m, nil
+}
+
+// execEdit runs an UPDATE or DELETE in the edit transaction, starting it if
+// needed
+func (m Model) execEdit(statement string, args ...any) (Model, error) {
+	m, err := m.beginEdit()
+	if err != nil {
+		return m, err
+	}
+	ctx := context.Background()
+
+	if m.tx == nil {
+		return m, m.dbConnection.Exec(ctx, statement, args...)
+	}
+
+	// A failed statement aborts the whole transaction on PostgreSQL, the
+	// savepoint keeps the changes made before it
+	savepoint := m.dbConnection.GetDbType() == "postgres"
+	if savepoint {
+		if _, err := m.tx.ExecContext(ctx, "SAVEPOINT squix_edit"); err != nil {
+			return m, err
+		}
+	}
+	if _, err := m.tx.ExecContext(ctx, statement, args...); err != nil {
+		if savepoint {
+			m.tx.ExecContext(ctx, "ROLLBACK TO SAVEPOINT squix_edit")
+		}
+		return m, err
+	}
+	return m, nil
+}
+
+// queryRows reads inside the edit transaction when one is open, so reads see
+// the pending changes and don't wait on the rows they lock
+func (m Model) queryRows(ctx context.Context, query string, args ...any) (*sql.Rows, error) {
+	if m.tx != nil {
+		return m.tx.QueryContext(ctx, query, args...)
+	}
+	return m.dbConnection.ExecQuery(ctx, query, args...)
+}
+
+// columnTypesByName maps the lowercase column names of the result set to
+// their database type
+func (m Model) columnTypesByName() map[string]string {
+	columnTypes := map[string]string{}
+	for i, col := range m.columns {
+		if i < len(m.columnTypes) {
+			columnTypes[strings.ToLower(col)] = m.columnTypes[i]
+		}
+	}
+	return columnTypes
+}
+
+// bindEdit turns the literals of a statement into arguments typed after the
+// columns of the result set
+func (m Model) bindEdit(statement string) (string, []any) {
+	statement, args := db.BindLiterals(statement, m.columnTypesByName(), m.dbConnection.GetPlaceholder)
+	return statement, db.DriverArgs(args, m.dbConnection.GetDbType())
+}
+
+// addPending records an edit that ran in the transaction. Without one there
+// is nothing to commit, the change goes to the undo journal and the status
+// says it is already applied
+func (m Model) addPending(change pendingChange) Model {
+	if m.tx == nil {
+		if m.autocommit {
+			warning := journal([]pendingChange{change})
+			m.statusMessage = styles.Faint.Render(fmt.Sprintf("Applied right away, %s has no transactions%s", m.dbConnection.GetDbType(), warning))
+		}
+		return m
+	}
+	m.pending = append(m.pending, change)
+	return m
+}
+
+func (m Model) startCommand() Model {
+	m.prompt = promptCommand
+	m.promptInput = ""
+	return m
+}
+
+// runCommand runs a command typed after :, any prefix of its name works
+func (m Model) runCommand(input string) (Model, tea.Cmd) {
+	input = strings.TrimSpace(input)
+	switch {
+	case input == "":
+		return m, nil
+	case strings.HasPrefix("commit", input):
+		m, _ = m.commitEdits()
+		return m, nil
+	case strings.HasPrefix("rollback", input):
+		return m.rollbackEdits(), nil
+	}
+	m.statusMessage = styles.Error.Render(fmt.Sprintf("✗ Unknown command: %s (use :commit or :rollback)", input))
+	return m, nil
+}
+
+// commitEdits commits the pending changes, reporting whether it worked
+func (m Model) commitEdits() (Model, bool) {
+	if m.tx == nil {
+		m.statusMessage = styles.Faint.Render("No pending changes")
+		return m, true
+	}
+
+	count := len(m.pending)
+	err := m.tx.Commit()
+	m.tx = nil
+	if err != nil {
+		m = m.revertPending()
+		m.statusMessage = styles.Error.Render(fmt.Sprintf("✗ Commit failed, changes rolled back: %v", err))
+		return m, false
+	}
+
+	warning := journal(m.pending)
+	m.pending = nil
+	m.statusMessage = styles.Success.Render(fmt.Sprintf("✓ Committed %s%s", pluralChanges(count), warning))
+	return m, true
+}
+
+func (m Model) rollbackEdits() Model {
+	if m.tx == nil {
+		m.statusMessage = styles.Faint.Render("No pending changes")
+		return m
+	}
+
+	count := len(m.pending)
+	err := m.tx.Rollback()
+	m.tx = nil
+	m = m.revertPending()
+	if err != nil {
+		m.statusMessage = styles.Error.Render(fmt.Sprintf("✗ Rollback failed: %v", err))
+		return m
+	}
+	m.statusMessage = styles.Success.Render(fmt.Sprintf("✓ Rolled back %s", pluralChanges(count)))
+	return m
+}
+
+// revertPending puts the loaded rows back as they were before the pending
+// changes, newest first so the positions of deleted rows still hold
+func (m Model) revertPending() Model {
+	for i := len(m.pending) - 1; i >= 0; i-- {
+		m = m.restoreRow(m.pending[i])
+	}
+	if m.allData != nil {
+		m = m.rebuildRows()
+	}
+	m.pending = nil
+	return m.clampSelection().refreshLayout()
+}
+
+// clampSelection keeps the selection on a row after inserted rows went away
+func (m Model) clampSelection() Model {
+	if m.selectedRow >= m.numRows() {
+		m.selectedRow = max(m.numRows()-1, 0)
+	}
+	if m.offsetY >= m.numRows() {
+		m.offsetY = max(m.numRows()-1, 0)
+	}
+	return m
+}
+
+// restoreRow puts back the loaded row a change edited or deleted, and takes
+// out the one it inserted
+func (m Model) restoreRow(change pendingChange) Model {
+	if change.inserted {
+		if i := indexOfRow(m.allData, change.row); i >= 0 {
+			m.allData = slices.Delete(m.allData, i, i+1)
+		}
+		if i := indexOfRow(m.data, change.row); i >= 0 {
+			m.data = slices.Delete(m.data, i, i+1)
+		}
+		return m
+	}
+	if change.row == nil {
+		return m
+	}
+	if !change.deleted {
+		change.row[change.col] = change.old
+		return m
+	}
+	if m.allData != nil {
+		m.allData = slices.Insert(m.allData, min(change.index, len(m.allData)), change.row)
+	} else {
+		m.data = slices.Insert(m.data, min(change.index, len(m.data)), change.row)
+	}
+	return m
+}
+
+// quit leaves the table view, first asking what to do with pending changes
+func (m Model) quit() (Model, tea.Cmd) {
+	if len(m.pending) > 0 {
+		m.confirmQuit = true
+		return m, nil
+	}
+	// A transaction whose only statement failed has nothing worth keeping
+	if m.tx != nil {
+		m.tx.Rollback()
+		m.tx = nil
+	}
+	return m, tea.Quit
+}
+
+func (m Model) handleQuitConfirmKey(msg tea.KeyMsg) (Model, tea.Cmd) {
+	switch msg.String() {
+	case "c", "y":
+		m.confirmQuit = false
+		var ok bool
+		m, ok = m.commitEdits()
+		if !ok {
+			// Stay to show why, the rows are back as they were
+			m.shouldRerunQuery = false
+			return m, nil
+		}
+		return m, tea.Quit
+	case "r", "n", "ctrl+c":
+		m.confirmQuit = false
+		m = m.rollbackEdits()
+		return m, tea.Quit
+	case "esc":
+		m.confirmQuit = false
+		m.shouldRerunQuery = false
+		return m, nil
+	}
+	return m, nil
+}
+
+func (m Model) renderQuitConfirm() string {
+	return styles.Title.Render(fmt.Sprintf("%s pending: ", pluralChanges(len(m.pending)))) +
+		fmt.Sprintf("%sommit  %sollback  %s keep editing",
+			styles.TableHeader.Render("[c]"),
+			styles.TableHeader.Render("[r]"),
+			styles.TableHeader.Render("[esc]"),
+		)
+}
+
+// pendingLabel tells how edits are applied, for the footer
+func (m Model) pendingLabel() string {
+	switch {
+	case len(m.pending) > 0:
+		return styles.Error.Render(pluralChanges(len(m.pending))+" pending") +
+			styles.Faint.Render(" :commit / :rollback")
+	case m.autocommit:
+		return styles.Faint.Render("autocommit, no transactions")
+	}
+	return ""
+}
+
+func pluralChanges(n int) string {
+	if n == 1 {
+		return "1 change"
+	}
+	return fmt.Sprintf("%d changes", n)
+}
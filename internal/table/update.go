@@ -2,6 +2,7 @@ package table
 
 import (
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/eduardofuncao/squix/internal/undo"
 )
 
 func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
@@ -21,12 +22,20 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return m.handleEditorComplete(msg)
 	case deleteCompleteMsg:
 		return m.handleDeleteComplete(msg)
+	case insertCompleteMsg:
+		return m.handleInsertComplete(msg)
+	case bulkEditCompleteMsg:
+		return m.handleBulkEditComplete(msg)
+	case undoCompleteMsg:
+		return m.handleUndoComplete(msg)
 	case queryEditCompleteMsg:
 		return m.handleQueryEditComplete(msg)
 	case detailViewEditCompleteMsg:
 		return m.handleDetailViewEditComplete(msg)
 	case saveQueryCompleteMsg:
 		return m.handleSaveQueryComplete(msg)
+	case rowsLoadedMsg:
+		return m.handleRowsLoaded(msg)
 	case tea.WindowSizeMsg:
 		return m.handleWindowResize(msg), nil
 	}
@@ -40,6 +49,14 @@ func (m Model) handleKeyPress(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		return m.executeExportForFormat(msg.String())
 	}
 
+	if m.confirmQuit {
+		return m.handleQuitConfirmKey(msg)
+	}
+
+	if m.prompt != promptNone {
+		return m.handlePromptKey(msg)
+	}
+
 	// If in detailed view mode, handle specific keys
 	if m.detailViewMode {
 		switch msg.String() {
@@ -50,7 +67,7 @@ func (m Model) handleKeyPress(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			return m.closeDetailView(), nil
 		case "e":
 			// Edit the cell content
-			if m.tableName != "" && m.primaryKeyCol != "" {
+			if m.tableName != "" && m.hasPrimaryKey() {
 				return m.editFromDetailView()
 			}
 			return m, nil
@@ -61,15 +78,28 @@ func (m Model) handleKeyPress(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		case "down", "j":
 			return m.scrollDetailViewDown(), nil
 		case "ctrl+c":
-			return m, tea.Quit
+			return m.quit()
 		}
 		return m, nil
 	}
 
+	// Row actions need a row, and a filter can leave none
+	if m.numRows() == 0 {
+		switch msg.String() {
+		case "y", "u", "D":
+			return m, nil
+		}
+	}
+
 	// Normal table navigation
 	switch msg.String() {
 	case "ctrl+c", "q":
-		return m, tea.Quit
+		return m.quit()
+	case "esc":
+		if m.filterActive() {
+			return m.clearFilter(), nil
+		}
+		return m.clearSearch(), nil
 	case "?":
 		m.uiVisibility.FooterKeymaps = !m.uiVisibility.FooterKeymaps
 		return m, nil
@@ -77,7 +107,7 @@ func (m Model) handleKeyPress(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	case "up", "k":
 		return m.moveUp(), nil
 	case "down", "j":
-		return m.moveDown(), nil
+		return m.moveDown().loadMoreIfNeeded()
 	case "left", "h":
 		return m.moveLeft(), nil
 	case "right", "l":
@@ -90,16 +120,40 @@ func (m Model) handleKeyPress(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	case "g":
 		return m.jumpToFirstRow(), nil
 	case "G":
-		return m.jumpToLastRow(), nil
+		return m.jumpToLastRow().loadMoreIfNeeded()
 
 	case "pgup", "ctrl+u":
 		return m.pageUp(), nil
 	case "pgdown", "ctrl+d":
-		return m.pageDown(), nil
+		return m.pageDown().loadMoreIfNeeded()
 
 	case "v":
 		return m.toggleVisualMode()
 
+	case "/":
+		return m.startSearch(), nil
+	case ":":
+		return m.startCommand(), nil
+	case "n":
+		return m.nextMatch(true)
+	case "N":
+		return m.nextMatch(false)
+	case "f":
+		return m.startFilter(m.selectedCol), nil
+	case "F":
+		return m.startFilter(allColumns), nil
+	case "S":
+		return m.cycleSort()
+	case "O":
+		return m.rerunSorted()
+
+	case "+":
+		return m.resizeColumn(2)
+	case "-":
+		return m.resizeColumn(-2)
+	case "=":
+		return m.autoFitColumn()
+
 	case "y":
 		return m.copySelection()
 	case "x":
@@ -110,7 +164,7 @@ func (m Model) handleKeyPress(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		if m.isTablesList {
 			if m.selectedRow >= 0 && m.selectedRow < m.numRows() {
 				// Get table name from the first column (should be "name")
-				m.selectedTableName = m.data[m.selectedRow][0]
+				m.selectedTableName = m.data[m.selectedRow][0].Text
 				return m, tea.Quit
 			}
 		}
@@ -118,9 +172,21 @@ func (m Model) handleKeyPress(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		return m.showDetailView(), nil
 
 	case "u":
+		if m.visualMode {
+			return m.bulkEdit(undo.ActionUpdate)
+		}
 		return m.updateCell()
 	case "D":
+		if m.visualMode {
+			return m.bulkEdit(undo.ActionDelete)
+		}
 		return m.deleteRow()
+	case "i":
+		return m.insertRow(false)
+	case "o":
+		return m.insertRow(true)
+	case "U":
+		return m.undoChange()
 	case "e":
 		return m.editAndRerunQuery()
 	case "s":
@@ -134,10 +200,7 @@ func (m Model) handleWindowResize(msg tea.WindowSizeMsg) Model {
 	m.width = msg.Width
 	m.height = msg.Height
 
-	m.visibleCols = (m.width - 2) / (m.cellWidth + 1)
-	if m.visibleCols > m.numCols() {
-		m.visibleCols = m.numCols()
-	}
+	m = m.ensureColVisible()
 
 	// Calculate dynamic header height
 	headerLines := m.calculateHeaderLines()
@@ -1,14 +1,17 @@
 package table
 
 import (
+	"context"
 	"fmt"
-	"os"
 	"regexp"
+	"slices"
 	"strings"
 	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/eduardofuncao/squix/internal/db"
 	"github.com/eduardofuncao/squix/internal/styles"
+	"github.com/eduardofuncao/squix/internal/undo"
 )
 
 func (m Model) updateCell() (tea.Model, tea.Cmd) {
@@ -20,62 +23,13 @@ func (m Model) updateCell() (tea.Model, tea.Cmd) {
 	}
 
 	updateStmt := m.buildUpdateStatement()
-	editorCmd := os.Getenv("EDITOR")
-	if editorCmd == "" {
-		editorCmd = "vim"
-	}
-
-	tmpFile, err := os.CreateTemp("", "squix-update-*.sql")
-	if err != nil {
-		return m, nil
-	}
-	tmpPath := tmpFile.Name()
-
-	if _, err := tmpFile.Write([]byte(updateStmt)); err != nil {
-		tmpFile.Close()
-		os.Remove(tmpPath)
-		return m, nil
-	}
-	tmpFile.Close()
-
-	// Get file modification time before editor
-	beforeModTime, err := os.Stat(tmpPath)
-	if err != nil {
-		os.Remove(tmpPath)
-		return m, nil
-	}
-
-	cmd := buildEditorCommand(editorCmd, tmpPath, updateStmt, CursorAtUpdateValue)
-
-	return m, tea.ExecProcess(cmd, func(err error) tea.Msg {
-		// Check if file was modified
-		afterModTime, statErr := os.Stat(tmpPath)
-		if statErr != nil {
-			os.Remove(tmpPath)
-			return nil
-		}
+	colIndex := m.selectedCol
 
-		// If file wasn't modified, user cancelled (exited without saving)
-		if afterModTime.ModTime().Equal(beforeModTime.ModTime()) || afterModTime.ModTime().Before(beforeModTime.ModTime()) {
-			os.Remove(tmpPath)
-			// Return a message that will show "cancelled" status
-			return editorCompleteMsg{
-				sql:       "",
-				colIndex:  m.selectedCol,
-				cancelled: true,
-			}
-		}
-
-		editedSQL, readErr := os.ReadFile(tmpPath)
-		os.Remove(tmpPath)
-
-		if err != nil || readErr != nil {
-			return nil
-		}
+	return m.editInEditor("squix-update-*.sql", updateStmt, CursorAtUpdateValue, func(sql string, cancelled bool) tea.Msg {
 		return editorCompleteMsg{
-			sql:       string(editedSQL),
-			colIndex:  m.selectedCol,
-			cancelled: false,
+			sql:       sql,
+			colIndex:  colIndex,
+			cancelled: cancelled,
 		}
 	})
 }
@@ -95,8 +49,8 @@ func (m Model) handleEditorComplete(msg editorCompleteMsg) (tea.Model, tea.Cmd)
 		})
 	}
 
-	if err := validateUpdateStatement(msg.sql); err != nil {
-		printError("Update validation failed:  %v", err)
+	if err := validateUpdateStatement(msg.sql, m.primaryKeyCols); err != nil {
+		m.statusMessage = styles.Error.Render(fmt.Sprintf("✗ Update validation failed: %v", err))
 		return m, nil
 	}
 
@@ -104,12 +58,14 @@ func (m Model) handleEditorComplete(msg editorCompleteMsg) (tea.Model, tea.Cmd)
 
 	m.lastExecutedQuery = m.cleanSQLForDisplay(msg.sql)
 
-	if err := m.executeUpdate(msg.sql); err != nil {
-		printError("Could not execute update: %v", err)
-		return m, nil
+	m, entry, err := m.executeUpdate(msg.sql)
+	if err != nil {
+		return m.editFailed("update", err)
 	}
 
-	m.data[m.selectedRow][msg.colIndex] = newValue
+	row := m.data[m.selectedRow]
+	m = m.addPending(pendingChange{journal: entry, row: row, col: msg.colIndex, old: row[msg.colIndex]})
+	row[msg.colIndex] = newValue
 
 	m.blinkUpdatedCell = true
 	m.updatedRow = m.selectedRow
@@ -131,91 +87,133 @@ func (m Model) buildUpdateStatement() string {
 	columnName := m.columns[m.selectedCol]
 	currentValue := m.data[m.selectedRow][m.selectedCol]
 
-	pkValue := ""
-	var multipleMatches bool
-
-	if m.primaryKeyCol != "" {
-		for i, col := range m.columns {
-			if col == m.primaryKeyCol {
-				pkValue = m.data[m.selectedRow][i]
-				break
-			}
-		}
-	}
-
-	// If PK not found in result set, try to fetch it
-	if m.primaryKeyCol != "" && pkValue == "" {
-		pkValue, multipleMatches = m.fetchPrimaryKeyValue()
-	}
+	pkValues, multipleMatches := m.primaryKeyValues()
 
 	stmt := m.dbConnection.BuildUpdateStatement(
 		m.tableName,
 		columnName,
 		currentValue,
-		m.primaryKeyCol,
-		pkValue,
+		m.primaryKeyCols,
+		pkValues,
+		m.primaryKeyTypes(),
 	)
 
-	if multipleMatches && pkValue != "" {
+	if multipleMatches && len(pkValues) > 0 {
 		stmt = fmt.Sprintf("-- Warning: Multiple rows matched the WHERE clause, using PK from first match\n%s", stmt)
 	}
 
 	return stmt
 }
 
-func (m Model) fetchPrimaryKeyValue() (string, bool) {
-	if m.primaryKeyCol == "" || m.tableName == "" {
-		return "", false
+// primaryKeyValues reads the key of the selected row, fetching it from the
+// table when a key column isn't in the result set
+func (m Model) primaryKeyValues() ([]string, bool) {
+	if !m.hasPrimaryKey() {
+		return nil, false
+	}
+
+	pkValues := make([]string, len(m.primaryKeyCols))
+	for k, pkCol := range m.primaryKeyCols {
+		i := slices.Index(m.columns, pkCol)
+		if i < 0 {
+			return m.fetchPrimaryKeyValues()
+		}
+		pkValues[k] = m.data[m.selectedRow][i].Text
+	}
+	return pkValues, false
+}
+
+// primaryKeyTypes lists the column type of every key column, read from the
+// table when a key column isn't in the result set
+func (m Model) primaryKeyTypes() []string {
+	columnTypes := m.columnTypesByName()
+	pkTypes := make([]string, len(m.primaryKeyCols))
+	var metadata *db.TableMetadata
+	for k, pkCol := range m.primaryKeyCols {
+		if t, ok := columnTypes[strings.ToLower(pkCol)]; ok && t != "" {
+			pkTypes[k] = t
+			continue
+		}
+		if metadata == nil {
+			metadata = &db.TableMetadata{}
+			if m.dbConnection != nil && m.tableName != "" {
+				if found, err := m.dbConnection.GetTableMetadata(m.tableName); err == nil && found != nil {
+					metadata = found
+				}
+			}
+		}
+		if i := slices.Index(metadata.Columns, pkCol); i >= 0 && i < len(metadata.ColumnTypes) {
+			pkTypes[k] = metadata.ColumnTypes[i]
+		}
+	}
+	return pkTypes
+}
+
+func (m Model) fetchPrimaryKeyValues() ([]string, bool) {
+	if !m.hasPrimaryKey() || m.tableName == "" {
+		return nil, false
 	}
 
 	// Build WHERE clause from all columns in current row
 	var whereConditions []string
 	for i, col := range m.columns {
 		val := m.data[m.selectedRow][i]
-		whereConditions = append(whereConditions, fmt.Sprintf("%s = '%s'", col, escapeSQLValue(val)))
+		if val.Null {
+			whereConditions = append(whereConditions, fmt.Sprintf("%s IS NULL", col))
+			continue
+		}
+		whereConditions = append(whereConditions, fmt.Sprintf("%s = '%s'", col, escapeSQLValue(val.Text)))
 	}
 
 	if len(whereConditions) == 0 {
-		return "", false
+		return nil, false
 	}
 
 	whereClause := strings.Join(whereConditions, " AND ")
 
-	// Query for PK value
-	query := fmt.Sprintf("SELECT %s FROM %s WHERE %s", m.primaryKeyCol, m.tableName, whereClause)
+	// Query for PK values
+	query, args := m.bindEdit(fmt.Sprintf("SELECT %s FROM %s WHERE %s", strings.Join(m.primaryKeyCols, ", "), m.tableName, whereClause))
 
-	rows, err := m.dbConnection.ExecQuery(query)
+	rows, err := m.queryRows(context.Background(), query, args...)
 	if err != nil {
-		return "", false
+		return nil, false
 	}
 	defer rows.Close()
 
-	// Collect all PK values to check for multiple matches
-	var pkValues []string
+	// Collect all keys to check for multiple matches
+	var keys [][]string
 	for rows.Next() {
-		var pkVal string
-		if err := rows.Scan(&pkVal); err != nil {
+		key := make([]string, len(m.primaryKeyCols))
+		dest := make([]any, len(key))
+		for i := range key {
+			dest[i] = &key[i]
+		}
+		if err := rows.Scan(dest...); err != nil {
 			continue
 		}
-		pkValues = append(pkValues, pkVal)
+		keys = append(keys, key)
 	}
 
-	if len(pkValues) == 0 {
-		return "", false
+	if len(keys) == 0 {
+		return nil, false
 	}
 
-	if len(pkValues) > 1 {
-		return pkValues[0], true
-	}
+	return keys[0], len(keys) > 1
+}
+
+func (m Model) hasPrimaryKey() bool {
+	return len(m.primaryKeyCols) > 0
+}
 
-	return pkValues[0], false
+func (m Model) isPrimaryKey(col string) bool {
+	return slices.ContainsFunc(m.primaryKeyCols, func(pk string) bool { return strings.EqualFold(pk, col) })
 }
 
 func escapeSQLValue(val string) string {
 	return strings.ReplaceAll(val, "'", "''")
 }
 
-func (m Model) executeUpdate(sql string) error {
+func (m Model) executeUpdate(sql string) (Model, undo.Entry, error) {
 	var result strings.Builder
 	for line := range strings.SplitSeq(sql, "\n") {
 		trimmed := strings.TrimSpace(line)
@@ -229,13 +227,23 @@ func (m Model) executeUpdate(sql string) error {
 	cleanSQL = strings.TrimSuffix(cleanSQL, ";")
 
 	if cleanSQL == "" {
-		return fmt.Errorf("no SQL to execute")
+		return m, undo.Entry{}, fmt.Errorf("no SQL to execute")
 	}
 
-	return m.dbConnection.Exec(cleanSQL)
+	return m.execJournaled(undo.ActionUpdate, cleanSQL)
+}
+
+// editFailed reports a statement that failed. With changes pending the table
+// view stays open so they can still be committed, otherwise squix exits
+func (m Model) editFailed(action string, err error) (Model, tea.Cmd) {
+	if len(m.pending) == 0 {
+		printError("Could not execute %s: %v", action, err)
+	}
+	m.statusMessage = styles.Error.Render(fmt.Sprintf("✗ Could not execute %s: %v", action, err))
+	return m, nil
 }
 
-func validateUpdateStatement(sql string) error {
+func validateUpdateStatement(sql string, pkColumns []string) error {
 	var result strings.Builder
 	for line := range strings.SplitSeq(sql, "\n") {
 		trimmed := strings.TrimSpace(line)
@@ -281,6 +289,27 @@ func validateUpdateStatement(sql string) error {
 		return fmt.Errorf("UPDATE statement must include a WHERE clause for safety")
 	}
 
+	return checkKeyColumns("UPDATE", cleanSQL, pkColumns)
+}
+
+// checkKeyColumns makes sure the WHERE clause still names every column of a
+// composite primary key, as part of the key can match more rows than meant
+func checkKeyColumns(verb, cleanSQL string, pkColumns []string) error {
+	match := whereClausePattern.FindStringSubmatch(cleanSQL)
+	if len(pkColumns) < 2 || match == nil {
+		return nil
+	}
+
+	var missing []string
+	for _, col := range pkColumns {
+		colRegex := regexp.MustCompile(`(?i)\b` + regexp.QuoteMeta(col) + `\b`)
+		if !colRegex.MatchString(match[1]) {
+			missing = append(missing, col)
+		}
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("%s statement must match on every primary key column, missing %s", verb, strings.Join(missing, ", "))
+	}
 	return nil
 }
 
@@ -298,7 +327,7 @@ func (m Model) cleanSQLForDisplay(sql string) string {
 	return cleanSQL
 }
 
-func (m Model) extractNewValue(sql string, columnName string) string {
+func (m Model) extractNewValue(sql string, columnName string) db.Cell {
 	var result strings.Builder
 	for line := range strings.SplitSeq(sql, "\n") {
 		trimmed := strings.TrimSpace(line)
@@ -316,11 +345,13 @@ func (m Model) extractNewValue(sql string, columnName string) string {
 	matches := setRe.FindStringSubmatch(cleanSQL)
 	if len(matches) > 0 {
 		if matches[2] != "" {
-			return matches[2]
+			return db.TextCell(matches[2])
 		} else if matches[3] != "" {
-			return matches[3]
+			return db.TextCell(matches[3])
+		} else if strings.EqualFold(matches[4], "NULL") {
+			return db.NullCell
 		} else if matches[4] != "" {
-			return matches[4]
+			return db.TextCell(matches[4])
 		}
 	}
 
@@ -332,13 +363,15 @@ func (m Model) extractNewValue(sql string, columnName string) string {
 	matches = updateRe.FindStringSubmatch(cleanSQL)
 	if len(matches) > 0 {
 		if matches[2] != "" {
-			return matches[2]
+			return db.TextCell(matches[2])
 		} else if matches[3] != "" {
-			return matches[3]
+			return db.TextCell(matches[3])
+		} else if strings.EqualFold(matches[4], "NULL") {
+			return db.NullCell
 		} else if matches[4] != "" {
-			return matches[4]
+			return db.TextCell(matches[4])
 		}
 	}
 
-	return "<unknown>"
+	return db.TextCell("<unknown>")
 }
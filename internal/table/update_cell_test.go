@@ -0,0 +1,86 @@
+package table
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/eduardofuncao/squix/internal/db"
+)
+
+func TestValidateUpdateStatementCompositeKey(t *testing.T) {
+	pkColumns := []string{"order_id", "line"}
+
+	tests := []struct {
+		name    string
+		sql     string
+		wantErr bool
+	}{
+		{
+			name:    "Every key column",
+			sql:     "UPDATE order_lines\nSET qty = '2'\nWHERE order_id = '7' AND line = '1';",
+			wantErr: false,
+		},
+		{
+			name:    "Quoted key columns",
+			sql:     "UPDATE order_lines SET qty = '2' WHERE [order_id] = '7' AND \"line\" = '1'",
+			wantErr: false,
+		},
+		{
+			name:    "Part of the key",
+			sql:     "UPDATE order_lines\nSET qty = '2'\nWHERE order_id = '7';",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateUpdateStatement(tt.sql, pkColumns)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("validateUpdateStatement(%q) error = %v, wantErr %v", tt.sql, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestInvalidEditKeepsTableOpen(t *testing.T) {
+	m := Model{columns: []string{"id", "name"}, primaryKeyCols: []string{"id"}}
+
+	// A statement without WHERE is refused in the status line, the table
+	// view and any pending changes stay as they were
+	model, _ := m.handleEditorComplete(editorCompleteMsg{sql: "UPDATE users SET name = 'x'", colIndex: 1})
+	if got := model.(Model).statusMessage; !strings.Contains(got, "WHERE") {
+		t.Errorf("update status = %q, want the validation error", got)
+	}
+
+	model, _ = m.handleDeleteComplete(deleteCompleteMsg{sql: "DELETE FROM users"})
+	if got := model.(Model).statusMessage; !strings.Contains(got, "WHERE") {
+		t.Errorf("delete status = %q, want the validation error", got)
+	}
+}
+
+func TestPartialKeyEditKeepsTableOpen(t *testing.T) {
+	m := Model{
+		columns:        []string{"order_id", "line", "qty"},
+		primaryKeyCols: []string{"order_id", "line"},
+		data:           [][]db.Cell{{db.TextCell("7"), db.TextCell("1"), db.TextCell("2")}},
+	}
+
+	model, _ := m.handleEditorComplete(editorCompleteMsg{
+		sql:      "UPDATE order_lines SET qty = '3' WHERE order_id = '7'",
+		colIndex: 2,
+	})
+	if got := model.(Model).statusMessage; !strings.Contains(got, "missing line") {
+		t.Errorf("update status = %q, want the missing key column", got)
+	}
+	if got := model.(Model).data[0][2].Text; got != "2" {
+		t.Errorf("refused update changed the cell to %q", got)
+	}
+
+	model, _ = m.handleDeleteComplete(deleteCompleteMsg{sql: "DELETE FROM order_lines WHERE order_id = '7'"})
+	if got := model.(Model).statusMessage; !strings.Contains(got, "missing line") {
+		t.Errorf("delete status = %q, want the missing key column", got)
+	}
+	if model.(Model).numRows() != 1 {
+		t.Error("refused delete removed the row")
+	}
+}
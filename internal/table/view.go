@@ -20,7 +20,7 @@ func (m Model) View() string {
 	}
 
 	// Don't render if we're about to rerun the query (prevents duplicate output)
-	if m.shouldRerunQuery {
+	if m.shouldRerunQuery && !m.confirmQuit {
 		return ""
 	}
 
@@ -28,7 +28,7 @@ func (m Model) View() string {
 
 	// Display query name header
 	if m.uiVisibility.QueryName {
-		b.WriteString(styles.Title.Render("◆ " + m.currentQuery.Name))
+		b.WriteString(m.renderQueryTitle())
 		b.WriteString("\n")
 	}
 
@@ -52,7 +52,7 @@ func (m Model) View() string {
 	separatorWidth := 0
 	endCol := min(m.offsetX+m.visibleCols, m.numCols())
 	for j := m.offsetX; j < endCol; j++ {
-		separatorWidth += m.cellWidth
+		separatorWidth += m.colWidth(j)
 		if j < endCol-1 {
 			separatorWidth += 1
 		}
@@ -70,7 +70,15 @@ func (m Model) View() string {
 		b.WriteString("\n")
 	}
 	if len(m.data) < 1 {
-		b.WriteString("Nothing to show here...")
+		if m.filterActive() {
+			b.WriteString("No rows match the filter...")
+		} else {
+			b.WriteString("Nothing to show here...")
+		}
+	}
+	if m.loadingMore {
+		b.WriteString(styles.Faint.Render("loading more…"))
+		b.WriteString("\n")
 	}
 
 	b.WriteString(m.renderFooter())
@@ -78,8 +86,12 @@ func (m Model) View() string {
 	// Always add a newline for status message area
 	b.WriteString("\n")
 
-	// Display status message if present
-	if m.statusMessage != "" {
+	// The search/filter prompt takes the status line while typing
+	if m.confirmQuit {
+		b.WriteString(m.renderQuitConfirm())
+	} else if m.prompt != promptNone {
+		b.WriteString(m.renderPrompt())
+	} else if m.statusMessage != "" {
 		b.WriteString(m.statusMessage)
 	}
 
@@ -91,28 +103,32 @@ func (m Model) renderHeader() string {
 	endCol := min(m.offsetX+m.visibleCols, m.numCols())
 
 	for j := m.offsetX; j < endCol; j++ {
-		typeIcon := ""
-		if m.uiVisibility.TypeDisplay && j < len(m.columnTypes) && m.columnTypes[j] != "" {
-			typeIcon = getTypeIcon(m.columnTypes[j]) + " "
-		}
+		columnDisplay := m.sortIndicator(j) + m.headerLabel(j)
+		content := formatCell(columnDisplay, m.colWidth(j))
+		cells = append(cells, styles.TableHeader.Render(content))
+	}
 
-		pkIcon := ""
-		if m.uiVisibility.KeyIcons && m.primaryKeyCol != "" && j < len(m.columns) &&
-			m.columns[j] == m.primaryKeyCol {
-			pkIcon = "⚿ "
-		}
+	return strings.Join(cells, styles.TableBorder.Render("│"))
+}
 
-		fkIcon := ""
-		if m.uiVisibility.KeyIcons && j < len(m.columnFKs) && m.columnFKs[j] != "" {
-			fkIcon = "⚭ "
-		}
+// headerLabel is the column name with its key and type icons
+func (m Model) headerLabel(j int) string {
+	typeIcon := ""
+	if m.uiVisibility.TypeDisplay && j < len(m.columnTypes) && m.columnTypes[j] != "" {
+		typeIcon = getTypeIcon(m.columnTypes[j]) + " "
+	}
 
-		columnDisplay := pkIcon + fkIcon + typeIcon + m.columns[j]
-		content := formatCell(columnDisplay, m.cellWidth)
-		cells = append(cells, styles.TableHeader.Render(content))
+	pkIcon := ""
+	if m.uiVisibility.KeyIcons && j < len(m.columns) && m.isPrimaryKey(m.columns[j]) {
+		pkIcon = "⚿ "
 	}
 
-	return strings.Join(cells, styles.TableBorder.Render("│"))
+	fkIcon := ""
+	if m.uiVisibility.KeyIcons && j < len(m.columnFKs) && m.columnFKs[j] != "" {
+		fkIcon = "⚭ "
+	}
+
+	return pkIcon + fkIcon + typeIcon + m.columns[j]
 }
 
 func (m Model) renderDataRow(rowIndex int) string {
@@ -120,8 +136,12 @@ func (m Model) renderDataRow(rowIndex int) string {
 	endCol := min(m.offsetX+m.visibleCols, m.numCols())
 
 	for j := m.offsetX; j < endCol; j++ {
-		content := formatCell(m.data[rowIndex][j], m.cellWidth)
+		content := formatCell(m.data[rowIndex][j].String(), m.colWidth(j))
 		style := m.getCellStyle(rowIndex, j)
+		if m.searchRe != nil && !m.isCellInSelection(rowIndex, j) {
+			cells = append(cells, m.highlightMatches(content, style.Render))
+			continue
+		}
 		cells = append(cells, style.Render(content))
 	}
 
@@ -157,7 +177,7 @@ func (m Model) renderFooter() string {
 
 		if m.selectedRow >= 0 && m.selectedRow < len(m.data) &&
 			m.selectedCol >= 0 && m.selectedCol < len(m.data[m.selectedRow]) {
-			currentCellValue = m.data[m.selectedRow][m.selectedCol]
+			currentCellValue = m.data[m.selectedRow][m.selectedCol].String()
 		}
 
 		if m.selectedCol >= 0 && m.selectedCol < len(m.columnTypes) {
@@ -170,7 +190,7 @@ func (m Model) renderFooter() string {
 
 		maxPreviewWidth := m.width - len(columnType) - len(fkRef) - 10
 		displayValue := currentCellValue
-		if len(displayValue) > maxPreviewWidth && maxPreviewWidth > 0 {
+		if len(displayValue) > maxPreviewWidth && maxPreviewWidth > 3 {
 			displayValue = displayValue[:maxPreviewWidth-3] + "..."
 		}
 
@@ -184,10 +204,14 @@ func (m Model) renderFooter() string {
 	statsInfo := ""
 	if m.uiVisibility.FooterStats {
 		statsInfo = fmt.Sprintf("%s | %s | %s",
-			styles.Faint.Render(fmt.Sprintf("%dx%d", m.numRows(), m.numCols())),
+			styles.Faint.Render(fmt.Sprintf("%sx%d", m.rowCountLabel(), m.numCols())),
 			styles.Faint.Render(fmt.Sprintf("In %.2fs", m.elapsed.Seconds())),
 			styles.Faint.Render(fmt.Sprintf("[%d/%d]", m.selectedRow+1, m.selectedCol+1)),
 		)
+		if m.filterActive() {
+			statsInfo += styles.Faint.Render(" | ") +
+				styles.TableHeader.Render(fmt.Sprintf("filter %s: %s", m.filterScope(m.filterCol), m.filterExpr))
+		}
 	}
 
 	// Build keymaps info (conditional)
@@ -195,6 +219,7 @@ func (m Model) renderFooter() string {
 	if m.uiVisibility.FooterKeymaps {
 		updateInfo := ""
 		delInfo := ""
+		insertInfo := ""
 		enterInfo := ""
 
 		if m.isTablesList {
@@ -206,13 +231,14 @@ func (m Model) renderFooter() string {
 			)
 			updateInfo = ""
 			delInfo = ""
-		} else if m.tableName != "" && m.primaryKeyCol != "" {
+		} else if m.tableName != "" && m.hasPrimaryKey() {
 			updateInfo = styles.TableHeader.Render(
 				"u",
 			) + styles.Faint.Render(
 				"pdate",
 			)
 			delInfo = styles.TableHeader.Render("D") + styles.Faint.Render("el")
+			insertInfo = styles.TableHeader.Render("i") + styles.Faint.Render("nsert")
 		} else if m.tableName != "" {
 			updateInfo = styles.TableHeader.Render(
 				"u",
@@ -220,6 +246,7 @@ func (m Model) renderFooter() string {
 				"pdate (no PK)",
 			)
 			delInfo = ""
+			insertInfo = styles.TableHeader.Render("i") + styles.Faint.Render("nsert")
 		} else {
 			// No table name means JOIN or complex query
 			updateInfo = styles.Faint.Render("(update/delete disabled)")
@@ -230,6 +257,8 @@ func (m Model) renderFooter() string {
 		edit := styles.TableHeader.Render("e") + styles.Faint.Render("ditSQL")
 		save := styles.TableHeader.Render("s") + styles.Faint.Render("ave")
 		yank := styles.TableHeader.Render("y") + styles.Faint.Render("ank")
+		search := styles.TableHeader.Render("/") + styles.Faint.Render("search")
+		filter := styles.TableHeader.Render("f") + styles.Faint.Render("ilter")
 		exportKey := styles.Faint.Render("e") + styles.TableHeader.Render("x") + styles.Faint.Render("port")
 		quit := styles.TableHeader.Render("q") + styles.Faint.Render("uit")
 		hjkl := styles.TableHeader.Render("hjkl") + styles.Faint.Render("←↓↑→")
@@ -244,7 +273,9 @@ func (m Model) renderFooter() string {
 				hjkl,
 			)
 		} else if m.visualMode {
-			keymapsInfo = fmt.Sprintf("  %s  %s  %s  %s  %s  %s  %s",
+			keymapsInfo = fmt.Sprintf("  %s  %s  %s  %s  %s  %s  %s  %s  %s",
+				updateInfo,
+				delInfo,
 				yank,
 				exportKey,
 				sel,
@@ -254,10 +285,13 @@ func (m Model) renderFooter() string {
 				hjkl,
 			)
 		} else {
-			keymapsInfo = fmt.Sprintf("  %s  %s  %s  %s  %s  %s  %s  %s  %s",
+			keymapsInfo = fmt.Sprintf("  %s  %s  %s  %s  %s  %s  %s  %s  %s  %s  %s  %s",
 				updateInfo,
 				delInfo,
+				insertInfo,
 				yank,
+				search,
+				filter,
 				sel,
 				edit,
 				save,
@@ -268,6 +302,14 @@ func (m Model) renderFooter() string {
 		}
 	}
 
+	// Pending edits show even with the stats hidden
+	if pending := m.pendingLabel(); pending != "" {
+		if statsInfo != "" {
+			statsInfo += styles.Faint.Render(" | ")
+		}
+		statsInfo += pending
+	}
+
 	// Assemble footer from conditional parts
 	return fmt.Sprintf("\n%s%s%s", cellPreview, statsInfo, keymapsInfo)
 }
@@ -288,9 +330,41 @@ func (m Model) getCellStyle(row, col int) lipgloss.Style {
 		return styles.TableSelected
 	}
 
+	if m.data[row][col].Null {
+		return styles.TableNull
+	}
 	return styles.TableCell
 }
 
+// renderQueryTitle is the query name followed by its folder, tags and
+// description, cut to fit on one line
+func (m Model) renderQueryTitle() string {
+	title := "◆ " + m.currentQuery.Name
+
+	var labels []string
+	if m.currentQuery.Folder != "" {
+		labels = append(labels, m.currentQuery.Folder+"/")
+	}
+	for _, tag := range m.currentQuery.Tags {
+		labels = append(labels, "#"+tag)
+	}
+	if m.currentQuery.Description != "" {
+		labels = append(labels, "— "+m.currentQuery.Description)
+	}
+	if len(labels) == 0 {
+		return styles.Title.Render(title)
+	}
+
+	details := strings.Join(labels, " ")
+	if room := m.width - len([]rune(title)) - 1; room < len([]rune(details)) {
+		if room < 2 {
+			return styles.Title.Render(title)
+		}
+		details = formatCell(details, room)
+	}
+	return styles.Title.Render(title) + " " + styles.Faint.Render(details)
+}
+
 func formatCell(content string, cellWidth int) string {
 	runes := []rune(content)
 	runeCount := len(runes)
@@ -428,7 +502,7 @@ func (m Model) renderDetailView() string {
 	b.WriteString(styles.Faint.Render(posInfo))
 
 	// Show if editing/updating is enabled
-	if m.tableName != "" && m.primaryKeyCol != "" {
+	if m.tableName != "" && m.hasPrimaryKey() {
 		b.WriteString(" ")
 		b.WriteString(styles.Faint.Render("• Press 'e' to edit"))
 	}
@@ -502,7 +576,7 @@ func (m Model) renderDetailView() string {
 	hjkl := styles.TableHeader.Render("kj↑↓") + styles.Faint.Render(" scroll")
 
 	edit := ""
-	if m.tableName != "" && m.primaryKeyCol != "" {
+	if m.tableName != "" && m.hasPrimaryKey() {
 		edit = styles.TableHeader.Render("e") + styles.Faint.Render(" edit")
 	}
 
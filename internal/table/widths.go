@@ -0,0 +1,181 @@
+package table
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/eduardofuncao/squix/internal/styles"
+)
+
+const (
+	minColumnWidth = 3
+	maxColumnWidth = 200
+
+	// Rows measured when fitting a column to its content
+	widthSampleRows = 200
+
+	// Query.Metadata key holding the widths set with + and -, as "id=4,name=30"
+	columnWidthsKey = "column_widths"
+)
+
+// fitColumnWidth measures the header and a sample of the loaded cells,
+// capped at the configured column width
+func (m Model) fitColumnWidth(col int) int {
+	width := len([]rune(m.headerLabel(col)))
+	for i := 0; i < len(m.data) && i < widthSampleRows; i++ {
+		if col < len(m.data[i]) {
+			width = max(width, len([]rune(m.data[i][col].String())))
+		}
+	}
+	return min(max(width, minColumnWidth), max(m.cellWidth, minColumnWidth))
+}
+
+// initColumnWidths fits every column, then applies the widths saved with the
+// query
+func (m Model) initColumnWidths() Model {
+	m.colWidths = make([]int, m.numCols())
+	for j := range m.colWidths {
+		m.colWidths[j] = m.fitColumnWidth(j)
+	}
+
+	m.customWidths = parseColumnWidths(m.currentQuery.Metadata[columnWidthsKey])
+	for j, col := range m.columns {
+		if width, ok := m.customWidths[col]; ok {
+			m.colWidths[j] = width
+		}
+	}
+	return m
+}
+
+func (m Model) colWidth(col int) int {
+	if col < len(m.colWidths) {
+		return m.colWidths[col]
+	}
+	return m.cellWidth
+}
+
+// resizeColumn widens or narrows the selected column
+func (m Model) resizeColumn(delta int) (Model, tea.Cmd) {
+	if m.numCols() == 0 {
+		return m, nil
+	}
+
+	width := min(max(m.colWidth(m.selectedCol)+delta, minColumnWidth), maxColumnWidth)
+	m.colWidths[m.selectedCol] = width
+	m.customWidths = copyWidths(m.customWidths)
+	m.customWidths[m.columns[m.selectedCol]] = width
+
+	m = m.ensureColVisible()
+	m.statusMessage = styles.Faint.Render(fmt.Sprintf("%s: %d columns wide, s to save with the query", m.columns[m.selectedCol], width))
+	return m, nil
+}
+
+// autoFitColumn fits the selected column to its content again, dropping any
+// width set by hand
+func (m Model) autoFitColumn() (Model, tea.Cmd) {
+	if m.numCols() == 0 {
+		return m, nil
+	}
+
+	m.colWidths[m.selectedCol] = m.fitColumnWidth(m.selectedCol)
+	if _, ok := m.customWidths[m.columns[m.selectedCol]]; ok {
+		m.customWidths = copyWidths(m.customWidths)
+		delete(m.customWidths, m.columns[m.selectedCol])
+	}
+
+	m = m.ensureColVisible()
+	return m, nil
+}
+
+// colsFitting counts the columns that fit on screen starting at offset,
+// always at least one
+func (m Model) colsFitting(offset int) int {
+	available := m.width - 2
+	used, count := 0, 0
+	for j := offset; j < m.numCols(); j++ {
+		width := m.colWidth(j)
+		if count > 0 {
+			width++ // Border
+		}
+		if count > 0 && used+width > available {
+			break
+		}
+		used += width
+		count++
+	}
+	return count
+}
+
+// ensureColVisible scrolls horizontally just enough to show the selected
+// column, then recomputes how many columns fit
+func (m Model) ensureColVisible() Model {
+	if m.selectedCol < m.offsetX {
+		m.offsetX = m.selectedCol
+	}
+	for m.offsetX < m.selectedCol && m.offsetX+m.colsFitting(m.offsetX) <= m.selectedCol {
+		m.offsetX++
+	}
+	m.visibleCols = m.colsFitting(m.offsetX)
+	return m
+}
+
+// queryMetadata is the query's metadata with the current custom widths, for
+// saving
+func (m Model) queryMetadata() map[string]string {
+	metadata := map[string]string{}
+	for k, v := range m.currentQuery.Metadata {
+		metadata[k] = v
+	}
+
+	delete(metadata, columnWidthsKey)
+	if encoded := formatColumnWidths(m.customWidths); encoded != "" {
+		metadata[columnWidthsKey] = encoded
+	}
+
+	if len(metadata) == 0 {
+		return nil
+	}
+	return metadata
+}
+
+func parseColumnWidths(value string) map[string]int {
+	widths := map[string]int{}
+	for _, pair := range strings.Split(value, ",") {
+		name, width, ok := strings.Cut(pair, "=")
+		if !ok {
+			continue
+		}
+		n, err := strconv.Atoi(strings.TrimSpace(width))
+		if err != nil || n < minColumnWidth {
+			continue
+		}
+		widths[strings.TrimSpace(name)] = min(n, maxColumnWidth)
+	}
+	return widths
+}
+
+func formatColumnWidths(widths map[string]int) string {
+	names := make([]string, 0, len(widths))
+	for name := range widths {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	pairs := make([]string, 0, len(names))
+	for _, name := range names {
+		pairs = append(pairs, fmt.Sprintf("%s=%d", name, widths[name]))
+	}
+	return strings.Join(pairs, ",")
+}
+
+// copyWidths returns a writable copy of widths, which may be nil
+func copyWidths(widths map[string]int) map[string]int {
+	copied := make(map[string]int, len(widths)+1)
+	for k, v := range widths {
+		copied[k] = v
+	}
+	return copied
+}
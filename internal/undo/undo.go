@@ -0,0 +1,357 @@
+package undo
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/eduardofuncao/squix/internal/config"
+	"github.com/eduardofuncao/squix/internal/db"
+)
+
+var JournalFile = filepath.Join(config.CfgPath, "undo.jsonl")
+
+// MaxEntries is how many changes the journal keeps, older ones are dropped
+const MaxEntries = 200
+
+// Changes that can be undone
+const (
+	ActionUpdate = "update"
+	ActionDelete = "delete"
+)
+
+// Entry is an UPDATE or DELETE made from the table view, with every row it
+// touched as it was before. Changed is the columns an UPDATE set. Computed
+// and Identity come from the table metadata, for the columns that can't
+// simply be written back. Stored as one JSON line
+type Entry struct {
+	Time        time.Time   `json:"time"`
+	Connection  string      `json:"connection"`
+	DBType      string      `json:"db_type"`
+	Table       string      `json:"table"`
+	Action      string      `json:"action"`
+	SQL         string      `json:"sql"`
+	PrimaryKeys []string    `json:"primary_keys"`
+	Columns     []string    `json:"columns"`
+	ColumnTypes []string    `json:"column_types,omitempty"`
+	Rows        [][]db.Cell `json:"rows"`
+	Changed     []string    `json:"changed,omitempty"`
+	Computed    []string    `json:"computed,omitempty"`
+	Identity    []string    `json:"identity,omitempty"`
+	Undone      bool        `json:"undone,omitempty"`
+}
+
+// Load reads the journal, oldest first. A missing file is an empty journal
+func Load() ([]Entry, error) {
+	file, err := os.Open(JournalFile)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	defer file.Close()
+
+	var entries []Entry
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		line := scanner.Bytes()
+		if len(line) == 0 {
+			continue
+		}
+		var entry Entry
+		// Skip lines that were cut short, e.g. by a crash while writing
+		if err := json.Unmarshal(line, &entry); err != nil {
+			continue
+		}
+		entries = append(entries, entry)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("could not read undo journal: %w", err)
+	}
+	return entries, nil
+}
+
+// Append records changes, keeping only the newest MaxEntries
+func Append(added ...Entry) error {
+	if len(added) == 0 {
+		return nil
+	}
+	entries, err := Load()
+	if err != nil {
+		return err
+	}
+	entries = append(entries, added...)
+	if len(entries) > MaxEntries {
+		entries = entries[len(entries)-MaxEntries:]
+	}
+	return save(entries)
+}
+
+// Last returns the newest change on a connection that wasn't undone yet
+func Last(connection string) (Entry, bool, error) {
+	entries, err := Load()
+	if err != nil {
+		return Entry{}, false, err
+	}
+	for i := len(entries) - 1; i >= 0; i-- {
+		if entries[i].Connection == connection && !entries[i].Undone {
+			return entries[i], true, nil
+		}
+	}
+	return Entry{}, false, nil
+}
+
+// MarkUndone flags a change as undone so the next undo goes further back
+func MarkUndone(undone Entry) error {
+	entries, err := Load()
+	if err != nil {
+		return err
+	}
+	for i, entry := range entries {
+		if entry.Time.Equal(undone.Time) && entry.Connection == undone.Connection && entry.SQL == undone.SQL {
+			entries[i].Undone = true
+		}
+	}
+	return save(entries)
+}
+
+func save(entries []Entry) error {
+	if err := os.MkdirAll(filepath.Dir(JournalFile), 0755); err != nil {
+		return fmt.Errorf("failed to create config directory: %w", err)
+	}
+
+	var buf strings.Builder
+	for _, entry := range entries {
+		line, err := json.Marshal(entry)
+		if err != nil {
+			return err
+		}
+		buf.Write(line)
+		buf.WriteString("\n")
+	}
+
+	// Write to a temp file first so a crash can't leave a truncated journal
+	tmp := JournalFile + ".tmp"
+	if err := os.WriteFile(tmp, []byte(buf.String()), 0600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, JournalFile)
+}
+
+// Statements builds what reverts the change: the deleted rows inserted
+// again, or the updated rows set back to their old values by primary key.
+// Every column of a composite key goes in the WHERE clause
+func (e Entry) Statements() []string {
+	var statements []string
+	table := db.QuoteTableName(e.Table, e.DBType)
+	columns, override := e.insertColumns()
+	for _, row := range e.Rows {
+		switch e.Action {
+		case ActionDelete:
+			names := make([]string, len(columns))
+			values := make([]string, len(columns))
+			for i, col := range columns {
+				names[i] = db.QuoteIdentifier(e.Columns[col], e.DBType)
+				values[i] = e.literal(row, col)
+			}
+			statements = append(statements, fmt.Sprintf("INSERT INTO %s (%s)%s\nVALUES (%s);",
+				table, strings.Join(names, ", "), override, strings.Join(values, ", ")))
+
+		case ActionUpdate:
+			var assignments, conditions []string
+			for i, col := range e.Columns {
+				condition := fmt.Sprintf("%s = %s", db.QuoteIdentifier(col, e.DBType), e.literal(row, i))
+				if containsFold(e.PrimaryKeys, col) {
+					conditions = append(conditions, condition)
+					continue
+				}
+				if e.restored(col) {
+					assignments = append(assignments, condition)
+				}
+			}
+			if len(assignments) == 0 {
+				continue
+			}
+			where := strings.Join(conditions, " AND ")
+			// ClickHouse mutations have their own syntax
+			if db.CanonicalDBType(e.DBType) == "clickhouse" {
+				statements = append(statements, fmt.Sprintf("ALTER TABLE %s\nUPDATE %s\nWHERE %s;",
+					table, strings.Join(assignments, ", "), where))
+				continue
+			}
+			statements = append(statements, fmt.Sprintf("UPDATE %s\nSET %s\nWHERE %s;",
+				table, strings.Join(assignments, ", "), where))
+		}
+	}
+
+	// SQL Server takes identity values only while IDENTITY_INSERT is on
+	if e.Action == ActionDelete && len(statements) > 0 && e.identityOverridden() &&
+		db.CanonicalDBType(e.DBType) == "sqlserver" {
+		statements = append([]string{fmt.Sprintf("SET IDENTITY_INSERT %s ON;", table)}, statements...)
+		statements = append(statements, fmt.Sprintf("SET IDENTITY_INSERT %s OFF;", table))
+	}
+	return statements
+}
+
+// literal writes the value of column i in row after the type of the column
+func (e Entry) literal(row []db.Cell, i int) string {
+	columnType := ""
+	if i < len(e.ColumnTypes) {
+		columnType = e.ColumnTypes[i]
+	}
+	return db.TypedLiteral(row[i], columnType, e.DBType)
+}
+
+// restored tells if undoing an update sets col back: only the columns the
+// UPDATE changed, so later changes to the others are kept. Entries from
+// before Changed was recorded put back every column
+func (e Entry) restored(col string) bool {
+	if containsFold(e.Computed, col) {
+		return false
+	}
+	return len(e.Changed) == 0 || containsFold(e.Changed, col)
+}
+
+// insertColumns is the position of every column the deleted rows go back
+// into, and what goes between the column list and VALUES. Computed columns
+// are left out. Identity columns keep their values where the database has
+// a way to override them, otherwise they are left out too and the rows get
+// new ones
+func (e Entry) insertColumns() ([]int, string) {
+	override := ""
+	if e.identityOverridden() {
+		switch db.CanonicalDBType(e.DBType) {
+		case "postgres", "firebird":
+			override = "\nOVERRIDING SYSTEM VALUE"
+		}
+	}
+
+	var columns []int
+	for i, col := range e.Columns {
+		if containsFold(e.Computed, col) {
+			continue
+		}
+		if containsFold(e.Identity, col) && !e.identityOverridden() {
+			continue
+		}
+		columns = append(columns, i)
+	}
+	return columns, override
+}
+
+// identityOverridden tells if the identity columns of the table can be
+// given the old values back
+func (e Entry) identityOverridden() bool {
+	if !slices.ContainsFunc(e.Columns, func(col string) bool { return containsFold(e.Identity, col) }) {
+		return false
+	}
+	switch db.CanonicalDBType(e.DBType) {
+	case "postgres", "firebird", "sqlserver":
+		return true
+	}
+	return false
+}
+
+// lostIdentity is the identity columns a DELETE undo can't give back
+func (e Entry) lostIdentity() []string {
+	if e.Action != ActionDelete || e.identityOverridden() {
+		return nil
+	}
+	var lost []string
+	for _, col := range e.Columns {
+		if containsFold(e.Identity, col) {
+			lost = append(lost, col)
+		}
+	}
+	return lost
+}
+
+func containsFold(list []string, s string) bool {
+	return slices.ContainsFunc(list, func(item string) bool { return strings.EqualFold(item, s) })
+}
+
+// Preview is the statements reverting the change, after comments telling
+// what they revert, for the user to review in the editor
+func (e Entry) Preview() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "-- Undo the %s on %s made %s\n", e.Action, e.Table, e.Time.Format("2006-01-02 15:04:05"))
+	for _, line := range strings.Split(e.SQL, "\n") {
+		b.WriteString("--   " + line + "\n")
+	}
+	if lost := e.lostIdentity(); len(lost) > 0 {
+		fmt.Fprintf(&b, "-- %s can't be given back on %s, the rows get new values\n",
+			strings.Join(lost, ", "), e.DBType)
+	}
+	b.WriteString("\n")
+	b.WriteString(strings.Join(e.Statements(), "\n\n"))
+	b.WriteString("\n")
+	return b.String()
+}
+
+// ErrNothingToRun is returned by Run when the statements were all removed
+var ErrNothingToRun = errors.New("no statements to run")
+
+// Bind turns the literals of a statement reverting the change into
+// arguments typed after the columns of the table, like the edits made from
+// the table view
+func (e Entry) Bind(statement string, placeholder func(index int) string) (string, []any) {
+	columnTypes := map[string]string{}
+	for i, col := range e.Columns {
+		if i < len(e.ColumnTypes) {
+			columnTypes[strings.ToLower(col)] = e.ColumnTypes[i]
+		}
+	}
+	statement, args := db.BindLiterals(statement, columnTypes, placeholder)
+	return statement, db.DriverArgs(args, e.DBType)
+}
+
+// Run executes the statements of an undo in a single transaction, or one by
+// one on databases without transactions, with their literals bound
+func (e Entry) Run(conn db.DatabaseConnection, script string) error {
+	statements := db.SplitStatements(script, conn.GetDbType())
+	if len(statements) == 0 {
+		return ErrNothingToRun
+	}
+
+	ctx := context.Background()
+	tx, err := conn.BeginTx(ctx)
+	if errors.Is(err, db.ErrNoTransactions) {
+		for _, statement := range statements {
+			bound, args := e.Bind(statement, conn.GetPlaceholder)
+			if err := conn.Exec(ctx, bound, args...); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	for _, statement := range statements {
+		bound, args := e.Bind(statement, conn.GetPlaceholder)
+		if _, err := tx.ExecContext(ctx, bound, args...); err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// Summary describes the change in one line
+func (e Entry) Summary() string {
+	rows := "1 row"
+	if len(e.Rows) != 1 {
+		rows = fmt.Sprintf("%d rows", len(e.Rows))
+	}
+	return fmt.Sprintf("%s %s on %s (%s)", e.Time.Format("2006-01-02 15:04:05"), e.Action, e.Table, rows)
+}
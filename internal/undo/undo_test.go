@@ -0,0 +1,115 @@
+package undo
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/eduardofuncao/squix/internal/db"
+)
+
+func TestDeleteStatementsGeneratedColumns(t *testing.T) {
+	entry := Entry{
+		Table:    "users",
+		Action:   ActionDelete,
+		Columns:  []string{"id", "name", "name_upper"},
+		Rows:     [][]db.Cell{{db.TextCell("7"), db.TextCell("ann"), db.TextCell("ANN")}},
+		Computed: []string{"NAME_UPPER"},
+		Identity: []string{"id"},
+	}
+
+	tests := []struct {
+		dbType string
+		want   []string
+	}{
+		{
+			dbType: "postgres",
+			want:   []string{"INSERT INTO \"users\" (\"id\", \"name\")\nOVERRIDING SYSTEM VALUE\nVALUES ('7', 'ann');"},
+		},
+		{
+			dbType: "mssql",
+			want: []string{
+				"SET IDENTITY_INSERT [users] ON;",
+				"INSERT INTO [users] ([id], [name])\nVALUES ('7', 'ann');",
+				"SET IDENTITY_INSERT [users] OFF;",
+			},
+		},
+		{
+			dbType: "oracle",
+			want:   []string{"INSERT INTO \"USERS\" (\"name\")\nVALUES ('ann');"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.dbType, func(t *testing.T) {
+			entry.DBType = tt.dbType
+			if got := entry.Statements(); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Statements() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	entry.DBType = "oracle"
+	if preview := entry.Preview(); !strings.Contains(preview, "-- id can't be given back on oracle") {
+		t.Errorf("Preview() doesn't tell the identity is lost:\n%s", preview)
+	}
+
+	// Without identity columns nothing is overridden
+	entry.DBType = "postgres"
+	entry.Identity = nil
+	want := []string{"INSERT INTO \"users\" (\"id\", \"name\")\nVALUES ('7', 'ann');"}
+	if got := entry.Statements(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Statements() = %q, want %q", got, want)
+	}
+}
+
+func TestUpdateStatementsRestoreChangedColumns(t *testing.T) {
+	entry := Entry{
+		DBType:      "postgres",
+		Table:       "public.users",
+		Action:      ActionUpdate,
+		SQL:         "UPDATE users SET name = 'bob' WHERE id = 7",
+		PrimaryKeys: []string{"id"},
+		Columns:     []string{"id", "name", "age", "name_upper"},
+		ColumnTypes: []string{"INT4", "TEXT", "INT4", "TEXT"},
+		Rows:        [][]db.Cell{{db.TextCell("7"), db.TextCell("ann"), db.TextCell("30"), db.TextCell("ANN")}},
+		Changed:     []string{"name"},
+		Computed:    []string{"name_upper"},
+	}
+
+	want := []string{"UPDATE \"public\".\"users\"\nSET \"name\" = 'ann'\nWHERE \"id\" = 7;"}
+	if got := entry.Statements(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Statements() = %q, want %q", got, want)
+	}
+
+	// Entries from before Changed was recorded put back every column but
+	// the computed ones
+	entry.Changed = nil
+	want = []string{"UPDATE \"public\".\"users\"\nSET \"name\" = 'ann', \"age\" = 30\nWHERE \"id\" = 7;"}
+	if got := entry.Statements(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Statements() = %q, want %q", got, want)
+	}
+}
+
+func TestDeleteStatementsBind(t *testing.T) {
+	entry := Entry{
+		DBType:      "postgres",
+		Table:       "users",
+		Action:      ActionDelete,
+		Columns:     []string{"id", "name"},
+		ColumnTypes: []string{"INT4", "TEXT"},
+		Rows:        [][]db.Cell{{db.TextCell("7"), db.NullCell}},
+		Identity:    []string{"id"},
+	}
+
+	statements := entry.Statements()
+	got, args := entry.Bind(statements[0], func(i int) string { return fmt.Sprintf("$%d", i) })
+	want := "INSERT INTO \"users\" (\"id\", \"name\")\nOVERRIDING SYSTEM VALUE\nVALUES ($1, $2);"
+	if got != want {
+		t.Errorf("Bind() = %q, want %q", got, want)
+	}
+	if !reflect.DeepEqual(args, []any{int64(7), nil}) {
+		t.Errorf("Bind() args = %#v, want [7 <nil>]", args)
+	}
+}